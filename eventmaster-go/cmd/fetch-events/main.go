@@ -8,6 +8,7 @@ import (
 	"eventmaster-go/internal/services"
 	"flag"
 	"log"
+	_ "time/tzdata" // embed IANA zones so venue time zones resolve in minimal containers
 )
 
 func main() {
@@ -42,10 +43,31 @@ func main() {
 	if err := db.AutoMigrate(
 		&models.Role{},
 		&models.User{},
+		&models.Venue{},
+		&models.OrganizerProfile{},
 		&models.Event{},
 		&models.Participant{},
 		&models.Image{},
 		&models.Session{},
+		&models.Category{},
+		&models.Tag{},
+		&models.EventRevision{},
+		&models.EventTemplate{},
+		&models.ExportJob{},
+		&models.AgendaTrack{},
+		&models.Speaker{},
+		&models.AgendaSession{},
+		&models.PersonalAgendaItem{},
+		&models.TicketType{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.Hold{},
+		&models.CheckIn{},
+		&models.Collaborator{},
+		&models.EventSlug{},
+		&models.ShareLink{},
 	); err != nil {
 		log.Fatalf("Failed to auto-migrate database: %v", err)
 	}
@@ -55,10 +77,17 @@ func main() {
 	userRepo := repositories.NewUserRepository(db)
 	participantRepo := repositories.NewParticipantRepository(db)
 	imageRepo := repositories.NewImageRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	venueRepo := repositories.NewVenueRepository(db)
+	organizerRepo := repositories.NewOrganizerRepository(db)
+	ticketTypeRepo := repositories.NewTicketTypeRepository(db)
+	holdRepo := repositories.NewHoldRepository(db)
+	collaboratorRepo := repositories.NewCollaboratorRepository(db)
 
 	// Prepare dependencies
 	imageService := services.NewImageService(imageRepo)
-	participantService := services.NewParticipantService(participantRepo, eventRepo)
+	ticketSigner := services.NewTicketSigner(cfg.Ticket.SigningSecret)
+	participantService := services.NewParticipantService(participantRepo, eventRepo, ticketTypeRepo, holdRepo, ticketSigner, services.NewEventAccess(eventRepo, collaboratorRepo))
 	systemUserID, err := services.EnsureTicketmasterSystemUser(userRepo)
 	if err != nil {
 		log.Fatalf("Failed to ensure Ticketmaster system user: %v", err)
@@ -67,6 +96,9 @@ func main() {
 	// Initialize services
 	ticketmasterService := services.NewTicketmasterService(
 		eventRepo,
+		categoryRepo,
+		venueRepo,
+		organizerRepo,
 		imageService,
 		participantService,
 		cfg.Ticketmaster.APIKey,
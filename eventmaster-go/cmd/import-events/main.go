@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"eventmaster-go/internal/config"
+	"eventmaster-go/internal/database"
+	"eventmaster-go/internal/importer"
+	"eventmaster-go/internal/repositories"
+	"eventmaster-go/internal/server"
+	"eventmaster-go/internal/services"
+	"flag"
+	"log"
+	"os"
+	_ "time/tzdata" // embed IANA zones so row time zones resolve in minimal containers
+)
+
+func main() {
+	// Parse command line flags
+	configPath := flag.String("config", "../../.env", "path to config file")
+	filePath := flag.String("file", "", "CSV or JSON file to import")
+	formatFlag := flag.String("format", "", "file format (csv or json); detected from the extension when empty")
+	mappingSpec := flag.String("mapping", "", "column mapping, e.g. title=Name,eventDate=Start")
+	ownerEmail := flag.String("owner", "", "email of the user who will own the events (defaults to the root admin)")
+	dryRun := flag.Bool("dry-run", false, "validate and report without writing")
+	flag.Parse()
+
+	if *filePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	mapping, err := importer.ParseMapping(*mappingSpec)
+	if err != nil {
+		log.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	format, err := importer.DetectFormat(*formatFlag, *filePath, "")
+	if err != nil {
+		log.Fatalf("Failed to detect format: %v", err)
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatalf("Failed to open import file: %v", err)
+	}
+	defer file.Close()
+
+	records, err := importer.Read(file, format, mapping, 0)
+	if err != nil {
+		log.Fatalf("Failed to read import file: %v", err)
+	}
+
+	// Connect to the existing database; imports must never recreate it
+	db, err := database.Open(&database.Config{
+		Host:     cfg.DB.Host,
+		Port:     cfg.DB.Port,
+		User:     cfg.DB.Username,
+		Password: cfg.DB.Password,
+		DBName:   cfg.DB.Name,
+		SSLMode:  cfg.DB.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := database.CloseDB(db); err != nil {
+			log.Printf("Failed to close database: %v", err)
+		}
+	}()
+
+	// Initialize repositories
+	userRepo := repositories.NewUserRepository(db)
+	eventRepo := repositories.NewEventRepository(db)
+	imageRepo := repositories.NewImageRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	revisionRepo := repositories.NewEventRevisionRepository(db)
+	templateRepo := repositories.NewEventTemplateRepository(db)
+	venueRepo := repositories.NewVenueRepository(db)
+	organizerRepo := repositories.NewOrganizerRepository(db)
+
+	// Initialize services
+	eventService := services.NewEventService(eventRepo, imageRepo, categoryRepo, tagRepo, revisionRepo, templateRepo, venueRepo, organizerRepo)
+	importService := services.NewEventImportService(eventRepo, eventService)
+
+	email := *ownerEmail
+	if email == "" {
+		email = cfg.Auth.AdminEmail
+	}
+	owner, err := userRepo.FindByEmail(email)
+	if err != nil {
+		log.Fatalf("Failed to find owner %s: %v", email, err)
+	}
+
+	validator := &server.CustomValidator{Validator: server.NewValidator()}
+	report, err := importService.ImportEvents(server.BuildImportRows(records, validator), owner.ID, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to import events: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	log.Printf("Imported %d rows: %d created, %d updated, %d failed (dry run: %t)",
+		report.Total, report.Created, report.Updated, report.Failed, report.DryRun)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"time"
+	_ "time/tzdata" // embed IANA zones so event time zones resolve in minimal containers
 
 	"eventmaster-go/internal/config"
 	"eventmaster-go/internal/database"
@@ -12,6 +13,7 @@ import (
 	"eventmaster-go/internal/repositories"
 	"eventmaster-go/internal/server"
 	"eventmaster-go/internal/services"
+	"eventmaster-go/pkg/badge"
 
 	"gorm.io/gorm"
 )
@@ -47,10 +49,32 @@ func main() {
 	if err := db.AutoMigrate(
 		&models.Role{},
 		&models.User{},
+		&models.Venue{},
+		&models.OrganizerProfile{},
 		&models.Event{},
 		&models.Participant{},
 		&models.Image{},
 		&models.Session{},
+		&models.Category{},
+		&models.Tag{},
+		&models.EventRevision{},
+		&models.EventTemplate{},
+		&models.ExportJob{},
+		&models.AgendaTrack{},
+		&models.Speaker{},
+		&models.AgendaSession{},
+		&models.PersonalAgendaItem{},
+		&models.TicketType{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.Hold{},
+		&models.CheckIn{},
+		&models.Collaborator{},
+		&models.EventSlug{},
+		&models.ShareLink{},
+		&models.DataMigration{},
 	); err != nil {
 		log.Fatalf("Failed to auto-migrate database: %v", err)
 	}
@@ -65,6 +89,21 @@ func main() {
 	eventRepo := repositories.NewEventRepository(db)
 	participantRepo := repositories.NewParticipantRepository(db)
 	imageRepo := repositories.NewImageRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	revisionRepo := repositories.NewEventRevisionRepository(db)
+	templateRepo := repositories.NewEventTemplateRepository(db)
+	venueRepo := repositories.NewVenueRepository(db)
+	organizerRepo := repositories.NewOrganizerRepository(db)
+	ticketTypeRepo := repositories.NewTicketTypeRepository(db)
+	holdRepo := repositories.NewHoldRepository(db)
+	checkInRepo := repositories.NewCheckInRepository(db)
+	collaboratorRepo := repositories.NewCollaboratorRepository(db)
+	shareLinkRepo := repositories.NewShareLinkRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	promoCodeRepo := repositories.NewPromoCodeRepository(db)
+	agendaRepo := repositories.NewAgendaRepository(db)
+	exportJobRepo := repositories.NewExportJobRepository(db)
 	sessionRepo := repositories.NewSessionRepository(db)
 
 	// Initialize services
@@ -73,15 +112,67 @@ func main() {
 		sessionRepo,
 		cfg.Auth.JWTExpiration,
 	)
-	eventService := services.NewEventService(eventRepo, imageRepo)
-	participantService := services.NewParticipantService(participantRepo, eventRepo)
+	eventAccess := services.NewEventAccess(eventRepo, collaboratorRepo)
+	eventService := services.NewEventService(eventRepo, imageRepo, categoryRepo, tagRepo, revisionRepo, templateRepo, venueRepo, organizerRepo)
+	templateService := services.NewEventTemplateService(templateRepo, eventRepo, categoryRepo, imageRepo, venueRepo, eventAccess)
+	importService := services.NewEventImportService(eventRepo, eventService)
+	ticketSigner := services.NewTicketSigner(cfg.Ticket.SigningSecret)
+	if cfg.Ticket.FontFile != "" {
+		if err := badge.UseFontFiles(cfg.Ticket.FontFile, cfg.Ticket.BoldFontFile); err != nil {
+			log.Fatalf("Failed to load badge font: %v", err)
+		}
+	}
+	participantService := services.NewParticipantService(participantRepo, eventRepo, ticketTypeRepo, holdRepo, ticketSigner, eventAccess)
 	imageService := services.NewImageService(imageRepo)
+	categoryService := services.NewCategoryService(categoryRepo, tagRepo)
+	venueService := services.NewVenueService(venueRepo)
+	organizerService := services.NewOrganizerService(organizerRepo, eventRepo, userRepo, imageRepo)
+	agendaService := services.NewAgendaService(agendaRepo, eventRepo, participantRepo, imageRepo, eventAccess)
+	ticketTypeService := services.NewTicketTypeService(ticketTypeRepo, eventRepo, eventAccess)
+	if cfg.Payment.Provider != "fake" {
+		log.Fatalf("Unsupported payment provider: %q", cfg.Payment.Provider)
+	}
+	fakePaymentProvider := services.NewFakePaymentProvider(cfg.Payment.WebhookSecret, cfg.Payment.WebhookURL, cfg.Payment.FakeDelay)
+	promoCodeService := services.NewPromoCodeService(promoCodeRepo, eventRepo, ticketTypeRepo, eventAccess)
+	orderService := services.NewOrderService(orderRepo, eventRepo, ticketTypeRepo, promoCodeRepo, fakePaymentProvider, eventAccess)
+	holdService := services.NewHoldService(holdRepo, eventRepo, ticketTypeRepo, orderRepo, cfg.Hold.TTL)
+	ticketService := services.NewTicketService(participantRepo, eventRepo, ticketTypeRepo, orderRepo, checkInRepo, ticketSigner, eventAccess)
+	badgeService := services.NewBadgeService(ticketService, participantRepo, eventRepo, ticketTypeRepo, ticketSigner, eventAccess)
+	trashService := services.NewTrashService(eventRepo, participantRepo, cfg.Trash.Retention, eventAccess)
+	exportService := services.NewExportService(eventRepo, participantRepo, exportJobRepo, cfg.Export.Dir, cfg.Export.Retention, eventAccess)
+	collaboratorService := services.NewCollaboratorService(collaboratorRepo, eventRepo, userRepo, eventAccess, services.NewLogMailer(), cfg.Server.PublicURL+"/invitations/")
+	shareLinkService := services.NewShareLinkService(shareLinkRepo, eventRepo, eventAccess)
+	relatedEventService := services.NewRelatedEventService(eventRepo, eventAccess, cfg.Related.CacheTTL)
+	linked, err := organizerService.MigrateOrganizerStrings()
+	if err != nil {
+		log.Fatalf("Failed to migrate organizers to profiles: %v", err)
+	}
+	if linked > 0 {
+		log.Printf("Linked %d events to organizer profiles", linked)
+	}
+	migrated, err := eventService.MigrateHTMLDescriptions()
+	if err != nil {
+		log.Fatalf("Failed to migrate event descriptions to Markdown: %v", err)
+	}
+	if migrated > 0 {
+		log.Printf("Converted %d event descriptions to Markdown", migrated)
+	}
+	slugged, err := eventService.MigrateSlugs()
+	if err != nil {
+		log.Fatalf("Failed to give events slugs: %v", err)
+	}
+	if slugged > 0 {
+		log.Printf("Gave %d events slugs", slugged)
+	}
 	systemUserID, err := services.EnsureTicketmasterSystemUser(userRepo)
 	if err != nil {
 		log.Fatalf("Failed to ensure Ticketmaster system user: %v", err)
 	}
 	ticketmasterService := services.NewTicketmasterService(
 		eventRepo,
+		categoryRepo,
+		venueRepo,
+		organizerRepo,
 		imageService,
 		participantService,
 		cfg.Ticketmaster.APIKey,
@@ -91,6 +182,9 @@ func main() {
 	schedulerCtx, schedulerCancel := context.WithCancel(context.Background())
 	defer schedulerCancel()
 	ticketmasterService.StartScheduler(schedulerCtx, 6*time.Hour)
+	trashService.StartRetentionJob(schedulerCtx, time.Hour)
+	exportService.StartCleanupJob(schedulerCtx, 15*time.Minute)
+	holdService.StartReaper(schedulerCtx, cfg.Hold.ReapInterval)
 
 	go func() {
 		const initialFetchDelay = 5 * time.Second
@@ -119,14 +213,33 @@ func main() {
 	serverConfig := &server.Config{
 		Port:              serverPort,
 		SessionCookieName: cfg.Server.SessionCookieName,
+		PublicURL:         cfg.Server.PublicURL,
+		RequireIfMatch:    cfg.Server.RequireIfMatch,
 	}
 
-	srv := server.NewServer(authService, *serverConfig)
+	srv := server.NewServer(authService, eventAccess, *serverConfig)
 
 	// Register handlers
 	srv.RegisterEventHandlers(eventService)
 	srv.RegisterParticipantHandlers(participantService)
 	srv.RegisterFileHandlers(fileService)
+	srv.RegisterCategoryHandlers(categoryService)
+	srv.RegisterTrashHandlers(trashService)
+	srv.RegisterTemplateHandlers(templateService)
+	srv.RegisterImportHandlers(importService)
+	srv.RegisterExportHandlers(exportService)
+	srv.RegisterVenueHandlers(venueService)
+	srv.RegisterOrganizerHandlers(organizerService)
+	srv.RegisterAgendaHandlers(agendaService)
+	srv.RegisterTicketTypeHandlers(ticketTypeService)
+	srv.RegisterOrderHandlers(orderService, fakePaymentProvider)
+	srv.RegisterPromoCodeHandlers(promoCodeService)
+	srv.RegisterHoldHandlers(holdService)
+	srv.RegisterTicketHandlers(ticketService)
+	srv.RegisterBadgeHandlers(badgeService)
+	srv.RegisterCollaboratorHandlers(collaboratorService)
+	srv.RegisterShareLinkHandlers(shareLinkService)
+	srv.RegisterRelatedEventHandlers(relatedEventService)
 
 	log.Printf("Server starting on :%s\n", serverPort)
 	if err := srv.Start(); err != nil {
@@ -1,11 +1,23 @@
 package e2e
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"eventmaster-go/internal/models"
+
 	"github.com/google/uuid"
 )
 
@@ -119,6 +131,2709 @@ func TestEventEndpoints(t *testing.T) {
 		}
 	})
 
+	runSubtest(t, "event ics export", func(t *testing.T) {
+		id := requireEventID(t)
+		resp := doRequest(t, http.MethodGet, fmt.Sprintf("/events/%s/ics", id), nil, nil)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/calendar") {
+			t.Fatalf("expected text/calendar content type, got %s", resp.Header.Get("Content-Type"))
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), "UID:"+id+"@") {
+			t.Fatalf("expected stable UID for event %s", id)
+		}
+	})
+
+	runSubtest(t, "events ics feed", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/events.ics?limit=5", nil, nil)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.HasPrefix(string(body), "BEGIN:VCALENDAR\r\n") {
+			t.Fatalf("expected calendar document")
+		}
+		if strings.Count(string(body), "BEGIN:VEVENT") > 5 {
+			t.Fatalf("expected at most 5 events in feed")
+		}
+	})
+
+	runSubtest(t, "create event with time zone", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Zoned Event",
+			"latitude":  40.7,
+			"longitude": -74.0,
+			"eventDate": "2030-07-01T23:30:00Z",
+			"timeZone":  "America/New_York",
+		})
+
+		if event.TimeZone != "America/New_York" {
+			t.Fatalf("expected time zone America/New_York, got %s", event.TimeZone)
+		}
+		if event.EventDateLocal != "2030-07-01T19:30:00-04:00" {
+			t.Fatalf("expected local wall-clock time, got %s", event.EventDateLocal)
+		}
+	})
+
+	runSubtest(t, "invalid time zone filter", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/events?from=2030-01-01&tz=Mars/Olympus", nil, nil)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "event lifecycle", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Lifecycle Event",
+			"latitude":  51.5,
+			"longitude": -0.1,
+			"eventDate": time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+			"status":    "draft",
+		})
+
+		if event.Status != "draft" {
+			t.Fatalf("expected new event to be a draft, got %s", event.Status)
+		}
+
+		live := createEvent(t, cookie, map[string]any{
+			"title":     "Lifecycle Event Without Status",
+			"latitude":  51.5,
+			"longitude": -0.1,
+			"eventDate": time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		})
+		if live.Status != "published" {
+			t.Fatalf("expected an event created without status to be published, got %s", live.Status)
+		}
+
+		resetCookies(t)
+		resp := doRequest(t, http.MethodGet, fmt.Sprintf("/events/%s", event.ID), nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected draft to be hidden, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/publish", event.ID), nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected publish to succeed, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/cancel", event.ID), map[string]string{}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected cancel without reason to fail, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/cancel", event.ID), map[string]string{"reason": "Venue unavailable"}, headers)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected cancel to succeed, got %d", resp.StatusCode)
+		}
+
+		var cancelled EventResponse
+		decodeJSON(t, resp.Body, &cancelled)
+		if cancelled.Status != "cancelled" || cancelled.StatusReason != "Venue unavailable" {
+			t.Fatalf("expected cancelled event with reason, got %s %q", cancelled.Status, cancelled.StatusReason)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/publish", event.ID), nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected publishing a cancelled event to conflict, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "filter events by tag", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		tag := "e2e-" + uuid.NewString()[:8]
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Tagged Event",
+			"latitude":  48.85,
+			"longitude": 2.35,
+			"eventDate": time.Now().Add(72 * time.Hour).Format(time.RFC3339),
+			"status":    "published",
+			"tags":      []string{tag},
+		})
+
+		if len(event.Tags) != 1 || event.Tags[0] != tag {
+			t.Fatalf("expected event to carry tag %s, got %v", tag, event.Tags)
+		}
+
+		resp := doRequest(t, http.MethodGet, "/events?tag="+tag, nil, nil)
+		defer resp.Body.Close()
+
+		var list EventListResponse
+		decodeJSON(t, resp.Body, &list)
+		if list.TotalCount != 1 || list.Events[0].ID != event.ID {
+			t.Fatalf("expected only the tagged event, got %d events", list.TotalCount)
+		}
+	})
+
+	runSubtest(t, "filter and sort with query language", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		tag := "query-" + uuid.NewString()[:8]
+		for _, payload := range []map[string]any{
+			{"title": "Alpha Query", "eventDate": "2032-01-10T10:00:00Z"},
+			{"title": "Beta Query", "eventDate": "2032-03-10T10:00:00Z"},
+			{"title": "Gamma Query", "eventDate": "2032-05-10T10:00:00Z"},
+		} {
+			payload["latitude"] = 45.0
+			payload["longitude"] = 9.0
+			payload["status"] = "published"
+			payload["tags"] = []string{tag}
+			createEvent(t, cookie, payload)
+		}
+
+		path := "/events?tag=" + tag + "&filter[eventDate][gte]=2032-02-01&sort=-eventDate,title"
+		resp := doRequest(t, http.MethodGet, path, nil, nil)
+		var list EventListResponse
+		decodeJSON(t, resp.Body, &list)
+		resp.Body.Close()
+		if list.TotalCount != 2 || list.Events[0].Title != "Gamma Query" || list.Events[1].Title != "Beta Query" {
+			t.Fatalf("expected Gamma then Beta, got %+v", list.Events)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events?tag="+tag+"&filter[title][like]=alp", nil, nil)
+		decodeJSON(t, resp.Body, &list)
+		resp.Body.Close()
+		if list.TotalCount != 1 || list.Events[0].Title != "Alpha Query" {
+			t.Fatalf("expected only Alpha, got %+v", list.Events)
+		}
+
+		for _, bad := range []string{
+			"/events?filter[password][eq]=x",
+			"/events?filter[title][gte]=x",
+			"/events?filter[eventDate][gte]=yesterday",
+			"/events?sort=userId",
+			"/events?sortBy=id;DROP%20TABLE%20events",
+			"/participant/event/" + uuid.NewString() + "?filter[secret]=1",
+		} {
+			resp = doRequest(t, http.MethodGet, bad, nil, map[string]string{"Cookie": cookie})
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected %s to be rejected, got %d", bad, resp.StatusCode)
+			}
+		}
+	})
+
+	runSubtest(t, "venues link to events", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		city := "Venueville-" + uuid.NewString()[:8]
+
+		resp := doRequest(t, http.MethodPost, "/venues", map[string]any{
+			"name":      "Grand Hall",
+			"address":   "1 Main Street",
+			"city":      city,
+			"country":   "Portugal",
+			"latitude":  38.72,
+			"longitude": -9.14,
+			"timeZone":  "Europe/Lisbon",
+			"capacity":  1200,
+		}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected venue to be created, got %d", resp.StatusCode)
+		}
+		var venue struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &venue)
+		resp.Body.Close()
+
+		resp = doRequest(t, http.MethodGet, "/venues?q=grand&city="+city, nil, nil)
+		var found []struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &found)
+		resp.Body.Close()
+		if len(found) != 1 || found[0].ID != venue.ID {
+			t.Fatalf("expected picker search to find the venue, got %+v", found)
+		}
+
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Venue Event",
+			"venueId":   venue.ID,
+			"eventDate": "2031-09-01T18:00:00Z",
+			"status":    "published",
+		})
+		if event.VenueID != venue.ID || event.Location != "Grand Hall, "+city+", Portugal" ||
+			event.TimeZone != "Europe/Lisbon" || event.Latitude != 38.72 {
+			t.Fatalf("expected the venue location to be copied, got %+v", event)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events?city="+strings.ToLower(city), nil, nil)
+		var list EventListResponse
+		decodeJSON(t, resp.Body, &list)
+		resp.Body.Close()
+		if list.TotalCount != 1 || list.Events[0].ID != event.ID {
+			t.Fatalf("expected city filter to return the event, got %d events", list.TotalCount)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodPut, "/venues/"+venue.ID, map[string]any{"name": "Hijacked"}, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID, nil, nil)
+		resp.Body.Close()
+		etag := resp.Header.Get("ETag")
+		resp = doRequest(t, http.MethodPut, "/venues/"+venue.ID, map[string]any{"name": "Grand Hall East"}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the creator to update the venue, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID, nil, map[string]string{"If-None-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+			t.Fatalf("expected a venue edit to change the event ETag, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodDelete, "/venues/"+venue.ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected deleting a linked venue to conflict, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "organizer profiles and public page", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+
+		resp := doRequest(t, http.MethodPost, "/organizers", map[string]any{
+			"name":         "Harbour Collective",
+			"bio":          "Concerts by the water",
+			"contactEmail": "hello@harbour.example",
+			"links":        []map[string]string{{"label": "Instagram", "url": "https://instagram.com/harbour"}},
+		}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected organizer to be created, got %d", resp.StatusCode)
+		}
+		var organizer struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &organizer)
+		resp.Body.Close()
+
+		upcoming := createEvent(t, cookie, map[string]any{
+			"title":       "Harbour Nights",
+			"organizerId": organizer.ID,
+			"latitude":    41.15,
+			"longitude":   -8.61,
+			"eventDate":   time.Now().Add(72 * time.Hour).Format(time.RFC3339),
+			"status":      "published",
+		})
+		if upcoming.OrganizerID != organizer.ID || upcoming.Organizer != "Harbour Collective" {
+			t.Fatalf("expected the event to reference the profile, got %+v", upcoming)
+		}
+		past := createEvent(t, cookie, map[string]any{
+			"title":       "Harbour Opening",
+			"organizerId": organizer.ID,
+			"latitude":    41.15,
+			"longitude":   -8.61,
+			"eventDate":   time.Now().Add(-72 * time.Hour).Format(time.RFC3339),
+			"status":      "published",
+		})
+
+		resp = doRequest(t, http.MethodGet, "/organizers/"+organizer.ID, nil, nil)
+		var page struct {
+			UpcomingEvents []EventResponse `json:"upcomingEvents"`
+			PastEvents     []EventResponse `json:"pastEvents"`
+		}
+		decodeJSON(t, resp.Body, &page)
+		resp.Body.Close()
+		if len(page.UpcomingEvents) != 1 || page.UpcomingEvents[0].ID != upcoming.ID ||
+			len(page.PastEvents) != 1 || page.PastEvents[0].ID != past.ID {
+			t.Fatalf("expected the public page to split upcoming and past events, got %+v", page)
+		}
+
+		named := createEvent(t, cookie, map[string]any{
+			"title":     "Free Text Organizer",
+			"organizer": "Side Project",
+			"latitude":  41.15,
+			"longitude": -8.61,
+			"eventDate": "2031-05-01T18:00:00Z",
+		})
+		if named.OrganizerID == "" || named.OrganizerID == organizer.ID {
+			t.Fatalf("expected an organizer name to get its own profile, got %+v", named)
+		}
+		resp = doRequest(t, http.MethodPut, "/events/"+named.ID, map[string]any{"organizerId": ""}, headers)
+		var unlinked EventResponse
+		decodeJSON(t, resp.Body, &unlinked)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || unlinked.OrganizerID != "" || unlinked.Organizer != "Side Project" {
+			t.Fatalf("expected the profile to be unlinked and the name kept, got %d %+v", resp.StatusCode, unlinked)
+		}
+
+		otherEmail := randomEmail()
+		other := loginAndGetCookie(t, otherEmail, "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodPut, "/organizers/"+organizer.ID, map[string]any{"bio": "Hijacked"}, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected non-managers to be forbidden, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, "/organizers/"+organizer.ID+"/managers", map[string]any{"email": otherEmail}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected manager to be added, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+upcoming.ID, nil, nil)
+		resp.Body.Close()
+		etag := resp.Header.Get("ETag")
+		resp = doRequest(t, http.MethodPut, "/organizers/"+organizer.ID, map[string]any{"bio": "Now co-managed"}, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the new manager to edit the profile, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+upcoming.ID, nil, map[string]string{"If-None-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+			t.Fatalf("expected a profile edit to change the event ETag, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodDelete, "/organizers/"+organizer.ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected deleting a linked organizer to conflict, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "agenda sessions and personal agenda", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Agenda Conf",
+			"latitude":  52.52,
+			"longitude": 13.40,
+			"eventDate": "2031-10-01T08:00:00Z",
+			"status":    "published",
+		})
+		base := "/events/" + event.ID + "/agenda"
+
+		resp := doRequest(t, http.MethodPost, base+"/speakers", map[string]any{"name": "Ada Lovelace", "bio": "Analyst"}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected speaker to be created, got %d", resp.StatusCode)
+		}
+		var speaker struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &speaker)
+		resp.Body.Close()
+
+		resp = doRequest(t, http.MethodPost, base+"/tracks", map[string]any{"name": "Main", "color": "#336699"}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected track to be created, got %d", resp.StatusCode)
+		}
+		var track struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &track)
+		resp.Body.Close()
+
+		createSession := func(title, room, start, end string) (*http.Response, string) {
+			resp := doRequest(t, http.MethodPost, base+"/sessions", map[string]any{
+				"title":      title,
+				"room":       room,
+				"startsAt":   start,
+				"endsAt":     end,
+				"trackId":    track.ID,
+				"speakerIds": []string{speaker.ID},
+			}, headers)
+			var session struct {
+				ID string `json:"id"`
+			}
+			if resp.StatusCode == http.StatusCreated {
+				decodeJSON(t, resp.Body, &session)
+			}
+			resp.Body.Close()
+			return resp, session.ID
+		}
+
+		resp, keynote := createSession("Keynote", "Hall A", "2031-10-01T09:00:00Z", "2031-10-01T10:00:00Z")
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected session to be created, got %d", resp.StatusCode)
+		}
+		resp, _ = createSession("Double Booked", "hall a", "2031-10-01T09:30:00Z", "2031-10-01T10:30:00Z")
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected an overlapping session in the same room to conflict, got %d", resp.StatusCode)
+		}
+		resp, workshop := createSession("Workshop", "Room B", "2031-10-01T09:30:00Z", "2031-10-01T10:30:00Z")
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected a parallel session in another room, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, base, nil, nil)
+		var agenda struct {
+			Sessions []struct {
+				ID       string `json:"id"`
+				Speakers []struct {
+					Name string `json:"name"`
+				} `json:"speakers"`
+			} `json:"sessions"`
+		}
+		decodeJSON(t, resp.Body, &agenda)
+		resp.Body.Close()
+		if len(agenda.Sessions) != 2 || agenda.Sessions[0].ID != keynote || len(agenda.Sessions[0].Speakers) != 1 {
+			t.Fatalf("expected the agenda in schedule order with speakers, got %+v", agenda)
+		}
+
+		attendeeEmail := randomEmail()
+		participant := registerParticipant(t, event.ID, "Agenda Fan", attendeeEmail)
+		attendee := map[string]string{"Cookie": loginAndGetCookie(t, attendeeEmail, "StrongPassw0rd!")}
+		for _, sessionID := range []string{keynote, workshop} {
+			resp = doRequest(t, http.MethodPost, "/participant/"+participant.ID+"/agenda", map[string]any{"sessionId": sessionID}, attendee)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected session to be added to the personal agenda, got %d", resp.StatusCode)
+			}
+		}
+
+		resp = doRequest(t, http.MethodGet, "/participant/"+participant.ID+"/agenda", nil, attendee)
+		var personal struct {
+			Sessions []struct {
+				ID            string   `json:"id"`
+				ConflictsWith []string `json:"conflictsWith"`
+			} `json:"sessions"`
+		}
+		decodeJSON(t, resp.Body, &personal)
+		resp.Body.Close()
+		if len(personal.Sessions) != 2 || len(personal.Sessions[0].ConflictsWith) != 1 {
+			t.Fatalf("expected both sessions with their clash reported, got %+v", personal)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/participant/"+participant.ID+"/agenda", nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the organizer to see personal agendas, got %d", resp.StatusCode)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodDelete, base+"/sessions/"+keynote, nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodDelete, "/participant/"+participant.ID+"/agenda/"+keynote, nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from personal agendas, got %d", resp.StatusCode)
+		}
+
+		resetCookies(t)
+		resp = doRequest(t, http.MethodGet, "/participant/"+participant.ID+"/agenda", nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected personal agendas to require authentication, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "ticket types and inventory", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Ticketed Meetup",
+			"latitude":  48.85,
+			"longitude": 2.35,
+			"eventDate": "2031-11-05T18:00:00Z",
+			"status":    "published",
+		})
+
+		resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/ticket-types", map[string]any{
+			"name":        "Early bird",
+			"priceCents":  0,
+			"currency":    "eur",
+			"quantity":    1,
+			"minPerOrder": 3,
+			"maxPerOrder": 2,
+		}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected inverted per-order limits to be rejected, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, "/events/"+event.ID+"/ticket-types", map[string]any{
+			"name":     "Early bird",
+			"currency": "EUR",
+			"quantity": 1,
+		}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected ticket type to be created, got %d", resp.StatusCode)
+		}
+		var ticketType struct {
+			ID        string `json:"id"`
+			Available *int   `json:"available"`
+			OnSale    bool   `json:"onSale"`
+		}
+		decodeJSON(t, resp.Body, &ticketType)
+		resp.Body.Close()
+		if ticketType.Available == nil || *ticketType.Available != 1 || !ticketType.OnSale {
+			t.Fatalf("expected one ticket on sale, got %+v", ticketType)
+		}
+
+		register := func(ticketTypeID string) *http.Response {
+			payload := map[string]any{
+				"fullName":          "Ticket Holder",
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "friends",
+			}
+			if ticketTypeID != "" {
+				payload["ticketTypeId"] = ticketTypeID
+			}
+			resp := doRequest(t, http.MethodPost, "/participant/event/"+event.ID, payload, nil)
+			resp.Body.Close()
+			return resp
+		}
+
+		if resp := register(""); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected registration without a ticket type to be rejected, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID, nil, nil)
+		resp.Body.Close()
+		etag := resp.Header.Get("ETag")
+		if resp := register(ticketType.ID); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected registration with a ticket, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID, nil, map[string]string{"If-None-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+			t.Fatalf("expected a sale to change the event ETag, got %d", resp.StatusCode)
+		}
+		if resp := register(ticketType.ID); resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected the second registration to find the tickets sold out, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID, nil, nil)
+		var fetched struct {
+			TicketsAvailable *int `json:"ticketsAvailable"`
+			TicketTypes      []struct {
+				SoldOut bool `json:"soldOut"`
+			} `json:"ticketTypes"`
+		}
+		decodeJSON(t, resp.Body, &fetched)
+		resp.Body.Close()
+		if fetched.TicketsAvailable == nil || *fetched.TicketsAvailable != 0 || len(fetched.TicketTypes) != 1 || !fetched.TicketTypes[0].SoldOut {
+			t.Fatalf("expected the event to report no tickets left, got %+v", fetched)
+		}
+
+		resp = doRequest(t, http.MethodPut, "/events/"+event.ID+"/ticket-types/"+ticketType.ID, map[string]any{
+			"name":     "Early bird",
+			"quantity": 0,
+		}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected lowering the quantity below the tickets sold to conflict, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodDelete, "/events/"+event.ID+"/ticket-types/"+ticketType.ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected a ticket type with sales to be kept, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/participant/event/"+event.ID, nil, headers)
+		var holders []ParticipantResponse
+		decodeJSON(t, resp.Body, &holders)
+		resp.Body.Close()
+		if len(holders) != 1 {
+			t.Fatalf("expected one ticket holder, got %d", len(holders))
+		}
+		resp = doRequest(t, http.MethodDelete, "/participant/"+holders[0].ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected the participant to be deleted, got %d", resp.StatusCode)
+		}
+		if resp := register(ticketType.ID); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected a deleted registration to free its ticket, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodPost, "/trash/participants/"+holders[0].ID+"/restore", nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected restoring into a sold out ticket type to conflict, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "paid checkout with fake payment provider", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Paid Workshop",
+			"latitude":  41.39,
+			"longitude": 2.17,
+			"eventDate": "2031-12-01T10:00:00Z",
+			"status":    "published",
+		})
+
+		resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/ticket-types", map[string]any{
+			"name":        "Standard",
+			"priceCents":  2500,
+			"currency":    "EUR",
+			"quantity":    2,
+			"maxPerOrder": 2,
+		}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected ticket type to be created, got %d", resp.StatusCode)
+		}
+		var ticketType struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &ticketType)
+		resp.Body.Close()
+
+		resp = doRequest(t, http.MethodPost, "/participant/event/"+event.ID, map[string]any{
+			"fullName":          "Free Rider",
+			"email":             randomEmail(),
+			"sourceOfDiscovery": "friends",
+			"ticketTypeId":      ticketType.ID,
+		}, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusPaymentRequired {
+			t.Fatalf("expected paid tickets to require checkout, got %d", resp.StatusCode)
+		}
+
+		checkout := func(quantity int) *http.Response {
+			return doRequest(t, http.MethodPost, "/events/"+event.ID+"/orders", map[string]any{
+				"fullName":          "Paying Guest",
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "social_media",
+				"items":             []map[string]any{{"ticketTypeId": ticketType.ID, "quantity": quantity}},
+			}, nil)
+		}
+
+		resp = checkout(2)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected checkout to create an order, got %d", resp.StatusCode)
+		}
+		var created struct {
+			Order struct {
+				ID         string `json:"id"`
+				Status     string `json:"status"`
+				TotalCents int64  `json:"totalCents"`
+			} `json:"order"`
+			CheckoutURL string `json:"checkoutUrl"`
+		}
+		decodeJSON(t, resp.Body, &created)
+		resp.Body.Close()
+		if created.Order.Status != "pending" || created.Order.TotalCents != 5000 || created.CheckoutURL == "" {
+			t.Fatalf("expected a pending order of 50.00 with a checkout URL, got %+v", created)
+		}
+
+		resp = checkout(1)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected tickets held by the pending order to be unavailable, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/participant/event/"+event.ID, nil, map[string]string{"Cookie": cookie})
+		var participants []ParticipantResponse
+		decodeJSON(t, resp.Body, &participants)
+		resp.Body.Close()
+		if len(participants) != 0 {
+			t.Fatalf("expected no participants before payment, got %d", len(participants))
+		}
+
+		resp = doRequest(t, http.MethodPost, "/payments/webhook/fake", map[string]any{"reference": "forged", "outcome": "succeeded"}, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected an unsigned webhook to be rejected, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, strings.TrimPrefix(created.CheckoutURL, "/api"), map[string]any{"outcome": "succeeded"}, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected the fake payment to be accepted, got %d", resp.StatusCode)
+		}
+
+		var order struct {
+			Status string `json:"status"`
+		}
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+			resp = doRequest(t, http.MethodGet, "/orders/"+created.Order.ID, nil, nil)
+			decodeJSON(t, resp.Body, &order)
+			resp.Body.Close()
+			if order.Status != "pending" {
+				break
+			}
+		}
+		if order.Status != "paid" {
+			t.Fatalf("expected the webhook to mark the order paid, got %q", order.Status)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/participant/event/"+event.ID, nil, map[string]string{"Cookie": cookie})
+		decodeJSON(t, resp.Body, &participants)
+		resp.Body.Close()
+		if len(participants) != 2 {
+			t.Fatalf("expected a participant per paid ticket, got %d", len(participants))
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodPost, "/orders/"+created.Order.ID+"/refund", nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from refunding, got %d", resp.StatusCode)
+		}
+
+		// A participant trashed before the refund already gave its ticket back
+		resp = doRequest(t, http.MethodDelete, "/participant/"+participants[0].ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected the participant to be deleted, got %d", resp.StatusCode)
+		}
+		firstOrderID := created.Order.ID
+		resp = checkout(1)
+		decodeJSON(t, resp.Body, &created)
+		resp.Body.Close()
+		resp = doRequest(t, http.MethodPost, strings.TrimPrefix(created.CheckoutURL, "/api"), map[string]any{"outcome": "succeeded"}, nil)
+		resp.Body.Close()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+			resp = doRequest(t, http.MethodGet, "/orders/"+created.Order.ID, nil, nil)
+			decodeJSON(t, resp.Body, &order)
+			resp.Body.Close()
+			if order.Status != "pending" {
+				break
+			}
+		}
+		if order.Status != "paid" {
+			t.Fatalf("expected the freed ticket to be bought, got %q", order.Status)
+		}
+
+		resp = doRequest(t, http.MethodPost, "/orders/"+firstOrderID+"/refund", nil, headers)
+		decodeJSON(t, resp.Body, &order)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || order.Status != "refunded" {
+			t.Fatalf("expected the owner to refund the order, got %d %q", resp.StatusCode, order.Status)
+		}
+
+		ticketsAvailable := func() *int {
+			resp := doRequest(t, http.MethodGet, "/events/"+event.ID, nil, nil)
+			defer resp.Body.Close()
+			var fetched struct {
+				TicketsAvailable *int `json:"ticketsAvailable"`
+			}
+			decodeJSON(t, resp.Body, &fetched)
+			return fetched.TicketsAvailable
+		}
+		if available := ticketsAvailable(); available == nil || *available != 1 {
+			t.Fatalf("expected only the ticket still held by the refunded order back on sale, got %v", available)
+		}
+		resp = doRequest(t, http.MethodPost, "/trash/participants/"+participants[0].ID+"/restore", nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected restoring a participant of a refunded order to conflict, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, "/orders/"+created.Order.ID+"/refund", nil, headers)
+		resp.Body.Close()
+		if available := ticketsAvailable(); available == nil || *available != 2 {
+			t.Fatalf("expected refunded tickets back on sale, got %v", available)
+		}
+
+		// A payment completed after the reaper expired its order is refunded
+		resp = checkout(1)
+		decodeJSON(t, resp.Body, &created)
+		resp.Body.Close()
+		if err := db.Model(&models.Order{}).Where("id = ?", created.Order.ID).Update("created_at", time.Now().Add(-time.Hour)).Error; err != nil {
+			t.Fatalf("failed to backdate order: %v", err)
+		}
+		waitForOrder := func(status string) {
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+				resp = doRequest(t, http.MethodGet, "/orders/"+created.Order.ID, nil, nil)
+				decodeJSON(t, resp.Body, &order)
+				resp.Body.Close()
+				if order.Status == status {
+					return
+				}
+			}
+			t.Fatalf("expected the order to become %s, got %q", status, order.Status)
+		}
+		waitForOrder("failed")
+		resp = doRequest(t, http.MethodPost, strings.TrimPrefix(created.CheckoutURL, "/api"), map[string]any{"outcome": "succeeded"}, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected the late payment to be accepted, got %d", resp.StatusCode)
+		}
+		waitForOrder("refunded")
+
+		if available := ticketsAvailable(); available == nil || *available != 2 {
+			t.Fatalf("expected the expired order to keep no tickets, got %v", available)
+		}
+	})
+
+	runSubtest(t, "promo codes and redemption report", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Discounted Conference",
+			"latitude":  48.85,
+			"longitude": 2.35,
+			"eventDate": "2031-11-01T09:00:00Z",
+			"status":    "published",
+		})
+
+		resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/ticket-types", map[string]any{
+			"name":        "Standard",
+			"priceCents":  4000,
+			"currency":    "EUR",
+			"maxPerOrder": 4,
+		}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected ticket type to be created, got %d", resp.StatusCode)
+		}
+		var ticketType struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &ticketType)
+		resp.Body.Close()
+
+		createCode := func(body map[string]any) *http.Response {
+			return doRequest(t, http.MethodPost, "/events/"+event.ID+"/promo-codes", body, headers)
+		}
+		resp = createCode(map[string]any{"code": "comp", "discountType": "percent", "discountValue": 100, "maxRedemptions": 2, "perEmailLimit": 1})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected comp code to be created, got %d", resp.StatusCode)
+		}
+		resp = createCode(map[string]any{"code": "COMP", "discountType": "percent", "discountValue": 10})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected duplicate codes to be rejected, got %d", resp.StatusCode)
+		}
+		resp = createCode(map[string]any{"code": "TAKE25", "discountType": "percent", "discountValue": 25})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected percent code to be created, got %d", resp.StatusCode)
+		}
+
+		type checkoutResult struct {
+			Order struct {
+				Status        string `json:"status"`
+				SubtotalCents int64  `json:"subtotalCents"`
+				DiscountCents int64  `json:"discountCents"`
+				TotalCents    int64  `json:"totalCents"`
+			} `json:"order"`
+		}
+		checkout := func(email, code, source string) (*http.Response, checkoutResult) {
+			resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/orders", map[string]any{
+				"fullName":          "Promo Guest",
+				"email":             email,
+				"sourceOfDiscovery": source,
+				"promoCode":         code,
+				"items":             []map[string]any{{"ticketTypeId": ticketType.ID, "quantity": 2}},
+			}, nil)
+			var result checkoutResult
+			if resp.StatusCode == http.StatusCreated {
+				decodeJSON(t, resp.Body, &result)
+			}
+			resp.Body.Close()
+			return resp, result
+		}
+
+		compEmail := randomEmail()
+		resp, comp := checkout(compEmail, "comp", "friends")
+		if resp.StatusCode != http.StatusCreated || comp.Order.Status != "paid" || comp.Order.TotalCents != 0 {
+			t.Fatalf("expected a comp order to be paid straight away, got %d %+v", resp.StatusCode, comp)
+		}
+		if resp, _ = checkout(compEmail, "COMP", "friends"); resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected the per-email limit to be enforced, got %d", resp.StatusCode)
+		}
+		if resp, _ = checkout(randomEmail(), "COMP", "social_media"); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected a second comp to be redeemed, got %d", resp.StatusCode)
+		}
+		if resp, _ = checkout(randomEmail(), "COMP", "social_media"); resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected the redemption cap to be enforced, got %d", resp.StatusCode)
+		}
+		if resp, _ = checkout(randomEmail(), "NOPE", "friends"); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected an unknown code to be rejected, got %d", resp.StatusCode)
+		}
+
+		resp, discounted := checkout(randomEmail(), "take25", "found_myself")
+		if resp.StatusCode != http.StatusCreated || discounted.Order.Status != "pending" ||
+			discounted.Order.SubtotalCents != 8000 || discounted.Order.DiscountCents != 2000 || discounted.Order.TotalCents != 6000 {
+			t.Fatalf("expected 25%% off 80.00, got %d %+v", resp.StatusCode, discounted)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/promo-codes/report", nil, headers)
+		var report []struct {
+			Code        string `json:"code"`
+			Redeemed    int    `json:"redeemed"`
+			Redemptions int64  `json:"redemptions"`
+			PaidOrders  int64  `json:"paidOrders"`
+			BySource    []struct {
+				SourceOfDiscovery string `json:"sourceOfDiscovery"`
+				Redemptions       int64  `json:"redemptions"`
+			} `json:"bySource"`
+		}
+		decodeJSON(t, resp.Body, &report)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || len(report) != 2 {
+			t.Fatalf("expected a report row per code, got %d %+v", resp.StatusCode, report)
+		}
+		for _, row := range report {
+			if row.Code == "COMP" && (row.Redeemed != 2 || row.Redemptions != 2 || row.PaidOrders != 2 || len(row.BySource) != 2) {
+				t.Fatalf("expected two paid comps from two sources, got %+v", row)
+			}
+			if row.Code == "TAKE25" && (row.Redemptions != 1 || row.PaidOrders != 0) {
+				t.Fatalf("expected one pending redemption of TAKE25, got %+v", row)
+			}
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/promo-codes", nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected promo codes to be hidden from other users, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "concurrent registrations never oversell", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Popular Meetup",
+			"latitude":  52.52,
+			"longitude": 13.40,
+			"eventDate": "2031-10-01T18:00:00Z",
+			"status":    "published",
+		})
+
+		const capacity, attempts = 5, 40
+		resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/ticket-types", map[string]any{
+			"name":     "Free seat",
+			"quantity": capacity,
+		}, map[string]string{"Cookie": cookie})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected ticket type to be created, got %d", resp.StatusCode)
+		}
+		var ticketType struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &ticketType)
+		resp.Body.Close()
+
+		// doRequest fails the test, which only the test goroutine may do, so the racing
+		// requests report their outcome through a channel instead
+		type outcome struct {
+			status int
+			err    error
+		}
+		results := make(chan outcome, attempts)
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < attempts; i++ {
+			body, err := json.Marshal(map[string]any{
+				"fullName":          "Racing Guest",
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "friends",
+				"ticketTypeId":      ticketType.ID,
+			})
+			if err != nil {
+				t.Fatalf("failed to marshal body: %v", err)
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				req, err := http.NewRequest(http.MethodPost, apiBaseURL+"/participant/event/"+event.ID, bytes.NewReader(body))
+				if err != nil {
+					results <- outcome{err: err}
+					return
+				}
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := testClient.Do(req)
+				if err != nil {
+					results <- outcome{err: err}
+					return
+				}
+				resp.Body.Close()
+				results <- outcome{status: resp.StatusCode}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		close(results)
+
+		var created, soldOut, other int
+		for result := range results {
+			switch {
+			case result.err != nil:
+				t.Fatalf("request failed: %v", result.err)
+			case result.status == http.StatusCreated:
+				created++
+			case result.status == http.StatusConflict:
+				soldOut++
+			default:
+				other++
+			}
+		}
+		if created != capacity || soldOut != attempts-capacity || other != 0 {
+			t.Fatalf("expected %d registrations and %d sold out, got created=%d soldOut=%d other=%d",
+				capacity, attempts-capacity, created, soldOut, other)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/participant/event/"+event.ID, nil, map[string]string{"Cookie": cookie})
+		var participants []ParticipantResponse
+		decodeJSON(t, resp.Body, &participants)
+		resp.Body.Close()
+		if len(participants) != capacity {
+			t.Fatalf("expected %d participants, got %d", capacity, len(participants))
+		}
+
+		var counters struct {
+			Sold     int
+			Reserved int
+		}
+		if err := db.Raw("SELECT sold, reserved FROM ticket_types WHERE id = ?", ticketType.ID).Scan(&counters).Error; err != nil {
+			t.Fatalf("failed to read ticket counters: %v", err)
+		}
+		if counters.Sold != capacity || counters.Reserved != 0 {
+			t.Fatalf("expected %d sold and none reserved, got %+v", capacity, counters)
+		}
+	})
+
+	runSubtest(t, "ticket holds convert, expire and never oversell", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Small Workshop",
+			"latitude":  45.46,
+			"longitude": 9.19,
+			"eventDate": "2031-09-15T14:00:00Z",
+			"status":    "published",
+		})
+
+		const capacity, attempts = 3, 20
+		resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/ticket-types", map[string]any{
+			"name":     "Free seat",
+			"quantity": capacity,
+		}, map[string]string{"Cookie": cookie})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected ticket type to be created, got %d", resp.StatusCode)
+		}
+		var ticketType struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &ticketType)
+		resp.Body.Close()
+
+		type holdResult struct {
+			ID        string    `json:"id"`
+			Status    string    `json:"status"`
+			ExpiresAt time.Time `json:"expiresAt"`
+		}
+		var (
+			mu    sync.Mutex
+			holds []holdResult
+			wg    sync.WaitGroup
+		)
+		start := make(chan struct{})
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/holds", map[string]any{
+					"ticketTypeId": ticketType.ID,
+					"email":        randomEmail(),
+				}, nil)
+				defer resp.Body.Close()
+				if resp.StatusCode != http.StatusCreated {
+					return
+				}
+				var hold holdResult
+				if err := json.NewDecoder(resp.Body).Decode(&hold); err != nil {
+					return
+				}
+				mu.Lock()
+				holds = append(holds, hold)
+				mu.Unlock()
+			}()
+		}
+		close(start)
+		wg.Wait()
+		if len(holds) != capacity {
+			t.Fatalf("expected exactly %d holds, got %d", capacity, len(holds))
+		}
+		if holds[0].Status != "active" || !holds[0].ExpiresAt.After(time.Now()) {
+			t.Fatalf("expected an active hold expiring in the future, got %+v", holds[0])
+		}
+
+		register := func(holdID string) *http.Response {
+			body := map[string]any{
+				"fullName":          "Held Guest",
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "found_myself",
+				"ticketTypeId":      ticketType.ID,
+			}
+			if holdID != "" {
+				body["holdId"] = holdID
+			}
+			return doRequest(t, http.MethodPost, "/participant/event/"+event.ID, body, nil)
+		}
+
+		resp = register("")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected held tickets to be unavailable, got %d", resp.StatusCode)
+		}
+
+		resp = register(holds[0].ID)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected the hold to convert into a participant, got %d", resp.StatusCode)
+		}
+		resp = register(holds[0].ID)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected a converted hold to be single use, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodDelete, "/holds/"+holds[1].ID, nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected the hold to be released, got %d", resp.StatusCode)
+		}
+
+		if err := db.Model(&models.Hold{}).Where("id = ?", holds[2].ID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+			t.Fatalf("failed to backdate hold: %v", err)
+		}
+		resp = register(holds[2].ID)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected an expired hold to be rejected, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/holds/"+holds[2].ID, nil, nil)
+		var hold holdResult
+		decodeJSON(t, resp.Body, &hold)
+		resp.Body.Close()
+		if hold.Status != "expired" {
+			t.Fatalf("expected the hold to expire, got %q", hold.Status)
+		}
+
+		var counters struct {
+			Sold     int
+			Reserved int
+		}
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+			if err := db.Raw("SELECT sold, reserved FROM ticket_types WHERE id = ?", ticketType.ID).Scan(&counters).Error; err != nil {
+				t.Fatalf("failed to read ticket counters: %v", err)
+			}
+			if counters.Reserved == 0 {
+				break
+			}
+		}
+		if counters.Sold != 1 || counters.Reserved != 0 {
+			t.Fatalf("expected the reaper to release the expired hold, got %+v", counters)
+		}
+
+		for i := 0; i < capacity-1; i++ {
+			resp = register("")
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("expected released tickets to be back on sale, got %d", resp.StatusCode)
+			}
+		}
+		resp = register("")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected the event to be full, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "ticket codes, QR codes and check-in", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		newEvent := func(title string) EventResponse {
+			return createEvent(t, cookie, map[string]any{
+				"title":     title,
+				"latitude":  51.51,
+				"longitude": -0.13,
+				"eventDate": "2031-08-20T19:00:00Z",
+				"status":    "published",
+			})
+		}
+		event := newEvent("Door Check Concert")
+		otherEvent := newEvent("Another Concert")
+
+		type ticketResult struct {
+			ParticipantID string     `json:"participantId"`
+			Code          string     `json:"code"`
+			QRPNGURL      string     `json:"qrPngUrl"`
+			QRSVGURL      string     `json:"qrSvgUrl"`
+			CheckedInAt   *time.Time `json:"checkedInAt"`
+		}
+		register := func() ticketResult {
+			resp := doRequest(t, http.MethodPost, "/participant/event/"+event.ID, map[string]any{
+				"fullName":          "Concert Goer",
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "friends",
+			}, nil)
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("expected registration to succeed, got %d", resp.StatusCode)
+			}
+			var registration struct {
+				ID     string       `json:"id"`
+				Ticket ticketResult `json:"ticket"`
+			}
+			decodeJSON(t, resp.Body, &registration)
+			if registration.Ticket.Code == "" || registration.Ticket.ParticipantID != registration.ID {
+				t.Fatalf("expected a ticket code for the participant, got %+v", registration)
+			}
+			return registration.Ticket
+		}
+		first, second := register(), register()
+
+		resp := doRequest(t, http.MethodGet, strings.TrimPrefix(first.QRPNGURL, "/api"), nil, nil)
+		png, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/png" || !strings.HasPrefix(string(png), "\x89PNG") {
+			t.Fatalf("expected a PNG QR code, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		resp = doRequest(t, http.MethodGet, strings.TrimPrefix(first.QRSVGURL, "/api"), nil, nil)
+		svg, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(svg), "<svg") {
+			t.Fatalf("expected an SVG QR code, got %d", resp.StatusCode)
+		}
+
+		idPart, _, _ := strings.Cut(first.Code, ".")
+		forged := idPart + ".AAAAAAAAAAAAAAAAAAAAAA"
+		resp = doRequest(t, http.MethodGet, "/tickets/"+forged, nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected a forged code to be rejected, got %d", resp.StatusCode)
+		}
+
+		checkIn := func(eventID, code string, headers map[string]string) *http.Response {
+			return doRequest(t, http.MethodPost, "/events/"+eventID+"/check-ins", map[string]any{"code": code}, headers)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = checkIn(event.ID, first.Code, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from checking in, got %d", resp.StatusCode)
+		}
+
+		resp = checkIn(otherEvent.ID, first.Code, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected a ticket for another event to be rejected, got %d", resp.StatusCode)
+		}
+
+		type checkInResult struct {
+			Ticket  ticketResult `json:"ticket"`
+			CheckIn struct {
+				CheckedInAt time.Time `json:"checkedInAt"`
+				ScannedBy   string    `json:"scannedBy"`
+			} `json:"checkIn"`
+		}
+		resp = checkIn(event.ID, first.Code, headers)
+		var admitted checkInResult
+		decodeJSON(t, resp.Body, &admitted)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated || admitted.CheckIn.ScannedBy == "" || admitted.Ticket.ParticipantID != first.ParticipantID {
+			t.Fatalf("expected the ticket to be checked in, got %d %+v", resp.StatusCode, admitted)
+		}
+
+		resp = checkIn(event.ID, first.Code, headers)
+		var duplicate checkInResult
+		decodeJSON(t, resp.Body, &duplicate)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict || !duplicate.CheckIn.CheckedInAt.Equal(admitted.CheckIn.CheckedInAt) {
+			t.Fatalf("expected a duplicate scan to report the first check-in, got %d %+v", resp.StatusCode, duplicate)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/check-ins/live", nil, headers)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the live check-in stream, got %d", resp.StatusCode)
+		}
+		stream := bufio.NewReader(resp.Body)
+		type liveStats struct {
+			Registered int64 `json:"registered"`
+			CheckedIn  int64 `json:"checkedIn"`
+		}
+		nextStats := func() liveStats {
+			for {
+				line, err := stream.ReadString('\n')
+				if err != nil {
+					t.Fatalf("failed to read check-in stream: %v", err)
+				}
+				if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+					var stats liveStats
+					if err := json.Unmarshal([]byte(data), &stats); err != nil {
+						t.Fatalf("failed to decode live stats: %v", err)
+					}
+					return stats
+				}
+			}
+		}
+		if stats := nextStats(); stats.Registered != 2 || stats.CheckedIn != 1 {
+			t.Fatalf("expected 1 of 2 checked in, got %+v", stats)
+		}
+
+		resp2 := checkIn(event.ID, second.Code, headers)
+		resp2.Body.Close()
+		if resp2.StatusCode != http.StatusCreated {
+			t.Fatalf("expected the second ticket to be checked in, got %d", resp2.StatusCode)
+		}
+		if stats := nextStats(); stats.CheckedIn != 2 {
+			t.Fatalf("expected the live counts to follow check-ins, got %+v", stats)
+		}
+
+		resp2 = doRequest(t, http.MethodGet, "/tickets/"+first.Code, nil, nil)
+		var fetched ticketResult
+		decodeJSON(t, resp2.Body, &fetched)
+		resp2.Body.Close()
+		if fetched.CheckedInAt == nil {
+			t.Fatalf("expected the ticket to show its check-in, got %+v", fetched)
+		}
+	})
+
+	runSubtest(t, "offline kiosk manifest and sync", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Offline Door Festival",
+			"latitude":  48.85,
+			"longitude": 2.35,
+			"eventDate": "2031-09-12T18:00:00Z",
+			"status":    "published",
+		})
+
+		register := func() (string, string) {
+			resp := doRequest(t, http.MethodPost, "/participant/event/"+event.ID, map[string]any{
+				"fullName":          "Festival Goer",
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "friends",
+			}, nil)
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("expected registration to succeed, got %d", resp.StatusCode)
+			}
+			var registration struct {
+				ID     string `json:"id"`
+				Ticket struct {
+					Code string `json:"code"`
+				} `json:"ticket"`
+			}
+			decodeJSON(t, resp.Body, &registration)
+			return registration.ID, registration.Ticket.Code
+		}
+		firstID, firstCode := register()
+		_, secondCode := register()
+
+		resp := doRequest(t, http.MethodGet, "/tickets/manifest-key", nil, nil)
+		var key struct {
+			Algorithm string `json:"algorithm"`
+			PublicKey string `json:"publicKey"`
+		}
+		decodeJSON(t, resp.Body, &key)
+		resp.Body.Close()
+		publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+		if err != nil || key.Algorithm != "Ed25519" || len(publicKey) != ed25519.PublicKeySize {
+			t.Fatalf("expected an Ed25519 public key, got %+v", key)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/check-ins/manifest", nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from the manifest, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/check-ins/manifest", nil, headers)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the manifest, got %d", resp.StatusCode)
+		}
+		signature, err := base64.StdEncoding.DecodeString(resp.Header.Get("X-Manifest-Signature"))
+		if err != nil || !ed25519.Verify(publicKey, body, signature) {
+			t.Fatalf("expected the manifest signature to verify")
+		}
+		var manifest struct {
+			EventID string `json:"eventId"`
+			Tickets []struct {
+				CodeHash      string `json:"codeHash"`
+				ParticipantID string `json:"participantId"`
+			} `json:"tickets"`
+		}
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			t.Fatalf("failed to decode manifest: %v", err)
+		}
+		sum := sha256.Sum256([]byte(firstCode))
+		listed := false
+		for _, ticket := range manifest.Tickets {
+			if ticket.ParticipantID == firstID && ticket.CodeHash == base64.RawURLEncoding.EncodeToString(sum[:]) {
+				listed = true
+			}
+		}
+		if manifest.EventID != event.ID || len(manifest.Tickets) != 2 || !listed {
+			t.Fatalf("expected both tickets listed by code hash, got %+v", manifest)
+		}
+
+		type syncResult struct {
+			Code    string `json:"code"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			CheckIn *struct {
+				CheckedInAt time.Time `json:"checkedInAt"`
+				DeviceID    string    `json:"deviceId"`
+			} `json:"checkIn"`
+		}
+		upload := func(deviceID string, scans ...map[string]any) []syncResult {
+			resp := doRequest(t, http.MethodPost, "/events/"+event.ID+"/check-ins/sync", map[string]any{
+				"deviceId": deviceID,
+				"scans":    scans,
+			}, headers)
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected sync to succeed, got %d", resp.StatusCode)
+			}
+			var results []syncResult
+			decodeJSON(t, resp.Body, &results)
+			if len(results) != len(scans) {
+				t.Fatalf("expected a result per scan, got %+v", results)
+			}
+			return results
+		}
+		scan := func(code string, at time.Time) map[string]any {
+			return map[string]any{"code": code, "scannedAt": at.Format(time.RFC3339Nano)}
+		}
+
+		earlier := time.Now().Add(-10 * time.Minute).UTC()
+		later := earlier.Add(2 * time.Minute)
+
+		// The kiosk holding the later scan syncs first, then loses to the earlier one
+		results := upload("kiosk-b", scan(firstCode, later))
+		if results[0].Status != "accepted" {
+			t.Fatalf("expected the first upload to be accepted, got %+v", results[0])
+		}
+		idPart, _, _ := strings.Cut(secondCode, ".")
+		results = upload("kiosk-a",
+			scan(idPart+".AAAAAAAAAAAAAAAAAAAAAA", earlier),
+			scan(firstCode, earlier),
+			scan(secondCode, time.Now().Add(time.Hour)),
+		)
+		if results[0].Status != "rejected" || results[1].Status != "accepted" || results[2].Status != "rejected" {
+			t.Fatalf("expected forged and future scans rejected and the earlier scan kept, got %+v", results)
+		}
+		if results[1].CheckIn == nil || results[1].CheckIn.DeviceID != "kiosk-a" || !results[1].CheckIn.CheckedInAt.Equal(earlier.Truncate(time.Microsecond)) {
+			t.Fatalf("expected the earlier scan to be the check-in, got %+v", results[1].CheckIn)
+		}
+
+		results = upload("kiosk-b", scan(firstCode, later))
+		if results[0].Status != "duplicate" || results[0].CheckIn.DeviceID != "kiosk-a" {
+			t.Fatalf("expected the later scan to report the earlier check-in, got %+v", results[0])
+		}
+		results = upload("kiosk-a", scan(firstCode, earlier))
+		if results[0].Status != "accepted" {
+			t.Fatalf("expected re-uploading a scan to be idempotent, got %+v", results[0])
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/check-ins/stats", nil, headers)
+		var stats struct {
+			Registered int64 `json:"registered"`
+			CheckedIn  int64 `json:"checkedIn"`
+		}
+		decodeJSON(t, resp.Body, &stats)
+		resp.Body.Close()
+		if stats.Registered != 2 || stats.CheckedIn != 1 {
+			t.Fatalf("expected one check-in after syncing, got %+v", stats)
+		}
+	})
+
+	runSubtest(t, "printable ticket PDFs and badge sheets", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Badge Printing Summit",
+			"latitude":  52.52,
+			"longitude": 13.40,
+			"eventDate": "2031-10-02T09:00:00Z",
+			"status":    "published",
+		})
+
+		resp := doRequest(t, http.MethodGet, "/events/"+event.ID+"/badges.pdf", nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected an event without participants to have no badges, got %d", resp.StatusCode)
+		}
+
+		var pdfURL string
+		for i := 0; i < 9; i++ {
+			resp := doRequest(t, http.MethodPost, "/participant/event/"+event.ID, map[string]any{
+				"fullName":          fmt.Sprintf("Summit Attendee %d", i),
+				"email":             randomEmail(),
+				"sourceOfDiscovery": "friends",
+			}, nil)
+			var registration struct {
+				Ticket struct {
+					PDFURL string `json:"pdfUrl"`
+				} `json:"ticket"`
+			}
+			decodeJSON(t, resp.Body, &registration)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusCreated || registration.Ticket.PDFURL == "" {
+				t.Fatalf("expected registration with a ticket PDF link, got %d", resp.StatusCode)
+			}
+			pdfURL = registration.Ticket.PDFURL
+		}
+
+		readPDF := func(resp *http.Response) string {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/pdf" || !strings.HasPrefix(string(body), "%PDF-") {
+				t.Fatalf("expected a PDF, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+			return string(body)
+		}
+		pageCount := func(pdf string) int {
+			return strings.Count(pdf, "/Type /Page\n")
+		}
+
+		ticket := readPDF(doRequest(t, http.MethodGet, strings.TrimPrefix(pdfURL, "/api"), nil, nil))
+		if pages := pageCount(ticket); pages != 1 {
+			t.Fatalf("expected a one-page ticket, got %d pages", pages)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/badge-templates", nil, nil)
+		var templates []struct {
+			Name    string `json:"name"`
+			Columns int    `json:"columns"`
+			Rows    int    `json:"rows"`
+		}
+		decodeJSON(t, resp.Body, &templates)
+		resp.Body.Close()
+		perPage := map[string]int{}
+		for _, template := range templates {
+			perPage[template.Name] = template.Columns * template.Rows
+		}
+		if perPage["badge-2x4"] != 8 || perPage["label-3x8"] != 24 {
+			t.Fatalf("expected the badge templates to be listed, got %+v", templates)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/badges.pdf", nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from printing badges, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+event.ID+"/badges.pdf?template=poster", nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected an unknown template to be rejected, got %d", resp.StatusCode)
+		}
+
+		sheet := readPDF(doRequest(t, http.MethodGet, "/events/"+event.ID+"/badges.pdf", nil, headers))
+		if pages := pageCount(sheet); pages != 2 {
+			t.Fatalf("expected 9 badges on two pages of eight, got %d pages", pages)
+		}
+		labels := readPDF(doRequest(t, http.MethodGet, "/events/"+event.ID+"/badges.pdf?template=label-3x8", nil, headers))
+		if pages := pageCount(labels); pages != 1 {
+			t.Fatalf("expected 9 labels on one page, got %d pages", pages)
+		}
+	})
+
+	runSubtest(t, "event collaborators and permissions", func(t *testing.T) {
+		ownerEmail := randomEmail()
+		owner := loginAndGetCookie(t, ownerEmail, "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": owner}
+		event := createEvent(t, owner, map[string]any{
+			"title":     "Team Run Festival",
+			"latitude":  48.14,
+			"longitude": 11.58,
+			"eventDate": "2031-09-12T09:00:00Z",
+			"status":    "published",
+		})
+		resp := doRequest(t, http.MethodPost, "/participant/event/"+event.ID, map[string]any{
+			"fullName":          "Eager Runner",
+			"email":             randomEmail(),
+			"sourceOfDiscovery": "friends",
+		}, nil)
+		var registration struct {
+			ID     string `json:"id"`
+			Ticket struct {
+				Code string `json:"code"`
+			} `json:"ticket"`
+		}
+		decodeJSON(t, resp.Body, &registration)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected registration to succeed, got %d", resp.StatusCode)
+		}
+
+		participantsPath := "/participant/event/" + event.ID
+		resp = doRequest(t, http.MethodGet, participantsPath, nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected participants to require a login, got %d", resp.StatusCode)
+		}
+		stranger := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodGet, participantsPath, nil, map[string]string{"Cookie": stranger})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected participants to be hidden from other users, got %d", resp.StatusCode)
+		}
+
+		collaboratorsPath := "/events/" + event.ID + "/collaborators"
+		resp = doRequest(t, http.MethodPost, collaboratorsPath, map[string]any{"email": randomEmail(), "role": "editor"}, map[string]string{"Cookie": stranger})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from inviting, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodPost, collaboratorsPath, map[string]any{"email": ownerEmail, "role": "editor"}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("expected inviting the owner to conflict, got %d", resp.StatusCode)
+		}
+
+		type collaborator struct {
+			ID     string `json:"id"`
+			Email  string `json:"email"`
+			Role   string `json:"role"`
+			Status string `json:"status"`
+		}
+		join := func(collaboratorsPath, role string) (string, collaborator) {
+			email := randomEmail()
+			cookie := loginAndGetCookie(t, email, "StrongPassw0rd!")
+			resp := doRequest(t, http.MethodPost, collaboratorsPath, map[string]any{"email": email, "role": role}, headers)
+			var invited collaborator
+			decodeJSON(t, resp.Body, &invited)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusCreated || invited.Status != "invited" || invited.Role != role {
+				t.Fatalf("expected a pending %s invitation, got %d %+v", role, resp.StatusCode, invited)
+			}
+			mail, ok := mailer.lastEmailTo(email)
+			if !ok {
+				t.Fatalf("expected an invitation email to %s", email)
+			}
+			i := strings.Index(mail.Body, "/invitations/")
+			if i < 0 {
+				t.Fatalf("expected an invitation link, got %q", mail.Body)
+			}
+			token := strings.Fields(mail.Body[i+len("/invitations/"):])[0]
+
+			resp = doRequest(t, http.MethodPost, "/collaborator-invites/accept", map[string]any{"token": token}, map[string]string{"Cookie": stranger})
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden {
+				t.Fatalf("expected an invitation to be bound to its email, got %d", resp.StatusCode)
+			}
+			resp = doRequest(t, http.MethodPost, "/collaborator-invites/accept", map[string]any{"token": token}, map[string]string{"Cookie": cookie})
+			var accepted collaborator
+			decodeJSON(t, resp.Body, &accepted)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || accepted.Status != "active" {
+				t.Fatalf("expected the invitation to be accepted, got %d %+v", resp.StatusCode, accepted)
+			}
+			resp = doRequest(t, http.MethodPost, "/collaborator-invites/accept", map[string]any{"token": token}, map[string]string{"Cookie": cookie})
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Fatalf("expected an invitation to be usable once, got %d", resp.StatusCode)
+			}
+			return cookie, accepted
+		}
+		editor, _ := join(collaboratorsPath, "editor")
+		staff, _ := join(collaboratorsPath, "check_in")
+		viewer, viewerRow := join(collaboratorsPath, "viewer")
+
+		resp = doRequest(t, http.MethodPut, "/events/"+event.ID, map[string]any{"title": "Team Run Festival 2031"}, map[string]string{"Cookie": editor})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected editors to update the event, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodDelete, "/events/"+event.ID, nil, map[string]string{"Cookie": editor})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected only owners to delete the event, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodPost, collaboratorsPath, map[string]any{"email": randomEmail(), "role": "viewer"}, map[string]string{"Cookie": editor})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected only owners to invite, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPut, "/events/"+event.ID, map[string]any{"title": "Hijacked"}, map[string]string{"Cookie": staff})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected check-in staff to be forbidden from editing, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodPost, "/events/"+event.ID+"/check-ins", map[string]any{"code": registration.Ticket.Code}, map[string]string{"Cookie": viewer})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected viewers to be forbidden from checking in, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodPost, "/events/"+event.ID+"/check-ins", map[string]any{"code": registration.Ticket.Code}, map[string]string{"Cookie": staff})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected check-in staff to check attendees in, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, participantsPath, nil, map[string]string{"Cookie": viewer})
+		var participants []ParticipantResponse
+		decodeJSON(t, resp.Body, &participants)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || len(participants) != 1 {
+			t.Fatalf("expected viewers to see participants, got %d with %d", resp.StatusCode, len(participants))
+		}
+		resp = doRequest(t, http.MethodDelete, "/participant/"+registration.ID, nil, map[string]string{"Cookie": viewer})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected viewers to be forbidden from deleting participants, got %d", resp.StatusCode)
+		}
+
+		var role struct {
+			Role string `json:"role"`
+		}
+		resp = doRequest(t, http.MethodGet, collaboratorsPath+"/me", nil, map[string]string{"Cookie": staff})
+		decodeJSON(t, resp.Body, &role)
+		resp.Body.Close()
+		if role.Role != "check_in" {
+			t.Fatalf("expected the check-in role, got %q", role.Role)
+		}
+		resp = doRequest(t, http.MethodGet, collaboratorsPath+"/me", nil, headers)
+		decodeJSON(t, resp.Body, &role)
+		resp.Body.Close()
+		if role.Role != "owner" {
+			t.Fatalf("expected the creator to be an owner, got %q", role.Role)
+		}
+
+		var shared []collaborator
+		resp = doRequest(t, http.MethodGet, "/collaborations", nil, map[string]string{"Cookie": viewer})
+		decodeJSON(t, resp.Body, &shared)
+		resp.Body.Close()
+		if len(shared) != 1 || shared[0].ID != viewerRow.ID {
+			t.Fatalf("expected the shared event to be listed, got %+v", shared)
+		}
+
+		resp = doRequest(t, http.MethodPut, collaboratorsPath+"/"+viewerRow.ID, map[string]any{"role": "editor"}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected the owner to change roles, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodDelete, collaboratorsPath+"/"+viewerRow.ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected the owner to remove a collaborator, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, participantsPath, nil, map[string]string{"Cookie": viewer})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected removed collaborators to lose access, got %d", resp.StatusCode)
+		}
+
+		var list []collaborator
+		resp = doRequest(t, http.MethodGet, collaboratorsPath, nil, map[string]string{"Cookie": editor})
+		decodeJSON(t, resp.Body, &list)
+		resp.Body.Close()
+		if len(list) != 2 {
+			t.Fatalf("expected two remaining collaborators, got %+v", list)
+		}
+
+		trashed := func(cookie string) bool {
+			resp := doRequest(t, http.MethodGet, "/trash/participants", nil, map[string]string{"Cookie": cookie})
+			defer resp.Body.Close()
+			var participants []ParticipantResponse
+			decodeJSON(t, resp.Body, &participants)
+			for _, participant := range participants {
+				if participant.ID == registration.ID {
+					return true
+				}
+			}
+			return false
+		}
+		resp = doRequest(t, http.MethodDelete, "/participant/"+registration.ID, nil, map[string]string{"Cookie": editor})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected editors to delete participants, got %d", resp.StatusCode)
+		}
+		if !trashed(editor) {
+			t.Fatalf("expected editors to see the participants they may restore in the trash")
+		}
+		if trashed(staff) || trashed(stranger) {
+			t.Fatalf("expected the trashed participant to be hidden from users who may not restore it")
+		}
+
+		draft := createEvent(t, owner, map[string]any{
+			"title":     "Team Run Planning",
+			"latitude":  48.14,
+			"longitude": 11.58,
+			"eventDate": "2031-10-12T09:00:00Z",
+			"status":    "draft",
+		})
+		reviewer, _ := join("/events/"+draft.ID+"/collaborators", "viewer")
+		listsDraft := func(cookie string) bool {
+			resp := doRequest(t, http.MethodGet, "/events?status=draft", nil, map[string]string{"Cookie": cookie})
+			defer resp.Body.Close()
+			var drafts EventListResponse
+			decodeJSON(t, resp.Body, &drafts)
+			for _, listed := range drafts.Events {
+				if listed.ID == draft.ID {
+					return true
+				}
+			}
+			return false
+		}
+		if !listsDraft(reviewer) {
+			t.Fatalf("expected collaborators to list drafts shared with them")
+		}
+		if listsDraft(editor) {
+			t.Fatalf("expected drafts to stay hidden from collaborators of other events")
+		}
+
+		resp = doRequest(t, http.MethodPost, "/events/"+draft.ID+"/template", map[string]any{"name": "Team Run"}, map[string]string{"Cookie": editor})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected drafts to stay hidden from collaborators of other events, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodPost, "/events/"+draft.ID+"/template", map[string]any{"name": "Team Run"}, map[string]string{"Cookie": reviewer})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected collaborators to save a shared draft as a template, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "markdown descriptions render to sanitized html", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		source := "**Doors** at 8pm\nSee [the venue](https://example.com/venue) or [this](javascript:alert(1))\n\n<script>alert('x')</script><img src=x onerror=alert(1)>"
+		event := createEvent(t, cookie, map[string]any{
+			"title":       "Markdown Night",
+			"description": source,
+			"latitude":    40.71,
+			"longitude":   -74.0,
+			"eventDate":   "2031-11-05T20:00:00Z",
+			"status":      "published",
+		})
+
+		type description struct {
+			Description         string `json:"description"`
+			DescriptionMarkdown string `json:"descriptionMarkdown"`
+			DescriptionHTML     string `json:"descriptionHtml"`
+		}
+		fetch := func(id string) description {
+			resp := doRequest(t, http.MethodGet, "/events/"+id, nil, headers)
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected the event, got %d", resp.StatusCode)
+			}
+			var got description
+			decodeJSON(t, resp.Body, &got)
+			return got
+		}
+
+		got := fetch(event.ID)
+		if got.DescriptionMarkdown != source || got.Description != source {
+			t.Fatalf("expected the Markdown source to be returned as written, got %q", got.DescriptionMarkdown)
+		}
+		for _, want := range []string{"<strong>Doors</strong> at 8pm<br", `href="https://example.com/venue"`, `rel="nofollow noopener"`} {
+			if !strings.Contains(got.DescriptionHTML, want) {
+				t.Fatalf("expected %q in the rendered description, got %q", want, got.DescriptionHTML)
+			}
+		}
+		for _, unwanted := range []string{"<script", "<img", "onerror", "javascript:"} {
+			if strings.Contains(got.DescriptionHTML, unwanted) {
+				t.Fatalf("expected %q to be removed from the rendered description, got %q", unwanted, got.DescriptionHTML)
+			}
+		}
+
+		csvBody := "Name,Start,Lat,Lng,About\n" +
+			"Imported Markup,2031-11-06 20:00,40.71,-74.0,<p>Doors at <b>8pm</b><br />No re-entry</p>\n" +
+			"Imported Markdown,2031-11-07 20:00,40.71,-74.0,See <https://example.com/tickets> if a<b and c>d\n"
+		path := fmt.Sprintf("%s/events/import?mapping=%s", apiBaseURL, url.QueryEscape("title=Name,eventDate=Start,latitude=Lat,longitude=Lng,description=About"))
+		req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(csvBody))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Cookie", cookie)
+		resp, err := testClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		var report struct {
+			Rows []struct {
+				EventID string `json:"eventId"`
+			} `json:"rows"`
+		}
+		decodeJSON(t, resp.Body, &report)
+		resp.Body.Close()
+		if len(report.Rows) != 2 || report.Rows[0].EventID == "" || report.Rows[1].EventID == "" {
+			t.Fatalf("expected both rows to be imported, got %+v", report)
+		}
+		got = fetch(report.Rows[0].EventID)
+		if got.DescriptionMarkdown != "Doors at **8pm**\nNo re-entry" {
+			t.Fatalf("expected imported HTML to be converted to Markdown, got %q", got.DescriptionMarkdown)
+		}
+		got = fetch(report.Rows[1].EventID)
+		if got.DescriptionMarkdown != "See <https://example.com/tickets> if a<b and c>d" {
+			t.Fatalf("expected an autolink and a comparison to be kept as written, got %q", got.DescriptionMarkdown)
+		}
+		if !strings.Contains(got.DescriptionHTML, `href="https://example.com/tickets"`) {
+			t.Fatalf("expected the autolink to be rendered as a link, got %q", got.DescriptionHTML)
+		}
+	})
+
+	runSubtest(t, "event slugs and share links", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		suffix := uuid.NewString()[:8]
+		newEvent := func(title string) EventResponse {
+			return createEvent(t, cookie, map[string]any{
+				"title":     title,
+				"latitude":  47.37,
+				"longitude": 8.54,
+				"eventDate": "2031-12-01T19:00:00Z",
+				"status":    "published",
+			})
+		}
+		first := newEvent("Café Über Nacht " + suffix)
+		second := newEvent("Cafe Ueber Nacht " + suffix)
+		want := "cafe-ueber-nacht-" + suffix
+		if first.Slug != want || second.Slug != want+"-2" {
+			t.Fatalf("expected transliterated slugs with a collision suffix, got %q and %q", first.Slug, second.Slug)
+		}
+		russian := newEvent("Москва " + suffix)
+		if russian.Slug != "moskva-"+suffix {
+			t.Fatalf("expected a transliterated Cyrillic slug, got %q", russian.Slug)
+		}
+
+		resp := doRequest(t, http.MethodGet, "/events/"+second.Slug, nil, nil)
+		var found EventResponse
+		decodeJSON(t, resp.Body, &found)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || found.ID != second.ID {
+			t.Fatalf("expected the event by slug, got %d %+v", resp.StatusCode, found)
+		}
+		export := newEvent("Export")
+		if !strings.HasPrefix(export.Slug, "export-") {
+			t.Fatalf("expected a route segment to be avoided as a slug, got %q", export.Slug)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+export.Slug, nil, nil)
+		decodeJSON(t, resp.Body, &found)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || found.ID != export.ID {
+			t.Fatalf("expected the event by its suffixed slug, got %d %+v", resp.StatusCode, found)
+		}
+
+		resp = doRequest(t, http.MethodPut, "/events/"+first.ID, map[string]any{"description": "Same title"}, headers)
+		decodeJSON(t, resp.Body, &found)
+		resp.Body.Close()
+		if found.Slug != first.Slug {
+			t.Fatalf("expected the slug to stay while the title does, got %q", found.Slug)
+		}
+		resp = doRequest(t, http.MethodPut, "/events/"+first.ID, map[string]any{"title": "Winter Nights " + suffix}, headers)
+		decodeJSON(t, resp.Body, &found)
+		resp.Body.Close()
+		if found.Slug != "winter-nights-"+suffix {
+			t.Fatalf("expected a new slug after the title changed, got %q", found.Slug)
+		}
+
+		noRedirects := &http.Client{
+			Jar:     testClient.Jar,
+			Timeout: testClient.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		get := func(path string) *http.Response {
+			resp, err := noRedirects.Get(apiBaseURL + path)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			return resp
+		}
+		resp = get("/events/" + want)
+		if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/api/events/winter-nights-"+suffix {
+			t.Fatalf("expected the old slug to redirect temporarily, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+		}
+		resp = get("/events/" + want + "?lang=de")
+		if resp.Header.Get("Location") != "/api/events/winter-nights-"+suffix+"?lang=de" {
+			t.Fatalf("expected the redirect to keep the query string, got %q", resp.Header.Get("Location"))
+		}
+		third := newEvent("Café Über Nacht " + suffix)
+		if third.Slug != want+"-3" {
+			t.Fatalf("expected slugs kept for redirects to stay taken, got %q", third.Slug)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/no-such-event-"+suffix, nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected an unknown slug to be missing, got %d", resp.StatusCode)
+		}
+
+		linksPath := "/events/" + first.ID + "/share-links"
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodPost, linksPath, map[string]any{"label": "spam"}, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden from creating share links, got %d", resp.StatusCode)
+		}
+
+		type shareLink struct {
+			ID     string `json:"id"`
+			Code   string `json:"code"`
+			Label  string `json:"label"`
+			Clicks int64  `json:"clicks"`
+			URL    string `json:"url"`
+		}
+		createLink := func(label string) shareLink {
+			resp := doRequest(t, http.MethodPost, linksPath, map[string]any{"label": label}, headers)
+			defer resp.Body.Close()
+			var link shareLink
+			decodeJSON(t, resp.Body, &link)
+			if resp.StatusCode != http.StatusCreated || len(link.Code) != 7 || !strings.HasSuffix(link.URL, "/api/s/"+link.Code) {
+				t.Fatalf("expected a share link with a short code, got %d %+v", resp.StatusCode, link)
+			}
+			return link
+		}
+		newsletter, social := createLink("newsletter"), createLink("social")
+		if newsletter.Code == social.Code {
+			t.Fatalf("expected distinct share codes, got %q twice", newsletter.Code)
+		}
+
+		for i := 0; i < 3; i++ {
+			resp = get("/s/" + social.Code)
+			if resp.StatusCode != http.StatusFound || !strings.HasSuffix(resp.Header.Get("Location"), "/events/winter-nights-"+suffix) {
+				t.Fatalf("expected the share link to redirect to the event, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+			}
+		}
+		get("/s/" + newsletter.Code)
+		if resp = get("/s/unknown"); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected an unknown share code to be missing, got %d", resp.StatusCode)
+		}
+
+		var links []shareLink
+		resp = doRequest(t, http.MethodGet, linksPath, nil, headers)
+		decodeJSON(t, resp.Body, &links)
+		resp.Body.Close()
+		if len(links) != 2 || links[0].ID != social.ID || links[0].Clicks != 3 || links[1].Clicks != 1 {
+			t.Fatalf("expected click counts of 3 and 1, got %+v", links)
+		}
+
+		draft := createEvent(t, cookie, map[string]any{
+			"title":     "Unannounced " + suffix,
+			"latitude":  47.37,
+			"longitude": 8.54,
+			"eventDate": "2031-12-02T19:00:00Z",
+			"status":    "draft",
+		})
+		draftLinksPath := "/events/" + draft.ID + "/share-links"
+		resp = doRequest(t, http.MethodPost, draftLinksPath, map[string]any{"label": "preview"}, headers)
+		var preview shareLink
+		decodeJSON(t, resp.Body, &preview)
+		resp.Body.Close()
+		if resp = get("/s/" + preview.Code); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected a share link to a draft to be missing, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, draftLinksPath, nil, headers)
+		decodeJSON(t, resp.Body, &links)
+		resp.Body.Close()
+		if len(links) != 1 || links[0].Clicks != 0 {
+			t.Fatalf("expected a visit of a draft's share link not to count, got %+v", links)
+		}
+
+		resp = doRequest(t, http.MethodDelete, linksPath+"/"+newsletter.ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected the share link to be deleted, got %d", resp.StatusCode)
+		}
+		if resp = get("/s/" + newsletter.Code); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected a deleted share link to stop redirecting, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "related event recommendations", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		newEvent := func(title string, lat, lng float64, date, status string) EventResponse {
+			return createEvent(t, cookie, map[string]any{
+				"title":     title + " " + uuid.NewString()[:8],
+				"latitude":  lat,
+				"longitude": lng,
+				"eventDate": date,
+				"status":    status,
+			})
+		}
+		// Events at a remote spot so those of other subtests do not compete
+		source := newEvent("Ushuaia Harbour Night", -54.80, -68.30, "2031-03-01T19:00:00Z", "published")
+		near := newEvent("Ushuaia Glacier Walk", -54.81, -68.31, "2031-03-02T10:00:00Z", "published")
+		later := newEvent("Tolhuin Lake Day", -54.51, -67.19, "2031-09-01T10:00:00Z", "published")
+		audience := newEvent("Buenos Aires Talk", -34.60, -58.38, "2031-03-01T19:00:00Z", "published")
+		unrelated := newEvent("Rio Gallegos Fair", -51.62, -69.22, "2031-03-01T19:00:00Z", "published")
+		past := newEvent("Ushuaia Old Festival", -54.80, -68.30, "2020-03-01T19:00:00Z", "published")
+		cancelled := newEvent("Ushuaia Cancelled Regatta", -54.80, -68.30, "2031-03-01T12:00:00Z", "published")
+		draft := newEvent("Ushuaia Draft Concert", -54.80, -68.30, "2031-03-01T12:00:00Z", "draft")
+
+		resp := doRequest(t, http.MethodPost, "/events/"+cancelled.ID+"/cancel", map[string]string{"reason": "Storm"}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected cancel to succeed, got %d", resp.StatusCode)
+		}
+
+		email := randomEmail()
+		for i, eventID := range []string{source.ID, audience.ID} {
+			if i == 1 {
+				email = strings.ToUpper(email)
+			}
+			resp = doRequest(t, http.MethodPost, "/participant/event/"+eventID, map[string]any{
+				"fullName":          "Regular Guest",
+				"email":             email,
+				"sourceOfDiscovery": "friends",
+			}, nil)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("expected registration to succeed, got %d", resp.StatusCode)
+			}
+		}
+
+		type relatedEvent struct {
+			EventResponse
+			Score      float64 `json:"score"`
+			DistanceKm float64 `json:"distanceKm"`
+		}
+		related := func(query string) []relatedEvent {
+			resp := doRequest(t, http.MethodGet, "/events/"+source.ID+"/related"+query, nil, nil)
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected related events, got %d", resp.StatusCode)
+			}
+			var events []relatedEvent
+			decodeJSON(t, resp.Body, &events)
+			return events
+		}
+
+		events := related("?limit=20")
+		rank := make(map[string]int, len(events))
+		for i, event := range events {
+			rank[event.ID] = i
+			if event.Score <= 0 {
+				t.Fatalf("expected positive scores, got %+v", event)
+			}
+		}
+		for _, excluded := range []EventResponse{source, unrelated, past, cancelled, draft} {
+			if _, ok := rank[excluded.ID]; ok {
+				t.Fatalf("expected %q to be excluded from related events", excluded.Title)
+			}
+		}
+		for _, included := range []EventResponse{near, later, audience} {
+			if _, ok := rank[included.ID]; !ok {
+				t.Fatalf("expected %q among related events, got %+v", included.Title, events)
+			}
+		}
+		if rank[near.ID] != 0 {
+			t.Fatalf("expected closer and sooner events to rank higher, got %v", rank)
+		}
+		if first := events[0]; first.DistanceKm > 5 {
+			t.Fatalf("expected the nearby event within a few km, got %.1f", first.DistanceKm)
+		}
+
+		if events = related("?limit=1"); len(events) != 1 || events[0].ID != near.ID {
+			t.Fatalf("expected the limit to apply, got %+v", events)
+		}
+
+		resp = doRequest(t, http.MethodPost, "/events/"+near.ID+"/cancel", map[string]string{"reason": "Fog"}, headers)
+		resp.Body.Close()
+		resp = doRequest(t, http.MethodDelete, "/events/"+later.ID, nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected the event to be deleted, got %d", resp.StatusCode)
+		}
+		for _, event := range related("?limit=20") {
+			if event.ID == near.ID || event.ID == later.ID {
+				t.Fatalf("expected cancelled and deleted events to leave the cached recommendations, got %q", event.Title)
+			}
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/"+uuid.NewString()+"/related", nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected unknown events to be missing, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, "/events/"+draft.ID+"/related", nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected drafts to be hidden from anonymous users, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "category counts", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/categories", nil, nil)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "trash and restore event", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Trashable Event",
+			"latitude":  35.68,
+			"longitude": 139.69,
+			"eventDate": time.Now().Add(96 * time.Hour).Format(time.RFC3339),
+			"status":    "published",
+		})
+
+		resp := doRequest(t, http.MethodDelete, fmt.Sprintf("/events/%s", event.ID), nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected delete to succeed, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/trash/events", nil, headers)
+		var trashed []EventResponse
+		decodeJSON(t, resp.Body, &trashed)
+		resp.Body.Close()
+		if len(trashed) != 1 || trashed[0].ID != event.ID {
+			t.Fatalf("expected deleted event in trash, got %d items", len(trashed))
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/trash/events/%s/restore", event.ID), nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected restore to succeed, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, fmt.Sprintf("/events/%s", event.ID), nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected restored event to be visible, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "revision history and revert", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Original Title",
+			"latitude":  52.52,
+			"longitude": 13.4,
+			"eventDate": time.Now().Add(120 * time.Hour).Format(time.RFC3339),
+		})
+
+		resp := doRequest(t, http.MethodPut, fmt.Sprintf("/events/%s", event.ID), map[string]any{"title": "Edited Title"}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected update to succeed, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPut, fmt.Sprintf("/events/%s", event.ID), map[string]any{"title": "Half Saved", "categoryIds": []string{uuid.NewString()}}, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected an unknown category to be rejected, got %d", resp.StatusCode)
+		}
+		resp = doRequest(t, http.MethodGet, fmt.Sprintf("/events/%s", event.ID), nil, headers)
+		var current EventResponse
+		decodeJSON(t, resp.Body, &current)
+		resp.Body.Close()
+		if current.Title != "Edited Title" {
+			t.Fatalf("expected a rejected update to save nothing, got %q", current.Title)
+		}
+
+		resp = doRequest(t, http.MethodGet, fmt.Sprintf("/events/%s/revisions", event.ID), nil, headers)
+		var revisions []struct {
+			Number  int `json:"number"`
+			Changes map[string]struct {
+				From any `json:"from"`
+				To   any `json:"to"`
+			} `json:"changes"`
+		}
+		decodeJSON(t, resp.Body, &revisions)
+		resp.Body.Close()
+
+		if len(revisions) != 2 {
+			t.Fatalf("expected 2 revisions, got %d", len(revisions))
+		}
+		if change, ok := revisions[0].Changes["title"]; !ok || change.From != "Original Title" || change.To != "Edited Title" {
+			t.Fatalf("expected title change in latest revision, got %+v", revisions[0].Changes)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/revisions/1/revert", event.ID), nil, headers)
+		defer resp.Body.Close()
+
+		var reverted EventResponse
+		decodeJSON(t, resp.Body, &reverted)
+		if reverted.Title != "Original Title" {
+			t.Fatalf("expected title to be reverted, got %s", reverted.Title)
+		}
+
+		resp = doRequest(t, http.MethodPut, fmt.Sprintf("/events/%s", event.ID), map[string]any{"tags": []string{"revisioned"}}, headers)
+		var tagged struct {
+			Tags []string `json:"tags"`
+		}
+		decodeJSON(t, resp.Body, &tagged)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || len(tagged.Tags) != 1 {
+			t.Fatalf("expected the tags to be updated, got %d %+v", resp.StatusCode, tagged)
+		}
+		resp = doRequest(t, http.MethodGet, fmt.Sprintf("/events/%s/revisions", event.ID), nil, headers)
+		decodeJSON(t, resp.Body, &revisions)
+		resp.Body.Close()
+		if _, ok := revisions[0].Changes["tags"]; !ok {
+			t.Fatalf("expected a revision recording the tag change, got %+v", revisions[0].Changes)
+		}
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/revisions/1/revert", event.ID), nil, headers)
+		decodeJSON(t, resp.Body, &tagged)
+		resp.Body.Close()
+		if len(tagged.Tags) != 0 {
+			t.Fatalf("expected reverting to restore the tags, got %+v", tagged.Tags)
+		}
+	})
+
+	runSubtest(t, "conditional requests with etag", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Versioned Event",
+			"latitude":  48.85,
+			"longitude": 2.35,
+			"eventDate": time.Now().Add(96 * time.Hour).Format(time.RFC3339),
+		})
+		path := fmt.Sprintf("/events/%s", event.ID)
+
+		resp := doRequest(t, http.MethodGet, path, nil, map[string]string{"Cookie": cookie})
+		resp.Body.Close()
+		etag := resp.Header.Get("ETag")
+		if etag == "" {
+			t.Fatalf("expected ETag header on event")
+		}
+
+		resp = doRequest(t, http.MethodGet, path, nil, map[string]string{"Cookie": cookie, "If-None-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotModified {
+			t.Fatalf("expected status %d, got %d", http.StatusNotModified, resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPut, path, map[string]any{"title": "First Writer"}, map[string]string{"Cookie": cookie, "If-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected update to succeed, got %d", resp.StatusCode)
+		}
+		if resp.Header.Get("ETag") == etag {
+			t.Fatalf("expected ETag to change after update")
+		}
+
+		resp = doRequest(t, http.MethodPut, path, map[string]any{"title": "Second Writer"}, map[string]string{"Cookie": cookie, "If-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusPreconditionFailed {
+			t.Fatalf("expected status %d for stale update, got %d", http.StatusPreconditionFailed, resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodDelete, path, nil, map[string]string{"Cookie": cookie, "If-Match": etag})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusPreconditionFailed {
+			t.Fatalf("expected status %d for stale delete, got %d", http.StatusPreconditionFailed, resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "clone event with shifted date", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		start := time.Date(2031, time.March, 20, 18, 0, 0, 0, time.UTC)
+		resp := doRequest(t, http.MethodPost, "/venues", map[string]any{
+			"name":      "Meetup Loft",
+			"address":   "5 Zeil",
+			"city":      "Frankfurt",
+			"country":   "Germany",
+			"latitude":  50.11,
+			"longitude": 8.68,
+			"timeZone":  "Europe/Berlin",
+		}, map[string]string{"Cookie": cookie})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected venue to be created, got %d", resp.StatusCode)
+		}
+		var venue struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &venue)
+		resp.Body.Close()
+
+		source := createEvent(t, cookie, map[string]any{
+			"title":     "Monthly Meetup",
+			"venueId":   venue.ID,
+			"latitude":  50.11,
+			"longitude": 8.68,
+			"eventDate": start.Format(time.RFC3339),
+			"timeZone":  "Europe/Berlin",
+			"tags":      []string{"meetup"},
+			"status":    "draft",
+		})
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/clone", source.ID), map[string]any{"shiftDays": 7}, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected draft to be hidden from other users, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/clone", source.ID), map[string]any{
+			"title":     "Monthly Meetup (April)",
+			"shiftDays": 14,
+		}, map[string]string{"Cookie": cookie})
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+		}
+
+		var clone EventResponse
+		decodeJSON(t, resp.Body, &clone)
+		if clone.ID == source.ID || clone.Title != "Monthly Meetup (April)" {
+			t.Fatalf("unexpected clone %+v", clone)
+		}
+		// The shift crosses the DST change, so the local wall-clock time is kept
+		if clone.EventDateLocal != "2031-04-03T19:00:00+02:00" {
+			t.Fatalf("expected shifted local start, got %s", clone.EventDateLocal)
+		}
+		if clone.TimeZone != "Europe/Berlin" || len(clone.Tags) != 1 || clone.Tags[0] != "meetup" {
+			t.Fatalf("expected settings to be copied, got %+v", clone)
+		}
+		if clone.VenueID != venue.ID {
+			t.Fatalf("expected clone to keep venue %s, got %q", venue.ID, clone.VenueID)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/events/%s/template", source.ID), map[string]any{"name": "Meetup"}, map[string]string{"Cookie": cookie})
+		var template struct {
+			ID      string `json:"id"`
+			VenueID string `json:"venueId"`
+		}
+		decodeJSON(t, resp.Body, &template)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated || template.VenueID != venue.ID {
+			t.Fatalf("expected template to keep venue %s, got %d %+v", venue.ID, resp.StatusCode, template)
+		}
+
+		fromTemplate := createEvent(t, cookie, map[string]any{
+			"templateId": template.ID,
+			"eventDate":  start.AddDate(0, 2, 0).Format(time.RFC3339),
+		})
+		if fromTemplate.VenueID != venue.ID {
+			t.Fatalf("expected event from template to use venue %s, got %q", venue.ID, fromTemplate.VenueID)
+		}
+	})
+
+	runSubtest(t, "create event from template", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+
+		resp := doRequest(t, http.MethodPost, "/event-templates", map[string]any{
+			"name":      "Workshop",
+			"title":     "Go Workshop",
+			"organizer": "Gophers",
+			"timeZone":  "America/New_York",
+			"latitude":  40.71,
+			"longitude": -74.0,
+			"tags":      []string{"workshop"},
+		}, headers)
+		var template struct {
+			ID string `json:"id"`
+		}
+		decodeJSON(t, resp.Body, &template)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected template to be created, got %d", resp.StatusCode)
+		}
+
+		event := createEvent(t, cookie, map[string]any{
+			"templateId": template.ID,
+			"eventDate":  time.Now().Add(240 * time.Hour).Format(time.RFC3339),
+		})
+		if event.Title != "Go Workshop" || event.TimeZone != "America/New_York" || len(event.Tags) != 1 {
+			t.Fatalf("expected template defaults, got %+v", event)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodPost, "/events", map[string]any{
+			"templateId": template.ID,
+			"eventDate":  time.Now().Add(240 * time.Hour).Format(time.RFC3339),
+		}, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected another user's template to be rejected, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "bulk import events from csv", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		key := "import-" + uuid.NewString()
+		csvBody := "Ref,Name,Start,Lat,Lng,Labels,Kind,Cats\n" +
+			key + ",Imported Concert,2031-05-01 20:00,52.52,13.40,music;live,concert,\n" +
+			",X,not-a-date,1,1,,,\n" +
+			",Unknown Category,2031-05-02 20:00,52.52,13.40,,," + uuid.NewString() + "\n"
+		mapping := "externalId=Ref,title=Name,eventDate=Start,latitude=Lat,longitude=Lng,tags=Labels,eventType=Kind,categoryIds=Cats"
+
+		type importReport struct {
+			DryRun  bool `json:"dryRun"`
+			Created int  `json:"created"`
+			Updated int  `json:"updated"`
+			Failed  int  `json:"failed"`
+			Rows    []struct {
+				Row     int      `json:"row"`
+				Action  string   `json:"action"`
+				EventID string   `json:"eventId"`
+				Errors  []string `json:"errors"`
+			} `json:"rows"`
+		}
+
+		runImport := func(dryRun bool) importReport {
+			path := fmt.Sprintf("%s/events/import?dryRun=%t&mapping=%s", apiBaseURL, dryRun, url.QueryEscape(mapping))
+			req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(csvBody))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "text/csv")
+			req.Header.Set("Cookie", cookie)
+
+			resp, err := testClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+
+			var report importReport
+			decodeJSON(t, resp.Body, &report)
+			return report
+		}
+
+		report := runImport(true)
+		if !report.DryRun || report.Created != 1 || report.Failed != 2 || report.Rows[0].EventID != "" {
+			t.Fatalf("unexpected dry run report %+v", report)
+		}
+		if report.Rows[1].Action != "error" || len(report.Rows[1].Errors) == 0 {
+			t.Fatalf("expected row 2 to report errors, got %+v", report.Rows[1])
+		}
+		if report.Rows[2].Action != "error" {
+			t.Fatalf("expected the dry run to reject an unknown category, got %+v", report.Rows[2])
+		}
+
+		report = runImport(false)
+		if report.Created != 1 || report.Rows[0].EventID == "" {
+			t.Fatalf("expected first import to create the event, got %+v", report)
+		}
+		eventID := report.Rows[0].EventID
+
+		csvBody = strings.Replace(csvBody, ",concert,", ",festival,", 1)
+		report = runImport(false)
+		if report.Updated != 1 || report.Rows[0].EventID != eventID {
+			t.Fatalf("expected re-import to update event %s, got %+v", eventID, report)
+		}
+		resp := doRequest(t, http.MethodGet, "/events/"+eventID, nil, nil)
+		var updated EventResponse
+		decodeJSON(t, resp.Body, &updated)
+		resp.Body.Close()
+		if updated.EventType != "festival" {
+			t.Fatalf("expected re-import to update the event type, got %q", updated.EventType)
+		}
+
+		oversized := "Ref,Name,Start,Lat,Lng\n" + strings.Repeat("too-big,Padding Row,2031-01-01 10:00,1,1\n", (10<<20)/40+1)
+		path := fmt.Sprintf("%s/events/import?dryRun=true&mapping=%s", apiBaseURL, url.QueryEscape("externalId=Ref,title=Name,eventDate=Start,latitude=Lat,longitude=Lng"))
+		req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(oversized))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Cookie", cookie)
+		resp, err = testClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Fatalf("expected an oversized file to be rejected, got %d", resp.StatusCode)
+		}
+	})
+
+	runSubtest(t, "export events and participants", func(t *testing.T) {
+		cookie := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		headers := map[string]string{"Cookie": cookie}
+		tag := "export-" + uuid.NewString()[:8]
+		event := createEvent(t, cookie, map[string]any{
+			"title":     "Exported Event",
+			"latitude":  41.9,
+			"longitude": 12.5,
+			"eventDate": "2031-06-01T18:00:00Z",
+			"status":    "published",
+			"tags":      []string{tag},
+		})
+
+		resp := doRequest(t, http.MethodGet, "/events/export?format=csv&tag="+tag, nil, nil)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+			t.Fatalf("expected csv export, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[1], event.ID+",Exported Event,") {
+			t.Fatalf("expected header and one event row, got %q", body)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/events/export?format=pdf", nil, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected unknown format to be rejected, got %d", resp.StatusCode)
+		}
+
+		other := loginAndGetCookie(t, randomEmail(), "StrongPassw0rd!")
+		resp = doRequest(t, http.MethodGet, fmt.Sprintf("/participant/event/%s/export", event.ID), nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected other users to be forbidden, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodPost, fmt.Sprintf("/exports/participants/%s?format=ndjson", event.ID), nil, headers)
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected export job to be accepted, got %d", resp.StatusCode)
+		}
+		var job struct {
+			ID          string `json:"id"`
+			Status      string `json:"status"`
+			DownloadURL string `json:"downloadUrl"`
+		}
+		decodeJSON(t, resp.Body, &job)
+		resp.Body.Close()
+
+		for i := 0; i < 50 && job.Status != "completed" && job.Status != "failed"; i++ {
+			time.Sleep(100 * time.Millisecond)
+			resp = doRequest(t, http.MethodGet, "/exports/"+job.ID, nil, headers)
+			decodeJSON(t, resp.Body, &job)
+			resp.Body.Close()
+		}
+		if job.Status != "completed" || job.DownloadURL == "" {
+			t.Fatalf("expected export job to complete, got %+v", job)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/exports/"+job.ID+"/download", nil, map[string]string{"Cookie": other})
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected export to be private, got %d", resp.StatusCode)
+		}
+
+		resp = doRequest(t, http.MethodGet, "/exports/"+job.ID+"/download", nil, headers)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Fatalf("expected ndjson download, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	})
+
 	runSubtest(t, "create event requires auth", func(t *testing.T) {
 		resetCookies(t)
 		payload := map[string]any{
@@ -40,8 +40,40 @@ var (
 	subtestsFailed    atomic.Int32
 	resultsMu         sync.Mutex
 	subtestResults    []testResult
+	mailer            = &recordingMailer{}
 )
 
+// recordingMailer keeps sent emails so tests can follow links such as invitations
+type recordingMailer struct {
+	mu     sync.Mutex
+	emails []sentEmail
+}
+
+type sentEmail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+func (m *recordingMailer) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails = append(m.emails, sentEmail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// lastEmailTo returns the most recent email sent to the address
+func (m *recordingMailer) lastEmailTo(to string) (sentEmail, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.emails) - 1; i >= 0; i-- {
+		if strings.EqualFold(m.emails[i].To, to) {
+			return m.emails[i], true
+		}
+	}
+	return sentEmail{}, false
+}
+
 type testResult struct {
 	Name   string `json:"name"`
 	Passed bool   `json:"passed"`
@@ -141,7 +173,7 @@ func TestMain(m *testing.M) {
 	}
 
 	// Auto-migrate the schema to ensure tables exist
-	if err := db.AutoMigrate(&models.Role{}, &models.User{}, &models.Event{}, &models.Participant{}, &models.Image{}, &models.Session{}); err != nil {
+	if err := db.AutoMigrate(&models.Role{}, &models.User{}, &models.Venue{}, &models.OrganizerProfile{}, &models.Event{}, &models.Participant{}, &models.Image{}, &models.Session{}, &models.Category{}, &models.Tag{}, &models.EventRevision{}, &models.EventTemplate{}, &models.ExportJob{}, &models.AgendaTrack{}, &models.Speaker{}, &models.AgendaSession{}, &models.PersonalAgendaItem{}, &models.TicketType{}, &models.Order{}, &models.OrderItem{}, &models.PromoCode{}, &models.PromoRedemption{}, &models.Hold{}, &models.CheckIn{}, &models.Collaborator{}, &models.EventSlug{}, &models.ShareLink{}, &models.DataMigration{}); err != nil {
 		panic(fmt.Sprintf("failed to automigrate: %v", err))
 	}
 
@@ -151,12 +183,48 @@ func TestMain(m *testing.M) {
 	eventRepo := repositories.NewEventRepository(db)
 	participantRepo := repositories.NewParticipantRepository(db)
 	imageRepo := repositories.NewImageRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	revisionRepo := repositories.NewEventRevisionRepository(db)
+	templateRepo := repositories.NewEventTemplateRepository(db)
+	venueRepo := repositories.NewVenueRepository(db)
+	organizerRepo := repositories.NewOrganizerRepository(db)
+	ticketTypeRepo := repositories.NewTicketTypeRepository(db)
+	holdRepo := repositories.NewHoldRepository(db)
+	checkInRepo := repositories.NewCheckInRepository(db)
+	collaboratorRepo := repositories.NewCollaboratorRepository(db)
+	shareLinkRepo := repositories.NewShareLinkRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	promoCodeRepo := repositories.NewPromoCodeRepository(db)
+	agendaRepo := repositories.NewAgendaRepository(db)
+	exportJobRepo := repositories.NewExportJobRepository(db)
 
 	// Set up services
 	authService := services.NewAuthService(userRepo, sessionRepo, cfg.Auth.JWTExpiration)
-	eventService := services.NewEventService(eventRepo, imageRepo)
-	participantService := services.NewParticipantService(participantRepo, eventRepo)
+	eventAccess := services.NewEventAccess(eventRepo, collaboratorRepo)
+	eventService := services.NewEventService(eventRepo, imageRepo, categoryRepo, tagRepo, revisionRepo, templateRepo, venueRepo, organizerRepo)
+	templateService := services.NewEventTemplateService(templateRepo, eventRepo, categoryRepo, imageRepo, venueRepo, eventAccess)
+	importService := services.NewEventImportService(eventRepo, eventService)
+	ticketSigner := services.NewTicketSigner(cfg.Ticket.SigningSecret)
+	participantService := services.NewParticipantService(participantRepo, eventRepo, ticketTypeRepo, holdRepo, ticketSigner, eventAccess)
 	imageService := services.NewImageService(imageRepo)
+	categoryService := services.NewCategoryService(categoryRepo, tagRepo)
+	venueService := services.NewVenueService(venueRepo)
+	organizerService := services.NewOrganizerService(organizerRepo, eventRepo, userRepo, imageRepo)
+	agendaService := services.NewAgendaService(agendaRepo, eventRepo, participantRepo, imageRepo, eventAccess)
+	ticketTypeService := services.NewTicketTypeService(ticketTypeRepo, eventRepo, eventAccess)
+	fakePaymentProvider := services.NewFakePaymentProvider(cfg.Payment.WebhookSecret, apiBaseURL+"/payments/webhook/fake", 100*time.Millisecond)
+	promoCodeService := services.NewPromoCodeService(promoCodeRepo, eventRepo, ticketTypeRepo, eventAccess)
+	orderService := services.NewOrderService(orderRepo, eventRepo, ticketTypeRepo, promoCodeRepo, fakePaymentProvider, eventAccess)
+	holdService := services.NewHoldService(holdRepo, eventRepo, ticketTypeRepo, orderRepo, cfg.Hold.TTL)
+	ticketService := services.NewTicketService(participantRepo, eventRepo, ticketTypeRepo, orderRepo, checkInRepo, ticketSigner, eventAccess)
+	badgeService := services.NewBadgeService(ticketService, participantRepo, eventRepo, ticketTypeRepo, ticketSigner, eventAccess)
+	trashService := services.NewTrashService(eventRepo, participantRepo, cfg.Trash.Retention, eventAccess)
+	exportService := services.NewExportService(eventRepo, participantRepo, exportJobRepo, filepath.Join(os.TempDir(), "eventmaster-e2e-exports"), cfg.Export.Retention, eventAccess)
+	collaboratorService := services.NewCollaboratorService(collaboratorRepo, eventRepo, userRepo, eventAccess, mailer, cfg.Server.PublicURL+"/invitations/")
+	shareLinkService := services.NewShareLinkService(shareLinkRepo, eventRepo, eventAccess)
+	// Cached, so recommendations served after an event is cancelled or trashed are checked
+	relatedEventService := services.NewRelatedEventService(eventRepo, eventAccess, time.Hour)
 	fileService := services.NewFileService(imageRepo, "./uploads", "/uploads")
 	systemUserID, err := services.EnsureTicketmasterSystemUser(userRepo)
 	if err != nil {
@@ -165,6 +233,9 @@ func TestMain(m *testing.M) {
 
 	ticketmasterService := services.NewTicketmasterService(
 		eventRepo,
+		categoryRepo,
+		venueRepo,
+		organizerRepo,
 		imageService,
 		participantService,
 		cfg.Ticketmaster.APIKey,
@@ -174,12 +245,31 @@ func TestMain(m *testing.M) {
 	serverConfig := server.Config{
 		Port:              cfg.Server.Port,
 		SessionCookieName: cfg.Server.SessionCookieName,
+		PublicURL:         cfg.Server.PublicURL,
+		RequireIfMatch:    cfg.Server.RequireIfMatch,
 	}
 
-	srv = server.NewServer(authService, serverConfig)
+	srv = server.NewServer(authService, eventAccess, serverConfig)
 	srv.RegisterEventHandlers(eventService)
 	srv.RegisterParticipantHandlers(participantService)
 	srv.RegisterFileHandlers(fileService)
+	srv.RegisterCategoryHandlers(categoryService)
+	srv.RegisterTrashHandlers(trashService)
+	srv.RegisterTemplateHandlers(templateService)
+	srv.RegisterImportHandlers(importService)
+	srv.RegisterExportHandlers(exportService)
+	srv.RegisterVenueHandlers(venueService)
+	srv.RegisterOrganizerHandlers(organizerService)
+	srv.RegisterAgendaHandlers(agendaService)
+	srv.RegisterTicketTypeHandlers(ticketTypeService)
+	srv.RegisterOrderHandlers(orderService, fakePaymentProvider)
+	srv.RegisterPromoCodeHandlers(promoCodeService)
+	srv.RegisterHoldHandlers(holdService)
+	srv.RegisterTicketHandlers(ticketService)
+	srv.RegisterBadgeHandlers(badgeService)
+	srv.RegisterCollaboratorHandlers(collaboratorService)
+	srv.RegisterShareLinkHandlers(shareLinkService)
+	srv.RegisterRelatedEventHandlers(relatedEventService)
 
 	srvCtx, cancel := context.WithCancel(context.Background())
 	cancelFn = cancel
@@ -191,6 +281,7 @@ func TestMain(m *testing.M) {
 	}()
 
 	go ticketmasterService.StartScheduler(srvCtx, time.Hour)
+	holdService.StartReaper(srvCtx, 200*time.Millisecond)
 
 	waitForServer(serverURL)
 
@@ -269,9 +360,21 @@ type EventListResponse struct {
 }
 
 type EventResponse struct {
-	ID        string     `json:"id"`
-	Title     string     `json:"title"`
-	EventDate *time.Time `json:"eventDate"`
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Slug           string     `json:"slug"`
+	EventDate      *time.Time `json:"eventDate"`
+	EventDateLocal string     `json:"eventDateLocal"`
+	TimeZone       string     `json:"timeZone"`
+	Status         string     `json:"status"`
+	StatusReason   string     `json:"statusReason"`
+	Tags           []string   `json:"tags"`
+	Location       string     `json:"location"`
+	VenueID        string     `json:"venueId"`
+	Latitude       float64    `json:"latitude"`
+	Organizer      string     `json:"organizer"`
+	OrganizerID    string     `json:"organizerId"`
+	EventType      string     `json:"eventType"`
 }
 
 type ParticipantResponse struct {
@@ -431,3 +534,19 @@ func registerParticipant(t *testing.T, eventID, fullName, email string) Particip
 	decodeJSON(t, resp.Body, &participant)
 	return participant
 }
+
+func createEvent(t *testing.T, cookie string, payload map[string]any) EventResponse {
+	t.Helper()
+
+	resp := doRequest(t, http.MethodPost, "/events", payload, map[string]string{"Cookie": cookie})
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusCreated, resp.StatusCode, string(body))
+	}
+
+	var event EventResponse
+	decodeJSON(t, resp.Body, &event)
+	return event
+}
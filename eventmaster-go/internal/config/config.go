@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +15,12 @@ type Config struct {
 	Server     ServerConfig
 	Auth       AuthConfig
 	Ticketmaster TicketmasterConfig
+	Trash      TrashConfig
+	Export     ExportConfig
+	Payment    PaymentConfig
+	Hold       HoldConfig
+	Ticket     TicketConfig
+	Related    RelatedConfig
 }
 
 type DBConfig struct {
@@ -28,6 +35,9 @@ type DBConfig struct {
 type ServerConfig struct {
 	Port             string
 	SessionCookieName string
+	PublicURL        string
+	// RequireIfMatch rejects event updates and deletes sent without an If-Match header
+	RequireIfMatch   bool
 }
 
 type AuthConfig struct {
@@ -42,6 +52,49 @@ type TicketmasterConfig struct {
 	APIKey string
 }
 
+type TrashConfig struct {
+	// Retention is how long soft-deleted items are kept before being purged; zero disables purging
+	Retention time.Duration
+}
+
+type ExportConfig struct {
+	// Dir is where asynchronous export jobs write their files
+	Dir string
+	// Retention is how long finished export files stay downloadable
+	Retention time.Duration
+}
+
+type PaymentConfig struct {
+	// Provider names the payment provider; only "fake" is available so far
+	Provider string
+	// WebhookSecret signs the webhooks of the fake provider
+	WebhookSecret string
+	// WebhookURL is where the fake provider posts payment outcomes
+	WebhookURL string
+	// FakeDelay is how long the fake provider waits before sending a webhook
+	FakeDelay time.Duration
+}
+
+type HoldConfig struct {
+	// TTL is how long a ticket hold or a pending order keeps its tickets before they are released
+	TTL time.Duration
+	// ReapInterval is how often expired holds and pending orders are released
+	ReapInterval time.Duration
+}
+
+type TicketConfig struct {
+	// SigningSecret signs the ticket codes shown as QR codes; changing it invalidates issued tickets
+	SigningSecret string
+	// FontFile and BoldFontFile replace the embedded badge font, e.g. with one covering CJK
+	FontFile     string
+	BoldFontFile string
+}
+
+type RelatedConfig struct {
+	// CacheTTL is how long the related events computed for an event are reused
+	CacheTTL time.Duration
+}
+
 // LoadConfig loads configuration from environment variables and .env file
 func LoadConfig(envPath string) (*Config, error) {
 	// First try to load from the current directory
@@ -67,6 +120,8 @@ func LoadConfig(envPath string) (*Config, error) {
 		Server: ServerConfig{
 			Port:             getEnv("SERVER_PORT", "3000"),
 			SessionCookieName: getEnv("SESSION_ID", "SessionID"),
+			PublicURL:        getEnv("PUBLIC_URL", "http://localhost:3001"),
+			RequireIfMatch:   getEnvBool("REQUIRE_IF_MATCH", false),
 		},
 		Auth: AuthConfig{
 			JWTSecret:     getEnv("JWT_SECRET", "your-secret-key-here"),
@@ -78,6 +133,31 @@ func LoadConfig(envPath string) (*Config, error) {
 		Ticketmaster: TicketmasterConfig{
 			APIKey: tmKey,
 		},
+		Trash: TrashConfig{
+			Retention: getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		},
+		Export: ExportConfig{
+			Dir:       getEnv("EXPORT_DIR", "./exports"),
+			Retention: getEnvDuration("EXPORT_RETENTION", 24*time.Hour),
+		},
+		Payment: PaymentConfig{
+			Provider:      getEnv("PAYMENT_PROVIDER", "fake"),
+			WebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", "fake-webhook-secret"),
+			WebhookURL:    getEnv("PAYMENT_WEBHOOK_URL", "http://localhost:3000/api/payments/webhook/fake"),
+			FakeDelay:     getEnvDuration("PAYMENT_FAKE_DELAY", 2*time.Second),
+		},
+		Hold: HoldConfig{
+			TTL:          getEnvDuration("HOLD_TTL", 10*time.Minute),
+			ReapInterval: getEnvDuration("HOLD_REAP_INTERVAL", 30*time.Second),
+		},
+		Ticket: TicketConfig{
+			SigningSecret: getEnv("TICKET_SIGNING_SECRET", "ticket-signing-secret"),
+			FontFile:      getEnv("BADGE_FONT_FILE", ""),
+			BoldFontFile:  getEnv("BADGE_BOLD_FONT_FILE", ""),
+		},
+		Related: RelatedConfig{
+			CacheTTL: getEnvDuration("RELATED_CACHE_TTL", 10*time.Minute),
+		},
 	}
 
 	// Validate required configurations
@@ -95,3 +175,33 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvBool parses a boolean from an environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("WARNING: invalid %s=%q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvDuration parses a Go duration from an environment variable or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("WARNING: invalid %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
@@ -85,9 +85,13 @@ func NewDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to create database: %w", err)
 	}
 
-	// Now connect to the new database
+	return Open(cfg)
+}
+
+// Open connects to an existing database without recreating it
+func Open(cfg *Config) (*gorm.DB, error) {
 	dsn := cfg.ConnectionString()
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -0,0 +1,252 @@
+// Package importer reads event rows from CSV or JSON files and maps their
+// columns onto event fields.
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format identifies the encoding of an import file
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ListSeparator splits multi-valued fields such as tags inside a single cell
+const ListSeparator = ";"
+
+// Fields lists the event fields a file can provide, by their API names
+var Fields = []string{
+	"externalId",
+	"templateId",
+	"title",
+	"description",
+	"organizer",
+	"eventDate",
+	"timeZone",
+	"latitude",
+	"longitude",
+	"location",
+	"venueId",
+	"eventType",
+	"status",
+	"categoryIds",
+	"tags",
+}
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format, expected csv or json")
+	ErrTooManyRows   = errors.New("import file has too many rows")
+)
+
+// Mapping maps event fields to the column (CSV header or JSON key) holding them.
+// Fields without an entry are read from a column of the same name.
+type Mapping map[string]string
+
+// Record holds the mapped values of one data row. Row is 1-based and does not
+// count the CSV header line.
+type Record struct {
+	Row    int
+	Values map[string]string
+}
+
+// ParseMapping parses a spec of the form "title=Event Name,eventDate=Start" or
+// a JSON object with the same pairs
+func ParseMapping(spec string) (Mapping, error) {
+	mapping := Mapping{}
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return mapping, nil
+	}
+
+	if strings.HasPrefix(spec, "{") {
+		if err := json.Unmarshal([]byte(spec), &mapping); err != nil {
+			return nil, fmt.Errorf("invalid mapping: %w", err)
+		}
+	} else {
+		for _, pair := range strings.Split(spec, ",") {
+			field, column, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid mapping entry %q, expected field=column", pair)
+			}
+			mapping[strings.TrimSpace(field)] = strings.TrimSpace(column)
+		}
+	}
+
+	for field := range mapping {
+		if !isField(field) {
+			return nil, fmt.Errorf("invalid mapping: unknown field %q", field)
+		}
+	}
+	return mapping, nil
+}
+
+// DetectFormat picks the format from an explicit value, then the file name, then the content type
+func DetectFormat(explicit, filename, contentType string) (Format, error) {
+	candidates := []string{
+		strings.ToLower(explicit),
+		strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), "."),
+	}
+	if mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";"); mediaType != "" {
+		candidates = append(candidates, mediaType)
+	}
+
+	for _, candidate := range candidates {
+		switch candidate {
+		case "csv", "text/csv":
+			return FormatCSV, nil
+		case "json", "application/json":
+			return FormatJSON, nil
+		}
+	}
+	return "", ErrUnknownFormat
+}
+
+// Read decodes up to maxRows records from r. A maxRows of zero means no limit.
+func Read(r io.Reader, format Format, mapping Mapping, maxRows int) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, mapping, maxRows)
+	case FormatJSON:
+		return readJSON(r, mapping, maxRows)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func readCSV(r io.Reader, mapping Mapping, maxRows int) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel likes to prefix UTF-8 files with a byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	records := make([]Record, 0)
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		if maxRows > 0 && len(records) == maxRows {
+			return nil, ErrTooManyRows
+		}
+
+		values := make(map[string]string, len(Fields))
+		for _, field := range Fields {
+			index, ok := columns[mapping.column(field)]
+			if ok && index < len(cells) {
+				values[field] = strings.TrimSpace(cells[index])
+			}
+		}
+		records = append(records, Record{Row: len(records) + 1, Values: values})
+	}
+	return records, nil
+}
+
+func readJSON(r io.Reader, mapping Mapping, maxRows int) ([]Record, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var objects []map[string]interface{}
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, fmt.Errorf("invalid json, expected an array of objects: %w", err)
+	}
+	if maxRows > 0 && len(objects) > maxRows {
+		return nil, ErrTooManyRows
+	}
+
+	records := make([]Record, len(objects))
+	for i, object := range objects {
+		values := make(map[string]string, len(Fields))
+		for _, field := range Fields {
+			if value, ok := object[mapping.column(field)]; ok {
+				values[field] = stringify(value)
+			}
+		}
+		records[i] = Record{Row: i + 1, Values: values}
+	}
+	return records, nil
+}
+
+// stringify flattens a JSON value into the cell form used by CSV files
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if part := stringify(item); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, ListSeparator)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// SplitList splits a multi-valued cell, dropping blank entries
+func SplitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m Mapping) column(field string) string {
+	if column, ok := m[field]; ok {
+		return column
+	}
+	return field
+}
+
+func isField(name string) bool {
+	for _, field := range Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
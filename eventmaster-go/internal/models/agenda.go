@@ -0,0 +1,162 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AgendaTrack groups the sessions of an event into a themed stream, such as "Backend" or "Workshops"
+type AgendaTrack struct {
+	Base
+	EventID  string `json:"eventId" gorm:"type:uuid;not null;index"`
+	Name     string `json:"name" gorm:"size:255;not null"`
+	Color    string `json:"color" gorm:"size:7"`
+	Position int    `json:"position" gorm:"not null;default:0"`
+}
+
+// Speaker is a person presenting one or more sessions of an event
+type Speaker struct {
+	Base
+	EventID  string  `json:"eventId" gorm:"type:uuid;not null;index"`
+	Name     string  `json:"name" gorm:"size:255;not null"`
+	Headline string  `json:"headline" gorm:"size:255"`
+	Bio      string  `json:"bio" gorm:"type:text"`
+	PhotoID  *string `json:"photoId" gorm:"type:uuid"`
+	Photo    *Image  `json:"-" gorm:"foreignKey:PhotoID"`
+}
+
+// AgendaSession is a scheduled item of an event's agenda. Sessions sharing a room may not overlap.
+type AgendaSession struct {
+	Base
+	EventID     string       `json:"eventId" gorm:"type:uuid;not null;index"`
+	TrackID     *string      `json:"trackId" gorm:"type:uuid;index"`
+	Track       *AgendaTrack `json:"-" gorm:"foreignKey:TrackID"`
+	Title       string       `json:"title" gorm:"size:255;not null"`
+	Description string       `json:"description" gorm:"type:text"`
+	Room        string       `json:"room" gorm:"size:255"`
+	StartsAt    time.Time    `json:"startsAt" gorm:"not null;index"`
+	EndsAt      time.Time    `json:"endsAt" gorm:"not null"`
+	Speakers    []Speaker    `json:"-" gorm:"many2many:agenda_session_speakers;"`
+}
+
+// PersonalAgendaItem records a session a participant added to their own agenda
+type PersonalAgendaItem struct {
+	Base
+	ParticipantID string `json:"participantId" gorm:"type:uuid;not null;uniqueIndex:idx_personal_agenda_item"`
+	SessionID     string `json:"sessionId" gorm:"type:uuid;not null;uniqueIndex:idx_personal_agenda_item;index"`
+}
+
+// AgendaTrackResponse represents a track sent to clients
+type AgendaTrackResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color,omitempty"`
+	Position int    `json:"position"`
+}
+
+// SpeakerResponse represents a speaker sent to clients
+type SpeakerResponse struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Headline string         `json:"headline,omitempty"`
+	Bio      string         `json:"bio,omitempty"`
+	PhotoID  *string        `json:"photoId,omitempty"`
+	Photo    *ImageResponse `json:"photo,omitempty"`
+}
+
+// AgendaSessionResponse represents a session sent to clients
+type AgendaSessionResponse struct {
+	ID          string               `json:"id"`
+	EventID     string               `json:"eventId"`
+	Title       string               `json:"title"`
+	Description string               `json:"description,omitempty"`
+	Room        string               `json:"room,omitempty"`
+	StartsAt    time.Time            `json:"startsAt"`
+	EndsAt      time.Time            `json:"endsAt"`
+	TrackID     *string              `json:"trackId,omitempty"`
+	Track       *AgendaTrackResponse `json:"track,omitempty"`
+	Speakers    []*SpeakerResponse   `json:"speakers"`
+}
+
+// ToResponse converts AgendaTrack to AgendaTrackResponse
+func (t *AgendaTrack) ToResponse() *AgendaTrackResponse {
+	return &AgendaTrackResponse{
+		ID:       t.ID,
+		Name:     t.Name,
+		Color:    t.Color,
+		Position: t.Position,
+	}
+}
+
+// ToResponse converts Speaker to SpeakerResponse
+func (s *Speaker) ToResponse() *SpeakerResponse {
+	var photo *ImageResponse
+	if s.Photo != nil {
+		photo = s.Photo.ToResponse()
+	}
+
+	return &SpeakerResponse{
+		ID:       s.ID,
+		Name:     s.Name,
+		Headline: s.Headline,
+		Bio:      s.Bio,
+		PhotoID:  s.PhotoID,
+		Photo:    photo,
+	}
+}
+
+// ToResponse converts AgendaSession to AgendaSessionResponse
+func (s *AgendaSession) ToResponse() *AgendaSessionResponse {
+	var track *AgendaTrackResponse
+	if s.Track != nil {
+		track = s.Track.ToResponse()
+	}
+
+	speakers := make([]*SpeakerResponse, len(s.Speakers))
+	for i := range s.Speakers {
+		speakers[i] = s.Speakers[i].ToResponse()
+	}
+
+	return &AgendaSessionResponse{
+		ID:          s.ID,
+		EventID:     s.EventID,
+		Title:       s.Title,
+		Description: s.Description,
+		Room:        s.Room,
+		StartsAt:    s.StartsAt.UTC(),
+		EndsAt:      s.EndsAt.UTC(),
+		TrackID:     s.TrackID,
+		Track:       track,
+		Speakers:    speakers,
+	}
+}
+
+// Overlaps reports whether the session shares any time with the interval [start, end)
+func (s *AgendaSession) Overlaps(start, end time.Time) bool {
+	return s.StartsAt.Before(end) && start.Before(s.EndsAt)
+}
+
+// BeforeCreate is a hook that runs before creating a track
+func (t *AgendaTrack) BeforeCreate(tx *gorm.DB) error {
+	t.ID = GenerateID()
+	return nil
+}
+
+// BeforeCreate is a hook that runs before creating a speaker
+func (s *Speaker) BeforeCreate(tx *gorm.DB) error {
+	s.ID = GenerateID()
+	return nil
+}
+
+// BeforeCreate is a hook that runs before creating a session
+func (s *AgendaSession) BeforeCreate(tx *gorm.DB) error {
+	s.ID = GenerateID()
+	return nil
+}
+
+// BeforeCreate is a hook that runs before creating a personal agenda item
+func (p *PersonalAgendaItem) BeforeCreate(tx *gorm.DB) error {
+	p.ID = GenerateID()
+	return nil
+}
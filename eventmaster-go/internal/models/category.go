@@ -0,0 +1,74 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Category is a node in the hierarchical event taxonomy (e.g. Music > Rock > Alternative).
+// Slug holds the full path of the node so descendants share its prefix.
+type Category struct {
+	Base
+	Name     string      `json:"name" gorm:"size:100;not null"`
+	Slug     string      `json:"slug" gorm:"size:255;not null;uniqueIndex"`
+	ParentID *string     `json:"parentId" gorm:"type:uuid;index"`
+	Parent   *Category   `json:"-" gorm:"foreignKey:ParentID"`
+	Children []*Category `json:"-" gorm:"foreignKey:ParentID"`
+	Events   []*Event    `json:"-" gorm:"many2many:event_categories;"`
+}
+
+// Tag is a free-form label attached to events
+type Tag struct {
+	Base
+	Name   string   `json:"name" gorm:"size:100;not null"`
+	Slug   string   `json:"slug" gorm:"size:100;not null;uniqueIndex"`
+	Events []*Event `json:"-" gorm:"many2many:event_tags;"`
+}
+
+// CategoryResponse represents a category sent to clients
+type CategoryResponse struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Slug       string              `json:"slug"`
+	ParentID   *string             `json:"parentId,omitempty"`
+	EventCount *int64              `json:"eventCount,omitempty"`
+	Children   []*CategoryResponse `json:"children,omitempty"`
+}
+
+// TagResponse represents a tag sent to clients
+type TagResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	EventCount *int64 `json:"eventCount,omitempty"`
+}
+
+// ToResponse converts Category to CategoryResponse
+func (c *Category) ToResponse() *CategoryResponse {
+	return &CategoryResponse{
+		ID:       c.ID,
+		Name:     c.Name,
+		Slug:     c.Slug,
+		ParentID: c.ParentID,
+	}
+}
+
+// ToResponse converts Tag to TagResponse
+func (t *Tag) ToResponse() *TagResponse {
+	return &TagResponse{
+		ID:   t.ID,
+		Name: t.Name,
+		Slug: t.Slug,
+	}
+}
+
+// BeforeCreate is a hook that runs before creating a category
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	c.ID = GenerateID()
+	return nil
+}
+
+// BeforeCreate is a hook that runs before creating a tag
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	t.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CheckIn records a participant's ticket being scanned at the door. A participant is checked
+// in at most once; when kiosks that scanned offline disagree, the earliest scan is kept.
+type CheckIn struct {
+	Base
+	EventID       string    `json:"eventId" gorm:"type:uuid;not null;index"`
+	ParticipantID string    `json:"participantId" gorm:"type:uuid;not null;uniqueIndex"`
+	CheckedInAt   time.Time `json:"checkedInAt" gorm:"not null"`
+	// ScannedBy is the user who scanned the ticket, or who uploaded the scans of a kiosk
+	ScannedBy string `json:"scannedBy" gorm:"type:uuid;not null"`
+	// DeviceID names the kiosk that scanned the ticket offline; empty for online scans
+	DeviceID string `json:"deviceId" gorm:"size:100;not null;default:''"`
+}
+
+// CheckInResponse represents a check-in sent to door staff
+type CheckInResponse struct {
+	ID            string    `json:"id"`
+	EventID       string    `json:"eventId"`
+	ParticipantID string    `json:"participantId"`
+	CheckedInAt   time.Time `json:"checkedInAt"`
+	ScannedBy     string    `json:"scannedBy"`
+	DeviceID      string    `json:"deviceId,omitempty"`
+}
+
+// ToResponse converts CheckIn to CheckInResponse
+func (c *CheckIn) ToResponse() *CheckInResponse {
+	return &CheckInResponse{
+		ID:            c.ID,
+		EventID:       c.EventID,
+		ParticipantID: c.ParticipantID,
+		CheckedInAt:   c.CheckedInAt,
+		ScannedBy:     c.ScannedBy,
+		DeviceID:      c.DeviceID,
+	}
+}
+
+// BeforeCreate is a hook that runs before creating a check-in
+func (c *CheckIn) BeforeCreate(tx *gorm.DB) error {
+	c.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CollaboratorRole is what a collaborator may do with an event. Roles are ordered: each one
+// may do everything the roles before it may.
+type CollaboratorRole string
+
+const (
+	// CollaboratorRoleViewer sees the event, its participants and its numbers
+	CollaboratorRoleViewer CollaboratorRole = "viewer"
+	// CollaboratorRoleCheckIn also checks tickets in at the door
+	CollaboratorRoleCheckIn CollaboratorRole = "check_in"
+	// CollaboratorRoleEditor also edits the event, its tickets, agenda and participants
+	CollaboratorRoleEditor CollaboratorRole = "editor"
+	// CollaboratorRoleOwner also deletes the event and manages its collaborators
+	CollaboratorRoleOwner CollaboratorRole = "owner"
+)
+
+var collaboratorRoleRanks = map[CollaboratorRole]int{
+	CollaboratorRoleViewer:  1,
+	CollaboratorRoleCheckIn: 2,
+	CollaboratorRoleEditor:  3,
+	CollaboratorRoleOwner:   4,
+}
+
+// IsValid reports whether r is a known role
+func (r CollaboratorRole) IsValid() bool {
+	_, ok := collaboratorRoleRanks[r]
+	return ok
+}
+
+// Includes reports whether r may do everything required may
+func (r CollaboratorRole) Includes(required CollaboratorRole) bool {
+	return r.IsValid() && collaboratorRoleRanks[r] >= collaboratorRoleRanks[required]
+}
+
+// RolesIncluding lists the roles that may do everything required may
+func RolesIncluding(required CollaboratorRole) []CollaboratorRole {
+	var roles []CollaboratorRole
+	for role := range collaboratorRoleRanks {
+		if role.Includes(required) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// CollaboratorStatus tells whether an invitation has been accepted
+type CollaboratorStatus string
+
+const (
+	CollaboratorStatusInvited CollaboratorStatus = "invited"
+	CollaboratorStatusActive  CollaboratorStatus = "active"
+)
+
+// Collaborator gives a user a role on an event they did not create. Collaborators are invited
+// by email and become active once the invited user accepts; until then UserID is empty and
+// the invitation is found by the hash of the token sent to them. The creator of an event is
+// its owner without a collaborator row.
+type Collaborator struct {
+	Base
+	EventID   string             `json:"eventId" gorm:"type:uuid;not null;uniqueIndex:idx_collaborators_event_email;index"`
+	Email     string             `json:"email" gorm:"size:255;not null;uniqueIndex:idx_collaborators_event_email"`
+	UserID    *string            `json:"userId" gorm:"type:uuid;index"`
+	Role      CollaboratorRole   `json:"role" gorm:"type:varchar(20);not null"`
+	Status    CollaboratorStatus `json:"status" gorm:"type:varchar(20);not null;default:'invited'"`
+	InvitedBy string             `json:"invitedBy" gorm:"type:uuid;not null"`
+	// InviteTokenHash is the SHA-256 of the invitation token; it is cleared on acceptance
+	InviteTokenHash *string    `json:"-" gorm:"size:64;uniqueIndex"`
+	InviteExpiresAt *time.Time `json:"inviteExpiresAt"`
+	AcceptedAt      *time.Time `json:"acceptedAt"`
+}
+
+// CollaboratorResponse represents the collaborator data sent to clients
+type CollaboratorResponse struct {
+	ID              string             `json:"id"`
+	EventID         string             `json:"eventId"`
+	Email           string             `json:"email"`
+	UserID          *string            `json:"userId,omitempty"`
+	Role            CollaboratorRole   `json:"role"`
+	Status          CollaboratorStatus `json:"status"`
+	InvitedBy       string             `json:"invitedBy"`
+	InviteExpiresAt *time.Time         `json:"inviteExpiresAt,omitempty"`
+	AcceptedAt      *time.Time         `json:"acceptedAt,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt"`
+}
+
+// ToResponse converts Collaborator to CollaboratorResponse
+func (c *Collaborator) ToResponse() *CollaboratorResponse {
+	return &CollaboratorResponse{
+		ID:              c.ID,
+		EventID:         c.EventID,
+		Email:           c.Email,
+		UserID:          c.UserID,
+		Role:            c.Role,
+		Status:          c.Status,
+		InvitedBy:       c.InvitedBy,
+		InviteExpiresAt: c.InviteExpiresAt,
+		AcceptedAt:      c.AcceptedAt,
+		CreatedAt:       c.CreatedAt,
+	}
+}
+
+// BeforeCreate is a hook that runs before creating a collaborator
+func (c *Collaborator) BeforeCreate(tx *gorm.DB) error {
+	c.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// DataMigration records a one-time rewrite of stored data that has been applied, so it
+// does not run again on the next start
+type DataMigration struct {
+	Name      string    `gorm:"size:100;primaryKey"`
+	AppliedAt time.Time `gorm:"not null;default:now()"`
+}
@@ -3,45 +3,94 @@ package models
 import (
 	"time"
 
+	"eventmaster-go/pkg/markdown"
+
 	"gorm.io/gorm"
 )
 
+// DefaultTimeZone is used for events created without an explicit zone
+const DefaultTimeZone = "UTC"
+
 // Event represents an event in the system
 type Event struct {
 	Base
 	Title         string     `json:"title" gorm:"not null"`
+	// Slug identifies the event in URLs. It follows the title; slugs it had before redirect here.
+	Slug          *string    `json:"slug" gorm:"size:100;uniqueIndex"`
+	// Description is Markdown; responses also carry it rendered to sanitized HTML
 	Description   string     `json:"description" gorm:"type:text"`
 	Organizer     string     `json:"organizer" gorm:"not null"`
+	// OrganizerID links the event to an organizer profile; Organizer keeps its name for display
+	OrganizerID   *string    `json:"organizerId" gorm:"type:uuid;index"`
+	OrganizerProfile *OrganizerProfile `json:"-" gorm:"foreignKey:OrganizerID"`
 	EventDate     *time.Time `json:"eventDate" gorm:"not null"`
+	TimeZone      string     `json:"timeZone" gorm:"size:64;not null;default:'UTC'"`
 	Latitude      float64    `json:"latitude" gorm:"type:decimal(10,8)"`
 	Longitude     float64    `json:"longitude" gorm:"type:decimal(11,8)"`
 	UserID        string     `json:"userId" gorm:"type:uuid;not null"`
 	User          User       `json:"-" gorm:"foreignKey:UserID"`
 	Images        []Image    `json:"images" gorm:"many2many:event_images;"`
+	Categories    []Category `json:"categories" gorm:"many2many:event_categories;"`
+	Tags          []Tag      `json:"tags" gorm:"many2many:event_tags;"`
+	TicketTypes   []TicketType `json:"-" gorm:"foreignKey:EventID"`
 	Location      string     `json:"location" gorm:"type:text"`
+	// VenueID links the event to a venue; Location and the coordinates are copied from it when linked
+	VenueID       *string    `json:"venueId" gorm:"type:uuid;index"`
+	Venue         *Venue     `json:"-" gorm:"foreignKey:VenueID"`
 	ExternalID    string     `json:"externalId" gorm:"index"`
 	ExternalURL   string     `json:"externalUrl" gorm:"type:text"`
 	EventType     string     `json:"eventType"`
 	IsExternal    bool       `json:"isExternal" gorm:"default:false"`
+	Status        EventStatus `json:"status" gorm:"type:varchar(20);not null;default:'published';index"`
+	StatusReason  string     `json:"statusReason" gorm:"type:text"`
+	PublishedAt   *time.Time `json:"publishedAt"`
+	CancelledAt   *time.Time `json:"cancelledAt"`
+	// TemplateID records the template the event was created from, if any
+	TemplateID    *string    `json:"templateId" gorm:"type:uuid;index"`
+	// Version is incremented on every update and backs the ETag used for optimistic concurrency
+	Version       int        `json:"version" gorm:"not null;default:1"`
 }
 
 // EventResponse represents the event data sent to clients
 type EventResponse struct {
 	ID          string         `json:"id"`
 	Title       string         `json:"title"`
+	Slug        string         `json:"slug,omitempty"`
+	// Description is the Markdown source, kept for older clients; see DescriptionMarkdown
 	Description string         `json:"description"`
+	DescriptionMarkdown string `json:"descriptionMarkdown"`
+	// DescriptionHTML is the description rendered to sanitized HTML, safe to insert as is
+	DescriptionHTML string     `json:"descriptionHtml"`
 	Organizer   string         `json:"organizer"`
+	OrganizerID *string        `json:"organizerId,omitempty"`
+	OrganizerProfile *OrganizerSummary `json:"organizerProfile,omitempty"`
 	EventDate   *time.Time     `json:"eventDate"`
+	EventDateLocal string      `json:"eventDateLocal,omitempty"`
+	TimeZone    string         `json:"timeZone"`
 	Latitude    float64        `json:"latitude"`
 	Longitude   float64        `json:"longitude"`
 	Location    string         `json:"location,omitempty"`
+	VenueID     *string        `json:"venueId,omitempty"`
+	Venue       *VenueResponse `json:"venue,omitempty"`
 	ExternalID  string         `json:"externalId,omitempty"`
 	ExternalURL string         `json:"externalUrl,omitempty"`
 	EventType   string         `json:"eventType,omitempty"`
 	IsExternal  bool           `json:"isExternal"`
+	Status      EventStatus    `json:"status"`
+	StatusReason string        `json:"statusReason,omitempty"`
+	PublishedAt *time.Time     `json:"publishedAt,omitempty"`
+	CancelledAt *time.Time     `json:"cancelledAt,omitempty"`
+	Version     int            `json:"version"`
+	TemplateID  *string        `json:"templateId,omitempty"`
 	CreatedAt   time.Time      `json:"createdAt"`
 	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   *time.Time     `json:"deletedAt,omitempty"`
 	Images      []ImageResponse `json:"images,omitempty"`
+	Categories  []*CategoryResponse `json:"categories,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	TicketTypes []*TicketTypeResponse `json:"ticketTypes,omitempty"`
+	// TicketsAvailable totals the tickets left across sellable types; it is omitted when any is unlimited
+	TicketsAvailable *int      `json:"ticketsAvailable,omitempty"`
 	User        *UserResponse  `json:"user,omitempty"`
 }
 
@@ -52,33 +101,149 @@ func (e *Event) ToResponse() *EventResponse {
 		images[i] = *img.ToResponse()
 	}
 
+	categories := make([]*CategoryResponse, len(e.Categories))
+	for i, category := range e.Categories {
+		categories[i] = category.ToResponse()
+	}
+
+	tags := make([]string, len(e.Tags))
+	for i, tag := range e.Tags {
+		tags[i] = tag.Name
+	}
+
+	ticketTypes := make([]*TicketTypeResponse, len(e.TicketTypes))
+	for i := range e.TicketTypes {
+		ticketTypes[i] = e.TicketTypes[i].ToResponse()
+	}
+
 	var userResp *UserResponse
 	if e.User.ID != "" {
 		userResp = e.User.ToResponse()
 	}
 
+	var venue *VenueResponse
+	if e.Venue != nil {
+		venue = e.Venue.ToResponse()
+	}
+
+	var organizer *OrganizerSummary
+	if e.OrganizerProfile != nil {
+		organizer = e.OrganizerProfile.Summary()
+	}
+
+	var deletedAt *time.Time
+	if e.DeletedAt.Valid {
+		deletedAt = &e.DeletedAt.Time
+	}
+
+	var eventDate *time.Time
+	var eventDateLocal string
+	if e.EventDate != nil {
+		utc := e.EventDate.UTC()
+		eventDate = &utc
+		eventDateLocal = e.LocalEventDate().Format(time.RFC3339)
+	}
+
+	var eventSlug string
+	if e.Slug != nil {
+		eventSlug = *e.Slug
+	}
+
 	return &EventResponse{
 		ID:          e.ID,
 		Title:       e.Title,
+		Slug:        eventSlug,
 		Description: e.Description,
+		DescriptionMarkdown: e.Description,
+		DescriptionHTML: markdown.Render(e.Description),
 		Organizer:   e.Organizer,
-		EventDate:   e.EventDate,
+		OrganizerID: e.OrganizerID,
+		OrganizerProfile: organizer,
+		EventDate:   eventDate,
+		EventDateLocal: eventDateLocal,
+		TimeZone:    e.TimeZoneName(),
 		Latitude:    e.Latitude,
 		Longitude:   e.Longitude,
 		Location:    e.Location,
+		VenueID:     e.VenueID,
+		Venue:       venue,
 		ExternalID:  e.ExternalID,
 		ExternalURL: e.ExternalURL,
 		EventType:   e.EventType,
 		IsExternal:  e.IsExternal,
+		Status:      e.Status,
+		StatusReason: e.StatusReason,
+		PublishedAt: e.PublishedAt,
+		CancelledAt: e.CancelledAt,
+		Version:     e.Version,
+		TemplateID:  e.TemplateID,
 		CreatedAt:   e.CreatedAt,
 		UpdatedAt:   e.UpdatedAt,
+		DeletedAt:   deletedAt,
 		Images:      images,
+		Categories:  categories,
+		Tags:        tags,
+		TicketTypes: ticketTypes,
+		TicketsAvailable: e.TicketsAvailable(),
 		User:        userResp,
 	}
 }
 
+// TicketsAvailable totals the tickets left across the loaded sellable ticket types.
+// It returns nil when there are none or any of them is unlimited.
+func (e *Event) TicketsAvailable() *int {
+	total := 0
+	sellable := false
+	for i := range e.TicketTypes {
+		if e.TicketTypes[i].Informational {
+			continue
+		}
+		available := e.TicketTypes[i].Available()
+		if available == nil {
+			return nil
+		}
+		sellable = true
+		total += *available
+	}
+	if !sellable {
+		return nil
+	}
+	return &total
+}
+
+// TimeZoneName returns the IANA zone of the event, defaulting to UTC
+func (e *Event) TimeZoneName() string {
+	if e.TimeZone == "" {
+		return DefaultTimeZone
+	}
+	return e.TimeZone
+}
+
+// TimeLocation resolves the event's IANA zone, falling back to UTC when it is unknown
+func (e *Event) TimeLocation() *time.Location {
+	loc, err := time.LoadLocation(e.TimeZoneName())
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalEventDate returns the event date as wall-clock time in the event's zone
+func (e *Event) LocalEventDate() time.Time {
+	if e.EventDate == nil {
+		return time.Time{}
+	}
+	return e.EventDate.In(e.TimeLocation())
+}
+
 // BeforeCreate is a hook that runs before creating an event
 func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	e.ID = GenerateID()
+	if e.TimeZone == "" {
+		e.TimeZone = DefaultTimeZone
+	}
+	if e.Version == 0 {
+		e.Version = 1
+	}
 	return nil
 }
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventSnapshot captures the editable fields of an event at a point in time
+type EventSnapshot struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Organizer   string     `json:"organizer"`
+	OrganizerID *string    `json:"organizerId"`
+	EventDate   *time.Time `json:"eventDate"`
+	TimeZone    string     `json:"timeZone"`
+	Latitude    float64    `json:"latitude"`
+	Longitude   float64    `json:"longitude"`
+	Location    string     `json:"location"`
+	VenueID     *string    `json:"venueId"`
+	EventType   string     `json:"eventType"`
+	// CategoryIDs and Tags are sorted. They are nil in revisions recorded before the
+	// taxonomy was tracked; applying such a snapshot leaves the taxonomy as it is.
+	CategoryIDs []string `json:"categoryIds"`
+	Tags        []string `json:"tags"`
+}
+
+// FieldChange records the previous and new value of a single field
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// FieldChanges maps API field names to their change
+type FieldChanges map[string]FieldChange
+
+// EventRevision is an entry in the edit history of an event
+type EventRevision struct {
+	Base
+	EventID      string        `json:"eventId" gorm:"type:uuid;not null;uniqueIndex:idx_event_revision_number"`
+	Number       int           `json:"number" gorm:"not null;uniqueIndex:idx_event_revision_number"`
+	UserID       string        `json:"userId" gorm:"type:uuid"`
+	Changes      FieldChanges  `json:"changes" gorm:"type:jsonb;not null;serializer:json"`
+	Snapshot     EventSnapshot `json:"snapshot" gorm:"type:jsonb;not null;serializer:json"`
+	RevertedFrom *int          `json:"revertedFrom"`
+}
+
+// EventRevisionResponse represents a revision sent to clients
+type EventRevisionResponse struct {
+	ID           string         `json:"id"`
+	EventID      string         `json:"eventId"`
+	Number       int            `json:"number"`
+	UserID       string         `json:"userId,omitempty"`
+	Changes      FieldChanges   `json:"changes"`
+	Snapshot     *EventSnapshot `json:"snapshot,omitempty"`
+	RevertedFrom *int           `json:"revertedFrom,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+}
+
+// Snapshot captures the editable fields of the event
+func (e *Event) Snapshot() EventSnapshot {
+	return EventSnapshot{
+		Title:       e.Title,
+		Description: e.Description,
+		Organizer:   e.Organizer,
+		OrganizerID: e.OrganizerID,
+		EventDate:   e.EventDate,
+		TimeZone:    e.TimeZone,
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		Location:    e.Location,
+		VenueID:     e.VenueID,
+		EventType:   e.EventType,
+		CategoryIDs: e.categoryIDs(),
+		Tags:        e.tagNames(),
+	}
+}
+
+// categoryIDs lists the IDs of the event's categories, sorted and never nil
+func (e *Event) categoryIDs() []string {
+	ids := make([]string, len(e.Categories))
+	for i, category := range e.Categories {
+		ids[i] = category.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// tagNames lists the names of the event's tags, sorted and never nil
+func (e *Event) tagNames() []string {
+	names := make([]string, len(e.Tags))
+	for i, tag := range e.Tags {
+		names[i] = tag.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTo copies the snapshot values onto an event
+func (s EventSnapshot) ApplyTo(e *Event) {
+	e.Title = s.Title
+	e.Description = s.Description
+	e.Organizer = s.Organizer
+	e.OrganizerID = s.OrganizerID
+	e.EventDate = s.EventDate
+	e.TimeZone = s.TimeZone
+	e.Latitude = s.Latitude
+	e.Longitude = s.Longitude
+	e.Location = s.Location
+	e.VenueID = s.VenueID
+	e.EventType = s.EventType
+	// Categories and tags are set by ID and name; the caller resolves them to stored rows
+	if s.CategoryIDs != nil {
+		e.Categories = make([]Category, len(s.CategoryIDs))
+		for i, id := range s.CategoryIDs {
+			e.Categories[i] = Category{Base: Base{ID: id}}
+		}
+	}
+	if s.Tags != nil {
+		e.Tags = make([]Tag, len(s.Tags))
+		for i, name := range s.Tags {
+			e.Tags[i] = Tag{Name: name}
+		}
+	}
+}
+
+// Diff lists the fields whose values differ between s and next
+func (s EventSnapshot) Diff(next EventSnapshot) FieldChanges {
+	changes := FieldChanges{}
+
+	compareString := func(field, from, to string) {
+		if from != to {
+			changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	compareFloat := func(field string, from, to float64) {
+		if from != to {
+			changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+
+	compareString("title", s.Title, next.Title)
+	compareString("description", s.Description, next.Description)
+	compareString("organizer", s.Organizer, next.Organizer)
+	compareString("timeZone", s.TimeZone, next.TimeZone)
+	compareFloat("latitude", s.Latitude, next.Latitude)
+	compareFloat("longitude", s.Longitude, next.Longitude)
+	compareString("location", s.Location, next.Location)
+	compareString("eventType", s.EventType, next.EventType)
+
+	if !sameID(s.OrganizerID, next.OrganizerID) {
+		changes["organizerId"] = FieldChange{From: s.OrganizerID, To: next.OrganizerID}
+	}
+	if !sameID(s.VenueID, next.VenueID) {
+		changes["venueId"] = FieldChange{From: s.VenueID, To: next.VenueID}
+	}
+
+	if !sameInstant(s.EventDate, next.EventDate) {
+		changes["eventDate"] = FieldChange{From: s.EventDate, To: next.EventDate}
+	}
+
+	if !sameStrings(s.CategoryIDs, next.CategoryIDs) {
+		changes["categoryIds"] = FieldChange{From: s.CategoryIDs, To: next.CategoryIDs}
+	}
+	if !sameStrings(s.Tags, next.Tags) {
+		changes["tags"] = FieldChange{From: s.Tags, To: next.Tags}
+	}
+
+	return changes
+}
+
+func sameID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameStrings compares sorted lists, treating nil as empty
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameInstant(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ToResponse converts EventRevision to EventRevisionResponse
+func (r *EventRevision) ToResponse(includeSnapshot bool) *EventRevisionResponse {
+	response := &EventRevisionResponse{
+		ID:           r.ID,
+		EventID:      r.EventID,
+		Number:       r.Number,
+		UserID:       r.UserID,
+		Changes:      r.Changes,
+		RevertedFrom: r.RevertedFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+	if includeSnapshot {
+		snapshot := r.Snapshot
+		response.Snapshot = &snapshot
+	}
+	return response
+}
+
+// BeforeCreate is a hook that runs before creating a revision
+func (r *EventRevision) BeforeCreate(tx *gorm.DB) error {
+	r.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+// EventStatus represents the lifecycle state of an event
+type EventStatus string
+
+const (
+	EventStatusDraft     EventStatus = "draft"
+	EventStatusPublished EventStatus = "published"
+	EventStatusCancelled EventStatus = "cancelled"
+	EventStatusPostponed EventStatus = "postponed"
+)
+
+// eventStatusTransitions lists the states each status may move to
+var eventStatusTransitions = map[EventStatus][]EventStatus{
+	EventStatusDraft:     {EventStatusPublished, EventStatusCancelled},
+	EventStatusPublished: {EventStatusCancelled, EventStatusPostponed},
+	EventStatusPostponed: {EventStatusPublished, EventStatusCancelled},
+	EventStatusCancelled: {},
+}
+
+// IsValid reports whether the status is one of the known lifecycle states
+func (s EventStatus) IsValid() bool {
+	_, ok := eventStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether an event may move from s to next
+func (s EventStatus) CanTransitionTo(next EventStatus) bool {
+	for _, allowed := range eventStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublic reports whether events in this state appear in public listings
+func (s EventStatus) IsPublic() bool {
+	return s != EventStatusDraft
+}
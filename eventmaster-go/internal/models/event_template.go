@@ -0,0 +1,150 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventTemplate holds reusable defaults for new events. Templates are private to their owner.
+type EventTemplate struct {
+	Base
+	Name        string   `json:"name" gorm:"size:255;not null"`
+	UserID      string   `json:"userId" gorm:"type:uuid;not null;index"`
+	Title       string   `json:"title"`
+	Description string   `json:"description" gorm:"type:text"`
+	Organizer   string   `json:"organizer"`
+	TimeZone    string   `json:"timeZone" gorm:"size:64"`
+	Latitude    float64  `json:"latitude" gorm:"type:decimal(10,8)"`
+	Longitude   float64  `json:"longitude" gorm:"type:decimal(11,8)"`
+	Location    string   `json:"location" gorm:"type:text"`
+	VenueID     *string  `json:"venueId" gorm:"type:uuid"`
+	EventType   string   `json:"eventType"`
+	CategoryIDs []string `json:"categoryIds" gorm:"type:jsonb;serializer:json"`
+	Tags        []string `json:"tags" gorm:"type:jsonb;serializer:json"`
+	ImageIDs    []string `json:"imageIds" gorm:"type:jsonb;serializer:json"`
+}
+
+// EventTemplateResponse represents a template sent to clients
+type EventTemplateResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Organizer   string    `json:"organizer,omitempty"`
+	TimeZone    string    `json:"timeZone,omitempty"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Location    string    `json:"location,omitempty"`
+	VenueID     *string   `json:"venueId,omitempty"`
+	EventType   string    `json:"eventType,omitempty"`
+	CategoryIDs []string  `json:"categoryIds"`
+	Tags        []string  `json:"tags"`
+	ImageIDs    []string  `json:"imageIds"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TemplateFromEvent captures the reusable settings of an event as a template
+func TemplateFromEvent(e *Event, name string) *EventTemplate {
+	template := &EventTemplate{
+		Name:        name,
+		Title:       e.Title,
+		Description: e.Description,
+		Organizer:   e.Organizer,
+		TimeZone:    e.TimeZone,
+		Latitude:    e.Latitude,
+		Longitude:   e.Longitude,
+		Location:    e.Location,
+		VenueID:     e.VenueID,
+		EventType:   e.EventType,
+		CategoryIDs: make([]string, len(e.Categories)),
+		Tags:        make([]string, len(e.Tags)),
+		ImageIDs:    make([]string, len(e.Images)),
+	}
+	for i, category := range e.Categories {
+		template.CategoryIDs[i] = category.ID
+	}
+	for i, tag := range e.Tags {
+		template.Tags[i] = tag.Name
+	}
+	for i, image := range e.Images {
+		template.ImageIDs[i] = image.ID
+	}
+	return template
+}
+
+// ApplyTo fills the fields of event that were left empty with the template defaults.
+// Categories and tags are added as stubs to be resolved when the event is created.
+func (t *EventTemplate) ApplyTo(e *Event) {
+	if e.Title == "" {
+		e.Title = t.Title
+	}
+	if e.Description == "" {
+		e.Description = t.Description
+	}
+	if e.Organizer == "" {
+		e.Organizer = t.Organizer
+	}
+	if e.TimeZone == "" {
+		e.TimeZone = t.TimeZone
+	}
+	if e.Latitude == 0 && e.Longitude == 0 {
+		e.Latitude = t.Latitude
+		e.Longitude = t.Longitude
+	}
+	if e.Location == "" {
+		e.Location = t.Location
+	}
+	if e.VenueID == nil {
+		e.VenueID = t.VenueID
+	}
+	if e.EventType == "" {
+		e.EventType = t.EventType
+	}
+	if len(e.Categories) == 0 {
+		for _, id := range t.CategoryIDs {
+			e.Categories = append(e.Categories, Category{Base: Base{ID: id}})
+		}
+	}
+	if len(e.Tags) == 0 {
+		for _, name := range t.Tags {
+			e.Tags = append(e.Tags, Tag{Name: name})
+		}
+	}
+}
+
+// ToResponse converts EventTemplate to EventTemplateResponse
+func (t *EventTemplate) ToResponse() *EventTemplateResponse {
+	return &EventTemplateResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Title:       t.Title,
+		Description: t.Description,
+		Organizer:   t.Organizer,
+		TimeZone:    t.TimeZone,
+		Latitude:    t.Latitude,
+		Longitude:   t.Longitude,
+		Location:    t.Location,
+		VenueID:     t.VenueID,
+		EventType:   t.EventType,
+		CategoryIDs: nonNilStrings(t.CategoryIDs),
+		Tags:        nonNilStrings(t.Tags),
+		ImageIDs:    nonNilStrings(t.ImageIDs),
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// BeforeCreate is a hook that runs before creating a template
+func (t *EventTemplate) BeforeCreate(tx *gorm.DB) error {
+	t.ID = GenerateID()
+	return nil
+}
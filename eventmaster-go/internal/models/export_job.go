@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExportKind identifies what an export job writes
+type ExportKind string
+
+const (
+	ExportKindEvents       ExportKind = "events"
+	ExportKindParticipants ExportKind = "participants"
+)
+
+// ExportStatus is the state of an asynchronous export job
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob tracks an export written to disk in the background for later download
+type ExportJob struct {
+	Base
+	UserID      string       `json:"userId" gorm:"type:uuid;not null;index"`
+	Kind        ExportKind   `json:"kind" gorm:"type:varchar(20);not null"`
+	Format      string       `json:"format" gorm:"type:varchar(10);not null"`
+	EventID     *string      `json:"eventId" gorm:"type:uuid"`
+	Status      ExportStatus `json:"status" gorm:"type:varchar(20);not null;default:pending;index"`
+	Error       string       `json:"error" gorm:"type:text"`
+	RowCount    int          `json:"rowCount"`
+	FilePath    string       `json:"-"`
+	CompletedAt *time.Time   `json:"completedAt"`
+	ExpiresAt   *time.Time   `json:"expiresAt" gorm:"index"`
+}
+
+// ExportJobResponse represents an export job sent to clients
+type ExportJobResponse struct {
+	ID          string       `json:"id"`
+	Kind        ExportKind   `json:"kind"`
+	Format      string       `json:"format"`
+	EventID     *string      `json:"eventId,omitempty"`
+	Status      ExportStatus `json:"status"`
+	Error       string       `json:"error,omitempty"`
+	RowCount    int          `json:"rowCount"`
+	DownloadURL string       `json:"downloadUrl,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	CompletedAt *time.Time   `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time   `json:"expiresAt,omitempty"`
+}
+
+// ToResponse converts ExportJob to ExportJobResponse
+func (j *ExportJob) ToResponse() *ExportJobResponse {
+	return &ExportJobResponse{
+		ID:          j.ID,
+		Kind:        j.Kind,
+		Format:      j.Format,
+		EventID:     j.EventID,
+		Status:      j.Status,
+		Error:       j.Error,
+		RowCount:    j.RowCount,
+		CreatedAt:   j.CreatedAt,
+		CompletedAt: j.CompletedAt,
+		ExpiresAt:   j.ExpiresAt,
+	}
+}
+
+// BeforeCreate is a hook that runs before creating an export job
+func (j *ExportJob) BeforeCreate(tx *gorm.DB) error {
+	j.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HoldStatus represents where a ticket hold is in its life
+type HoldStatus string
+
+const (
+	HoldStatusActive    HoldStatus = "active"
+	HoldStatusConverted HoldStatus = "converted"
+	HoldStatusReleased  HoldStatus = "released"
+	HoldStatusExpired   HoldStatus = "expired"
+)
+
+// Hold keeps one ticket of a free ticket type aside while a user fills in the registration
+// form. The ticket is counted as reserved until the hold is converted into a participant,
+// released, or expires.
+type Hold struct {
+	Base
+	EventID      string     `json:"eventId" gorm:"type:uuid;not null;index"`
+	TicketTypeID string     `json:"ticketTypeId" gorm:"type:uuid;not null;index"`
+	Email        string     `json:"email" gorm:"not null;index"`
+	Status       HoldStatus `json:"status" gorm:"type:varchar(20);not null;default:'active';index:idx_hold_status_expiry"`
+	ExpiresAt    time.Time  `json:"expiresAt" gorm:"not null;index:idx_hold_status_expiry"`
+	// ParticipantID is the participant the hold was converted into
+	ParticipantID *string    `json:"participantId" gorm:"type:uuid"`
+	ConvertedAt   *time.Time `json:"convertedAt"`
+}
+
+// HoldResponse represents the hold data sent to clients
+type HoldResponse struct {
+	ID            string     `json:"id"`
+	EventID       string     `json:"eventId"`
+	TicketTypeID  string     `json:"ticketTypeId"`
+	Status        HoldStatus `json:"status"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	ParticipantID *string    `json:"participantId,omitempty"`
+}
+
+// ToResponse converts Hold to HoldResponse, reporting an active hold past its expiry as expired
+// even before the reaper has released it
+func (h *Hold) ToResponse() *HoldResponse {
+	status := h.Status
+	if !h.ActiveAt(time.Now()) && status == HoldStatusActive {
+		status = HoldStatusExpired
+	}
+	return &HoldResponse{
+		ID:            h.ID,
+		EventID:       h.EventID,
+		TicketTypeID:  h.TicketTypeID,
+		Status:        status,
+		ExpiresAt:     h.ExpiresAt,
+		ParticipantID: h.ParticipantID,
+	}
+}
+
+// ActiveAt reports whether the hold still keeps its ticket at the given time
+func (h *Hold) ActiveAt(now time.Time) bool {
+	return h.Status == HoldStatusActive && now.Before(h.ExpiresAt)
+}
+
+// BeforeCreate is a hook that runs before creating a hold
+func (h *Hold) BeforeCreate(tx *gorm.DB) error {
+	h.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderStatus represents the payment state of an order
+type OrderStatus string
+
+const (
+	OrderStatusPending  OrderStatus = "pending"
+	OrderStatusPaid     OrderStatus = "paid"
+	OrderStatusFailed   OrderStatus = "failed"
+	OrderStatusRefunded OrderStatus = "refunded"
+)
+
+// orderStatusTransitions lists the states each order status may move to. A failed order
+// is refunded when a payment completes after it expired.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:  {OrderStatusPaid, OrderStatusFailed},
+	OrderStatusPaid:     {OrderStatusRefunded},
+	OrderStatusFailed:   {OrderStatusRefunded},
+	OrderStatusRefunded: {},
+}
+
+// IsValid reports whether the status is one of the known order states
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether an order may move from s to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Order is a purchase of tickets for an event. Its tickets are held while it is pending
+// and become participants once it is paid.
+type Order struct {
+	Base
+	EventID           string            `json:"eventId" gorm:"type:uuid;not null;index"`
+	Email             string            `json:"email" gorm:"not null;index"`
+	FullName          string            `json:"fullName" gorm:"not null"`
+	DateOfBirth       *time.Time        `json:"dateOfBirth"`
+	SourceOfDiscovery SourceOfDiscovery `json:"sourceOfDiscovery" gorm:"type:varchar(50);not null"`
+	Status            OrderStatus       `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	SubtotalCents     int64             `json:"subtotalCents" gorm:"not null;default:0"`
+	DiscountCents     int64             `json:"discountCents" gorm:"not null;default:0"`
+	TotalCents        int64             `json:"totalCents" gorm:"not null;default:0"`
+	Currency          string            `json:"currency" gorm:"size:3;not null"`
+	// PromoCodeID is the promo code redeemed by the order; PromoCode keeps the code as entered
+	PromoCodeID *string `json:"promoCodeId" gorm:"type:uuid;index"`
+	PromoCode   string  `json:"promoCode" gorm:"size:64"`
+	// Provider and ProviderRef identify the payment at the payment provider
+	Provider    string      `json:"provider" gorm:"size:50;uniqueIndex:idx_order_provider_ref,where:provider_ref <> ''"`
+	ProviderRef string      `json:"providerRef" gorm:"size:255;uniqueIndex:idx_order_provider_ref,where:provider_ref <> ''"`
+	PaidAt      *time.Time  `json:"paidAt"`
+	FailedAt    *time.Time  `json:"failedAt"`
+	RefundedAt  *time.Time  `json:"refundedAt"`
+	Items       []OrderItem `json:"items" gorm:"foreignKey:OrderID"`
+}
+
+// OrderItem is a line of an order: a number of tickets of one type at the price when ordered
+type OrderItem struct {
+	Base
+	OrderID        string `json:"orderId" gorm:"type:uuid;not null;index"`
+	TicketTypeID   string `json:"ticketTypeId" gorm:"type:uuid;not null;index"`
+	TicketTypeName string `json:"ticketTypeName" gorm:"size:255;not null"`
+	Quantity       int    `json:"quantity" gorm:"not null"`
+	UnitPriceCents int64  `json:"unitPriceCents" gorm:"not null"`
+}
+
+// OrderResponse represents the order data sent to clients
+type OrderResponse struct {
+	ID                string               `json:"id"`
+	EventID           string               `json:"eventId"`
+	Email             string               `json:"email"`
+	FullName          string               `json:"fullName"`
+	SourceOfDiscovery SourceOfDiscovery    `json:"sourceOfDiscovery"`
+	Status            OrderStatus          `json:"status"`
+	SubtotalCents     int64                `json:"subtotalCents"`
+	DiscountCents     int64                `json:"discountCents"`
+	TotalCents        int64                `json:"totalCents"`
+	PromoCode         string               `json:"promoCode,omitempty"`
+	Currency          string               `json:"currency"`
+	Provider          string               `json:"provider,omitempty"`
+	Items             []*OrderItemResponse `json:"items"`
+	PaidAt            *time.Time           `json:"paidAt,omitempty"`
+	FailedAt          *time.Time           `json:"failedAt,omitempty"`
+	RefundedAt        *time.Time           `json:"refundedAt,omitempty"`
+	CreatedAt         time.Time            `json:"createdAt"`
+	UpdatedAt         time.Time            `json:"updatedAt"`
+}
+
+// OrderItemResponse represents an order line sent to clients
+type OrderItemResponse struct {
+	TicketTypeID   string `json:"ticketTypeId"`
+	TicketTypeName string `json:"ticketTypeName"`
+	Quantity       int    `json:"quantity"`
+	UnitPriceCents int64  `json:"unitPriceCents"`
+	TotalCents     int64  `json:"totalCents"`
+}
+
+// ToResponse converts Order to OrderResponse
+func (o *Order) ToResponse() *OrderResponse {
+	items := make([]*OrderItemResponse, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = &OrderItemResponse{
+			TicketTypeID:   item.TicketTypeID,
+			TicketTypeName: item.TicketTypeName,
+			Quantity:       item.Quantity,
+			UnitPriceCents: item.UnitPriceCents,
+			TotalCents:     item.TotalCents(),
+		}
+	}
+
+	return &OrderResponse{
+		ID:                o.ID,
+		EventID:           o.EventID,
+		Email:             o.Email,
+		FullName:          o.FullName,
+		SourceOfDiscovery: o.SourceOfDiscovery,
+		Status:            o.Status,
+		SubtotalCents:     o.SubtotalCents,
+		DiscountCents:     o.DiscountCents,
+		TotalCents:        o.TotalCents,
+		PromoCode:         o.PromoCode,
+		Currency:          o.Currency,
+		Provider:          o.Provider,
+		Items:             items,
+		PaidAt:            o.PaidAt,
+		FailedAt:          o.FailedAt,
+		RefundedAt:        o.RefundedAt,
+		CreatedAt:         o.CreatedAt,
+		UpdatedAt:         o.UpdatedAt,
+	}
+}
+
+// TicketCount is the number of tickets across all lines of the order
+func (o *Order) TicketCount() int {
+	count := 0
+	for _, item := range o.Items {
+		count += item.Quantity
+	}
+	return count
+}
+
+// Participants builds one participant per ticket of the order, registered under the buyer's details
+func (o *Order) Participants() []Participant {
+	orderID := o.ID
+	participants := make([]Participant, 0, o.TicketCount())
+	for _, item := range o.Items {
+		for i := 0; i < item.Quantity; i++ {
+			ticketTypeID := item.TicketTypeID
+			participants = append(participants, Participant{
+				FullName:          o.FullName,
+				Email:             o.Email,
+				DateOfBirth:       o.DateOfBirth,
+				SourceOfDiscovery: o.SourceOfDiscovery,
+				EventID:           o.EventID,
+				TicketTypeID:      &ticketTypeID,
+				OrderID:           &orderID,
+			})
+		}
+	}
+	return participants
+}
+
+// TotalCents is the price of the line
+func (i *OrderItem) TotalCents() int64 {
+	return i.UnitPriceCents * int64(i.Quantity)
+}
+
+// BeforeCreate is a hook that runs before creating an order
+func (o *Order) BeforeCreate(tx *gorm.DB) error {
+	o.ID = GenerateID()
+	if o.Status == "" {
+		o.Status = OrderStatusPending
+	}
+	return nil
+}
+
+// BeforeCreate is a hook that runs before creating an order item
+func (i *OrderItem) BeforeCreate(tx *gorm.DB) error {
+	i.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrganizerLink is a labelled link shown on an organizer profile, such as a social account
+type OrganizerLink struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// OrganizerProfile is the public identity events are organized under. A profile is
+// separate from user accounts and may be managed by several users.
+type OrganizerProfile struct {
+	Base
+	Name         string          `json:"name" gorm:"size:255;not null;index"`
+	Bio          string          `json:"bio" gorm:"type:text"`
+	LogoID       *string         `json:"logoId" gorm:"type:uuid"`
+	Logo         *Image          `json:"-" gorm:"foreignKey:LogoID"`
+	ContactEmail string          `json:"contactEmail" gorm:"size:100"`
+	ContactPhone string          `json:"contactPhone" gorm:"size:50"`
+	Website      string          `json:"website" gorm:"type:text"`
+	Links        []OrganizerLink `json:"links" gorm:"type:jsonb;serializer:json"`
+	Managers     []User          `json:"-" gorm:"many2many:organizer_managers;"`
+}
+
+// OrganizerProfileResponse represents an organizer profile sent to clients
+type OrganizerProfileResponse struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Bio          string          `json:"bio,omitempty"`
+	LogoID       *string         `json:"logoId,omitempty"`
+	Logo         *ImageResponse  `json:"logo,omitempty"`
+	ContactEmail string          `json:"contactEmail,omitempty"`
+	ContactPhone string          `json:"contactPhone,omitempty"`
+	Website      string          `json:"website,omitempty"`
+	Links        []OrganizerLink `json:"links"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// OrganizerSummary is the short form of a profile embedded in event responses
+type OrganizerSummary struct {
+	ID   string         `json:"id"`
+	Name string         `json:"name"`
+	Logo *ImageResponse `json:"logo,omitempty"`
+}
+
+// OrganizerManagerResponse identifies a user managing a profile
+type OrganizerManagerResponse struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+// ToResponse converts OrganizerProfile to OrganizerProfileResponse
+func (o *OrganizerProfile) ToResponse() *OrganizerProfileResponse {
+	var logo *ImageResponse
+	if o.Logo != nil {
+		logo = o.Logo.ToResponse()
+	}
+
+	links := o.Links
+	if links == nil {
+		links = []OrganizerLink{}
+	}
+
+	return &OrganizerProfileResponse{
+		ID:           o.ID,
+		Name:         o.Name,
+		Bio:          o.Bio,
+		LogoID:       o.LogoID,
+		Logo:         logo,
+		ContactEmail: o.ContactEmail,
+		ContactPhone: o.ContactPhone,
+		Website:      o.Website,
+		Links:        links,
+		CreatedAt:    o.CreatedAt,
+		UpdatedAt:    o.UpdatedAt,
+	}
+}
+
+// Summary converts OrganizerProfile to OrganizerSummary
+func (o *OrganizerProfile) Summary() *OrganizerSummary {
+	var logo *ImageResponse
+	if o.Logo != nil {
+		logo = o.Logo.ToResponse()
+	}
+	return &OrganizerSummary{ID: o.ID, Name: o.Name, Logo: logo}
+}
+
+// ManagerResponses lists the loaded managers of the profile
+func (o *OrganizerProfile) ManagerResponses() []OrganizerManagerResponse {
+	managers := make([]OrganizerManagerResponse, len(o.Managers))
+	for i, manager := range o.Managers {
+		managers[i] = OrganizerManagerResponse{ID: manager.ID, Email: manager.Email}
+	}
+	return managers
+}
+
+// IsManagedBy reports whether userID is one of the loaded managers of the profile
+func (o *OrganizerProfile) IsManagedBy(userID string) bool {
+	for _, manager := range o.Managers {
+		if userID != "" && manager.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate is a hook that runs before creating an organizer profile
+func (o *OrganizerProfile) BeforeCreate(tx *gorm.DB) error {
+	o.ID = GenerateID()
+	return nil
+}
@@ -24,6 +24,10 @@ type Participant struct {
 	SourceOfDiscovery  SourceOfDiscovery `json:"sourceOfDiscovery" gorm:"type:varchar(50);not null"`
 	EventID            string          `json:"eventId" gorm:"type:uuid;not null;index"`
 	Event              *Event          `json:"-" gorm:"foreignKey:EventID"`
+	// TicketTypeID is the ticket type the participant registered with, if the event sells tickets
+	TicketTypeID       *string         `json:"ticketTypeId" gorm:"type:uuid;index"`
+	// OrderID is the paid order the participant's ticket was bought with
+	OrderID            *string         `json:"orderId" gorm:"type:uuid;index"`
 }
 
 // ParticipantResponse represents the participant data sent to clients
@@ -34,12 +38,20 @@ type ParticipantResponse struct {
 	DateOfBirth       *time.Time      `json:"dateOfBirth"`
 	SourceOfDiscovery SourceOfDiscovery `json:"sourceOfDiscovery"`
 	EventID           string          `json:"eventId"`
+	TicketTypeID      *string         `json:"ticketTypeId,omitempty"`
+	OrderID           *string         `json:"orderId,omitempty"`
 	CreatedAt         time.Time       `json:"createdAt"`
 	UpdatedAt         time.Time       `json:"updatedAt"`
+	DeletedAt         *time.Time      `json:"deletedAt,omitempty"`
 }
 
 // ToResponse converts Participant to ParticipantResponse
 func (p *Participant) ToResponse() *ParticipantResponse {
+	var deletedAt *time.Time
+	if p.DeletedAt.Valid {
+		deletedAt = &p.DeletedAt.Time
+	}
+
 	return &ParticipantResponse{
 		ID:                p.ID,
 		FullName:          p.FullName,
@@ -47,8 +59,11 @@ func (p *Participant) ToResponse() *ParticipantResponse {
 		DateOfBirth:       p.DateOfBirth,
 		SourceOfDiscovery: p.SourceOfDiscovery,
 		EventID:           p.EventID,
+		TicketTypeID:      p.TicketTypeID,
+		OrderID:           p.OrderID,
 		CreatedAt:         p.CreatedAt,
 		UpdatedAt:         p.UpdatedAt,
+		DeletedAt:         deletedAt,
 	}
 }
 
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DiscountType tells how a promo code lowers the price of an order
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+// PromoCode is a discount code for an event, optionally limited to one ticket type.
+// A 100% code gives free comps.
+type PromoCode struct {
+	Base
+	EventID string `json:"eventId" gorm:"type:uuid;not null;uniqueIndex:idx_promo_event_code,where:deleted_at IS NULL"`
+	// TicketTypeID limits the discount to tickets of one type; nil applies it to the whole order
+	TicketTypeID *string `json:"ticketTypeId" gorm:"type:uuid;index"`
+	// Code is stored upper case and matched case-insensitively
+	Code         string       `json:"code" gorm:"size:64;not null;uniqueIndex:idx_promo_event_code,where:deleted_at IS NULL"`
+	DiscountType DiscountType `json:"discountType" gorm:"type:varchar(10);not null"`
+	// DiscountValue is a percentage for percent codes and an amount in minor units for fixed codes
+	DiscountValue int64  `json:"discountValue" gorm:"not null"`
+	Currency      string `json:"currency" gorm:"size:3"`
+	// MaxRedemptions caps the uses of the code; nil means unlimited
+	MaxRedemptions *int `json:"maxRedemptions"`
+	// PerEmailLimit caps the uses by one buyer email; nil means unlimited
+	PerEmailLimit *int `json:"perEmailLimit"`
+	// Redeemed is only changed through conditional updates so it never exceeds MaxRedemptions
+	Redeemed   int        `json:"redeemed" gorm:"not null;default:0"`
+	ValidFrom  *time.Time `json:"validFrom"`
+	ValidUntil *time.Time `json:"validUntil"`
+}
+
+// PromoRedemption records a use of a promo code by an order
+type PromoRedemption struct {
+	Base
+	PromoCodeID   string `json:"promoCodeId" gorm:"type:uuid;not null;index"`
+	OrderID       string `json:"orderId" gorm:"type:uuid;not null;uniqueIndex"`
+	Email         string `json:"email" gorm:"not null;index"`
+	DiscountCents int64  `json:"discountCents" gorm:"not null"`
+}
+
+// PromoCodeResponse represents the promo code data sent to organizers
+type PromoCodeResponse struct {
+	ID             string       `json:"id"`
+	EventID        string       `json:"eventId"`
+	TicketTypeID   *string      `json:"ticketTypeId,omitempty"`
+	Code           string       `json:"code"`
+	DiscountType   DiscountType `json:"discountType"`
+	DiscountValue  int64        `json:"discountValue"`
+	Currency       string       `json:"currency,omitempty"`
+	MaxRedemptions *int         `json:"maxRedemptions,omitempty"`
+	PerEmailLimit  *int         `json:"perEmailLimit,omitempty"`
+	Redeemed       int          `json:"redeemed"`
+	ValidFrom      *time.Time   `json:"validFrom,omitempty"`
+	ValidUntil     *time.Time   `json:"validUntil,omitempty"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+// ToResponse converts PromoCode to PromoCodeResponse
+func (p *PromoCode) ToResponse() *PromoCodeResponse {
+	return &PromoCodeResponse{
+		ID:             p.ID,
+		EventID:        p.EventID,
+		TicketTypeID:   p.TicketTypeID,
+		Code:           p.Code,
+		DiscountType:   p.DiscountType,
+		DiscountValue:  p.DiscountValue,
+		Currency:       p.Currency,
+		MaxRedemptions: p.MaxRedemptions,
+		PerEmailLimit:  p.PerEmailLimit,
+		Redeemed:       p.Redeemed,
+		ValidFrom:      p.ValidFrom,
+		ValidUntil:     p.ValidUntil,
+		CreatedAt:      p.CreatedAt,
+	}
+}
+
+// ValidAt reports whether the code can be used at the given time
+func (p *PromoCode) ValidAt(now time.Time) bool {
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return false
+	}
+	return p.ValidUntil == nil || now.Before(*p.ValidUntil)
+}
+
+// Exhausted reports whether the code has been used as often as allowed
+func (p *PromoCode) Exhausted() bool {
+	return p.MaxRedemptions != nil && p.Redeemed >= *p.MaxRedemptions
+}
+
+// AppliesTo reports whether the order has tickets the code is scoped to
+func (p *PromoCode) AppliesTo(order *Order) bool {
+	if p.TicketTypeID == nil {
+		return true
+	}
+	for i := range order.Items {
+		if *p.TicketTypeID == order.Items[i].TicketTypeID {
+			return true
+		}
+	}
+	return false
+}
+
+// EligibleCents is the part of the order the code applies to
+func (p *PromoCode) EligibleCents(order *Order) int64 {
+	var eligible int64
+	for i := range order.Items {
+		if p.TicketTypeID == nil || *p.TicketTypeID == order.Items[i].TicketTypeID {
+			eligible += order.Items[i].TotalCents()
+		}
+	}
+	return eligible
+}
+
+// DiscountFor computes the discount on an order. Percentages round down; fixed amounts are
+// taken once per order and never exceed the eligible amount.
+func (p *PromoCode) DiscountFor(order *Order) int64 {
+	eligible := p.EligibleCents(order)
+	switch p.DiscountType {
+	case DiscountPercent:
+		return eligible * p.DiscountValue / 100
+	case DiscountFixed:
+		if p.DiscountValue < eligible {
+			return p.DiscountValue
+		}
+		return eligible
+	default:
+		return 0
+	}
+}
+
+// BeforeCreate is a hook that runs before creating a promo code
+func (p *PromoCode) BeforeCreate(tx *gorm.DB) error {
+	p.ID = GenerateID()
+	return nil
+}
+
+// BeforeCreate is a hook that runs before creating a promo redemption
+func (r *PromoRedemption) BeforeCreate(tx *gorm.DB) error {
+	r.ID = GenerateID()
+	return nil
+}
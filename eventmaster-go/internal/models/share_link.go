@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventSlug is a slug an event was reachable at before its title changed. Requests for it
+// are redirected to the event's current slug.
+type EventSlug struct {
+	Base
+	EventID string `json:"eventId" gorm:"type:uuid;not null;index"`
+	Slug    string `json:"slug" gorm:"size:100;not null;uniqueIndex"`
+}
+
+// BeforeCreate is a hook that runs before creating an event slug
+func (s *EventSlug) BeforeCreate(tx *gorm.DB) error {
+	s.ID = GenerateID()
+	return nil
+}
+
+// ShareLink is a short code that redirects to an event and counts how often it was followed.
+// Organizers create one per channel they share the event on to compare their reach.
+type ShareLink struct {
+	Base
+	EventID string `json:"eventId" gorm:"type:uuid;not null;index"`
+	Code    string `json:"code" gorm:"size:16;not null;uniqueIndex"`
+	// Label names the channel the link was shared on, such as "newsletter"
+	Label         string     `json:"label" gorm:"size:100;not null;default:''"`
+	Clicks        int64      `json:"clicks" gorm:"not null;default:0"`
+	LastClickedAt *time.Time `json:"lastClickedAt"`
+	CreatedBy     string     `json:"createdBy" gorm:"type:uuid;not null"`
+}
+
+// ShareLinkResponse represents a share link sent to organizers
+type ShareLinkResponse struct {
+	ID            string     `json:"id"`
+	EventID       string     `json:"eventId"`
+	Code          string     `json:"code"`
+	Label         string     `json:"label,omitempty"`
+	Clicks        int64      `json:"clicks"`
+	LastClickedAt *time.Time `json:"lastClickedAt,omitempty"`
+	CreatedBy     string     `json:"createdBy"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// ToResponse converts ShareLink to ShareLinkResponse
+func (l *ShareLink) ToResponse() *ShareLinkResponse {
+	return &ShareLinkResponse{
+		ID:            l.ID,
+		EventID:       l.EventID,
+		Code:          l.Code,
+		Label:         l.Label,
+		Clicks:        l.Clicks,
+		LastClickedAt: l.LastClickedAt,
+		CreatedBy:     l.CreatedBy,
+		CreatedAt:     l.CreatedAt,
+	}
+}
+
+// BeforeCreate is a hook that runs before creating a share link
+func (l *ShareLink) BeforeCreate(tx *gorm.DB) error {
+	l.ID = GenerateID()
+	return nil
+}
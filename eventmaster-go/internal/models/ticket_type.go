@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultCurrency is used for ticket types created without an explicit currency
+const DefaultCurrency = "USD"
+
+// TicketType is a kind of ticket sold for an event, such as "Early bird" or "VIP".
+// Prices are in the minor unit of the currency (cents for USD).
+type TicketType struct {
+	Base
+	EventID     string `json:"eventId" gorm:"type:uuid;not null;index"`
+	Name        string `json:"name" gorm:"size:255;not null"`
+	Description string `json:"description" gorm:"type:text"`
+	PriceCents  int64  `json:"priceCents" gorm:"not null;default:0"`
+	// MaxPriceCents is the upper end of a price range; only informational types have one
+	MaxPriceCents *int64 `json:"maxPriceCents"`
+	Currency      string `json:"currency" gorm:"size:3;not null;default:'USD'"`
+	// Quantity is the number of tickets for sale; nil means unlimited
+	Quantity *int `json:"quantity"`
+	// Sold and Reserved are only changed through conditional updates so together they never
+	// exceed Quantity; check constraints back that up in the database. Reserved counts tickets
+	// held by pending orders and ticket holds.
+	Sold        int        `json:"sold" gorm:"not null;default:0;check:chk_ticket_types_counters,sold >= 0 AND reserved >= 0"`
+	Reserved    int        `json:"reserved" gorm:"not null;default:0;check:chk_ticket_types_capacity,quantity IS NULL OR sold + reserved <= quantity"`
+	SalesStart  *time.Time `json:"salesStart"`
+	SalesEnd    *time.Time `json:"salesEnd"`
+	MinPerOrder int        `json:"minPerOrder" gorm:"not null;default:1"`
+	MaxPerOrder int        `json:"maxPerOrder" gorm:"not null;default:10"`
+	// Informational ticket types describe prices sold elsewhere, such as Ticketmaster price ranges
+	Informational bool `json:"informational" gorm:"not null;default:false"`
+	Position      int  `json:"position" gorm:"not null;default:0"`
+}
+
+// TicketTypeResponse represents a ticket type and its availability sent to clients
+type TicketTypeResponse struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description,omitempty"`
+	PriceCents    int64      `json:"priceCents"`
+	MaxPriceCents *int64     `json:"maxPriceCents,omitempty"`
+	Currency      string     `json:"currency"`
+	Quantity      *int       `json:"quantity,omitempty"`
+	Available     *int       `json:"available,omitempty"`
+	SalesStart    *time.Time `json:"salesStart,omitempty"`
+	SalesEnd      *time.Time `json:"salesEnd,omitempty"`
+	MinPerOrder   int        `json:"minPerOrder"`
+	MaxPerOrder   int        `json:"maxPerOrder"`
+	Informational bool       `json:"informational"`
+	OnSale        bool       `json:"onSale"`
+	SoldOut       bool       `json:"soldOut"`
+}
+
+// ToResponse converts TicketType to TicketTypeResponse, evaluating the sale window now
+func (t *TicketType) ToResponse() *TicketTypeResponse {
+	available := t.Available()
+	return &TicketTypeResponse{
+		ID:            t.ID,
+		Name:          t.Name,
+		Description:   t.Description,
+		PriceCents:    t.PriceCents,
+		MaxPriceCents: t.MaxPriceCents,
+		Currency:      t.Currency,
+		Quantity:      t.Quantity,
+		Available:     available,
+		SalesStart:    t.SalesStart,
+		SalesEnd:      t.SalesEnd,
+		MinPerOrder:   t.MinPerOrder,
+		MaxPerOrder:   t.MaxPerOrder,
+		Informational: t.Informational,
+		OnSale:        t.OnSaleAt(time.Now()),
+		SoldOut:       available != nil && *available == 0,
+	}
+}
+
+// Available returns the number of tickets neither sold nor held, or nil when the quantity is unlimited
+func (t *TicketType) Available() *int {
+	if t.Quantity == nil {
+		return nil
+	}
+	left := *t.Quantity - t.Sold - t.Reserved
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
+
+// OnSaleAt reports whether tickets of this type can be bought at the given time
+func (t *TicketType) OnSaleAt(now time.Time) bool {
+	if t.Informational {
+		return false
+	}
+	if t.SalesStart != nil && now.Before(*t.SalesStart) {
+		return false
+	}
+	if t.SalesEnd != nil && !now.Before(*t.SalesEnd) {
+		return false
+	}
+	available := t.Available()
+	return available == nil || *available > 0
+}
+
+// IsFree reports whether tickets of this type cost nothing
+func (t *TicketType) IsFree() bool {
+	return t.PriceCents == 0
+}
+
+// BeforeCreate is a hook that runs before creating a ticket type
+func (t *TicketType) BeforeCreate(tx *gorm.DB) error {
+	t.ID = GenerateID()
+	if t.Currency == "" {
+		t.Currency = DefaultCurrency
+	}
+	if t.MinPerOrder == 0 {
+		t.MinPerOrder = 1
+	}
+	if t.MaxPerOrder == 0 {
+		t.MaxPerOrder = 10
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Venue is a place events are held at. Venues are shared: any user may link an
+// event to any venue, but only the creator or an admin may change it.
+type Venue struct {
+	Base
+	Name      string  `json:"name" gorm:"size:255;not null;index"`
+	Address   string  `json:"address" gorm:"type:text"`
+	City      string  `json:"city" gorm:"size:100;index"`
+	Country   string  `json:"country" gorm:"size:100"`
+	Latitude  float64 `json:"latitude" gorm:"type:decimal(10,8)"`
+	Longitude float64 `json:"longitude" gorm:"type:decimal(11,8)"`
+	TimeZone  string  `json:"timeZone" gorm:"size:64"`
+	// Capacity is the number of people the venue holds, when known
+	Capacity *int `json:"capacity"`
+	// ExternalID is the Ticketmaster venue ID of imported venues
+	ExternalID *string `json:"externalId" gorm:"size:64;uniqueIndex"`
+	UserID     string  `json:"userId" gorm:"type:uuid;not null;index"`
+}
+
+// VenueResponse represents a venue sent to clients
+type VenueResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Address    string    `json:"address,omitempty"`
+	City       string    `json:"city,omitempty"`
+	Country    string    `json:"country,omitempty"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	TimeZone   string    `json:"timeZone,omitempty"`
+	Capacity   *int      `json:"capacity,omitempty"`
+	ExternalID *string   `json:"externalId,omitempty"`
+	UserID     string    `json:"userId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ToResponse converts Venue to VenueResponse
+func (v *Venue) ToResponse() *VenueResponse {
+	return &VenueResponse{
+		ID:         v.ID,
+		Name:       v.Name,
+		Address:    v.Address,
+		City:       v.City,
+		Country:    v.Country,
+		Latitude:   v.Latitude,
+		Longitude:  v.Longitude,
+		TimeZone:   v.TimeZone,
+		Capacity:   v.Capacity,
+		ExternalID: v.ExternalID,
+		UserID:     v.UserID,
+		CreatedAt:  v.CreatedAt,
+		UpdatedAt:  v.UpdatedAt,
+	}
+}
+
+// DisplayName formats the venue the way event locations are written: "name, city, country"
+func (v *Venue) DisplayName() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{v.Name, v.City, v.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BeforeCreate is a hook that runs before creating a venue
+func (v *Venue) BeforeCreate(tx *gorm.DB) error {
+	v.ID = GenerateID()
+	return nil
+}
@@ -0,0 +1,293 @@
+// Package query parses the filter and sort parameters accepted by list endpoints,
+// for example
+//
+//	filter[eventDate][gte]=2030-01-01&filter[title][like]=jazz&sort=-eventDate,title
+//
+// and applies them to gorm queries. Every field must appear in the resource's
+// Fields whitelist, which maps API names to columns, so client input is never
+// used as an SQL identifier.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidQuery wraps every parse error so callers can answer with 400
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Type determines how filter values are parsed and which operators apply
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeNumber
+	TypeTime
+	TypeBool
+)
+
+// Field maps an API field name to a column
+type Field struct {
+	Column string
+	Type   Type
+	// Values restricts a string field to an enumeration when set
+	Values []string
+	// NoSort excludes the field from sort, e.g. long text columns
+	NoSort bool
+}
+
+// Fields is the whitelist of a resource keyed by API field name
+type Fields map[string]Field
+
+// Operator compares a column to a filter value
+type Operator string
+
+const (
+	OpEq   Operator = "eq"
+	OpNe   Operator = "ne"
+	OpGt   Operator = "gt"
+	OpGte  Operator = "gte"
+	OpLt   Operator = "lt"
+	OpLte  Operator = "lte"
+	OpIn   Operator = "in"
+	OpLike Operator = "like"
+)
+
+// maxConditions bounds how many filters one request may combine
+const maxConditions = 20
+
+var filterParamPattern = regexp.MustCompile(`^filter\[([A-Za-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+// Condition is a parsed filter ready to be applied
+type Condition struct {
+	Column string
+	Op     Operator
+	Value  interface{}
+}
+
+// Order is one sort key
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// Query holds the filters and sort order of a list request
+type Query struct {
+	Conditions []Condition
+	Orders     []Order
+}
+
+// Parse reads filter[...] and sort parameters from values. Other parameters are ignored.
+func Parse(values url.Values, fields Fields) (Query, error) {
+	var q Query
+
+	// Walk parameters in a stable order so equal requests build identical SQL
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(key, "filter") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raws := values[key]
+		match := filterParamPattern.FindStringSubmatch(key)
+		if match == nil {
+			return q, invalid("malformed filter parameter %q", key)
+		}
+
+		name, op := match[1], Operator(match[2])
+		if op == "" {
+			op = OpEq
+		}
+		for _, raw := range raws {
+			condition, err := fields.condition(name, op, raw)
+			if err != nil {
+				return q, err
+			}
+			q.Conditions = append(q.Conditions, condition)
+		}
+	}
+	if len(q.Conditions) > maxConditions {
+		return q, invalid("at most %d filters are allowed", maxConditions)
+	}
+
+	orders, err := ParseSort(values.Get("sort"), fields)
+	if err != nil {
+		return q, err
+	}
+	q.Orders = orders
+
+	return q, nil
+}
+
+// ParseSort parses a comma separated list of fields, each optionally prefixed
+// with - for descending or + for ascending order
+func ParseSort(spec string, fields Fields) ([]Order, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	var orders []Order
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimLeft(part, "+-")
+
+		field, ok := fields[name]
+		if !ok || field.NoSort {
+			return nil, invalid("cannot sort by %q", name)
+		}
+		orders = append(orders, Order{Column: field.Column, Desc: desc})
+	}
+	return orders, nil
+}
+
+// Where adds the filter conditions to db
+func (q Query) Where(db *gorm.DB) *gorm.DB {
+	for _, c := range q.Conditions {
+		db = db.Where(c.expression())
+	}
+	return db
+}
+
+// OrderBy returns the requested sort order, or defaults when none was given
+func (q Query) OrderBy(defaults ...Order) clause.OrderBy {
+	orders := q.Orders
+	if len(orders) == 0 {
+		orders = defaults
+	}
+
+	columns := make([]clause.OrderByColumn, len(orders))
+	for i, order := range orders {
+		columns[i] = clause.OrderByColumn{Column: clause.Column{Name: order.Column}, Desc: order.Desc}
+	}
+	return clause.OrderBy{Columns: columns}
+}
+
+func (c Condition) expression() clause.Expression {
+	column := clause.Column{Name: c.Column}
+	switch c.Op {
+	case OpNe:
+		return clause.Neq{Column: column, Value: c.Value}
+	case OpGt:
+		return clause.Gt{Column: column, Value: c.Value}
+	case OpGte:
+		return clause.Gte{Column: column, Value: c.Value}
+	case OpLt:
+		return clause.Lt{Column: column, Value: c.Value}
+	case OpLte:
+		return clause.Lte{Column: column, Value: c.Value}
+	case OpIn:
+		return clause.IN{Column: column, Values: c.Value.([]interface{})}
+	case OpLike:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{column, c.Value}}
+	default:
+		return clause.Eq{Column: column, Value: c.Value}
+	}
+}
+
+func (fields Fields) condition(name string, op Operator, raw string) (Condition, error) {
+	field, ok := fields[name]
+	if !ok {
+		return Condition{}, invalid("unknown filter field %q", name)
+	}
+	if !field.supports(op) {
+		return Condition{}, invalid("operator %q is not supported for %q", op, name)
+	}
+
+	condition := Condition{Column: field.Column, Op: op}
+	switch op {
+	case OpIn:
+		parts := strings.Split(raw, ",")
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			value, err := field.parse(name, strings.TrimSpace(part))
+			if err != nil {
+				return Condition{}, err
+			}
+			values[i] = value
+		}
+		condition.Value = values
+	case OpLike:
+		condition.Value = "%" + escapeLike(raw) + "%"
+	default:
+		value, err := field.parse(name, raw)
+		if err != nil {
+			return Condition{}, err
+		}
+		condition.Value = value
+	}
+	return condition, nil
+}
+
+func (f Field) supports(op Operator) bool {
+	switch op {
+	case OpEq, OpNe, OpIn:
+		return true
+	case OpGt, OpGte, OpLt, OpLte:
+		return f.Type == TypeNumber || f.Type == TypeTime
+	case OpLike:
+		return f.Type == TypeString && len(f.Values) == 0
+	}
+	return false
+}
+
+// parse converts a raw filter value to the field's type. Times accept RFC 3339
+// instants or plain dates, which mean midnight UTC.
+func (f Field) parse(name, raw string) (interface{}, error) {
+	switch f.Type {
+	case TypeNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalid("%q expects a number", name)
+		}
+		return value, nil
+	case TypeTime:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		if value, err := time.Parse("2006-01-02", raw); err == nil {
+			return value, nil
+		}
+		return nil, invalid("%q expects an RFC 3339 time or YYYY-MM-DD date", name)
+	case TypeBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalid("%q expects true or false", name)
+		}
+		return value, nil
+	default:
+		if len(f.Values) > 0 && !contains(f.Values, raw) {
+			return nil, invalid("%q must be one of %s", name, strings.Join(f.Values, ", "))
+		}
+		return raw, nil
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidQuery, fmt.Sprintf(format, args...))
+}
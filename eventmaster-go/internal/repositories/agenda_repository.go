@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRoomOverlap is returned when a session would share its room with another session at the same time
+var ErrRoomOverlap = errors.New("session overlaps another session in the same room")
+
+// AgendaRepository defines the interface for agenda data operations: the sessions of an
+// event together with their tracks and speakers, and the personal agendas of participants
+type AgendaRepository interface {
+	BaseRepository[models.AgendaSession]
+	FindSessions(eventID string) ([]*models.AgendaSession, error)
+	FindSession(eventID, id string) (*models.AgendaSession, error)
+	SaveSession(session *models.AgendaSession, speakers []models.Speaker) error
+	DeleteSession(id string) error
+	FindTracks(eventID string) ([]*models.AgendaTrack, error)
+	FindTrack(eventID, id string) (*models.AgendaTrack, error)
+	SaveTrack(track *models.AgendaTrack) error
+	DeleteTrack(id string) error
+	FindSpeakers(eventID string) ([]*models.Speaker, error)
+	FindSpeaker(eventID, id string) (*models.Speaker, error)
+	FindSpeakersByIDs(eventID string, ids []string) ([]models.Speaker, error)
+	SaveSpeaker(speaker *models.Speaker) error
+	DeleteSpeaker(id string) error
+	FindPersonalSessions(participantID string) ([]*models.AgendaSession, error)
+	AddPersonalItem(participantID, sessionID string) error
+	RemovePersonalItem(participantID, sessionID string) error
+}
+
+type agendaRepository struct {
+	BaseRepository[models.AgendaSession]
+	db *gorm.DB
+}
+
+// NewAgendaRepository creates a new agenda repository
+func NewAgendaRepository(db *gorm.DB) AgendaRepository {
+	baseRepo := NewBaseRepository[models.AgendaSession](db, models.AgendaSession{})
+	return &agendaRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *agendaRepository) sessions() *gorm.DB {
+	return r.db.Preload("Track").Preload("Speakers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("speakers.name")
+	}).Preload("Speakers.Photo")
+}
+
+// FindSessions lists the sessions of an event in schedule order
+func (r *agendaRepository) FindSessions(eventID string) ([]*models.AgendaSession, error) {
+	var sessions []*models.AgendaSession
+	err := r.sessions().
+		Where("event_id = ?", eventID).
+		Order("starts_at").
+		Order("room").
+		Order("id").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *agendaRepository) FindSession(eventID, id string) (*models.AgendaSession, error) {
+	var session models.AgendaSession
+	err := r.sessions().First(&session, "id = ? AND event_id = ?", id, eventID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SaveSession creates or updates a session and replaces its speakers. The room check and
+// the write run under a per-event lock so concurrent edits cannot double-book a room.
+func (r *agendaRepository) SaveSession(session *models.AgendaSession, speakers []models.Speaker) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "agenda:"+session.EventID).Error; err != nil {
+			return err
+		}
+
+		if room := strings.TrimSpace(session.Room); room != "" {
+			query := tx.Where("event_id = ? AND LOWER(TRIM(room)) = LOWER(?)", session.EventID, room).
+				Where("starts_at < ? AND ends_at > ?", session.EndsAt, session.StartsAt)
+			if session.ID != "" {
+				query = query.Where("id <> ?", session.ID)
+			}
+
+			var conflict models.AgendaSession
+			err := query.Order("starts_at").First(&conflict).Error
+			if err == nil {
+				return fmt.Errorf("%w: %q in %s from %s to %s", ErrRoomOverlap, conflict.Title, conflict.Room,
+					conflict.StartsAt.UTC().Format("2006-01-02T15:04Z"), conflict.EndsAt.UTC().Format("2006-01-02T15:04Z"))
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if session.ID == "" {
+			if err := tx.Omit(clause.Associations).Create(session).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Omit(clause.Associations).Save(session).Error; err != nil {
+			return err
+		}
+
+		if speakers == nil {
+			return nil
+		}
+		if err := tx.Exec("DELETE FROM agenda_session_speakers WHERE agenda_session_id = ?", session.ID).Error; err != nil {
+			return err
+		}
+		for _, speaker := range speakers {
+			err := tx.Exec(
+				"INSERT INTO agenda_session_speakers (agenda_session_id, speaker_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				session.ID, speaker.ID,
+			).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteSession removes a session together with its speaker links and personal agenda entries
+func (r *agendaRepository) DeleteSession(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM agenda_session_speakers WHERE agenda_session_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("session_id = ?", id).Delete(&models.PersonalAgendaItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.AgendaSession{}, "id = ?", id).Error
+	})
+}
+
+func (r *agendaRepository) FindTracks(eventID string) ([]*models.AgendaTrack, error) {
+	var tracks []*models.AgendaTrack
+	err := r.db.Where("event_id = ?", eventID).Order("position").Order("name").Find(&tracks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (r *agendaRepository) FindTrack(eventID, id string) (*models.AgendaTrack, error) {
+	var track models.AgendaTrack
+	if err := r.db.First(&track, "id = ? AND event_id = ?", id, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+func (r *agendaRepository) SaveTrack(track *models.AgendaTrack) error {
+	if track.ID == "" {
+		return r.db.Create(track).Error
+	}
+	return r.db.Save(track).Error
+}
+
+// DeleteTrack removes a track; its sessions stay on the agenda without a track
+func (r *agendaRepository) DeleteTrack(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AgendaSession{}).Where("track_id = ?", id).Update("track_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.AgendaTrack{}, "id = ?", id).Error
+	})
+}
+
+func (r *agendaRepository) FindSpeakers(eventID string) ([]*models.Speaker, error) {
+	var speakers []*models.Speaker
+	err := r.db.Preload("Photo").Where("event_id = ?", eventID).Order("name").Order("id").Find(&speakers).Error
+	if err != nil {
+		return nil, err
+	}
+	return speakers, nil
+}
+
+func (r *agendaRepository) FindSpeaker(eventID, id string) (*models.Speaker, error) {
+	var speaker models.Speaker
+	if err := r.db.Preload("Photo").First(&speaker, "id = ? AND event_id = ?", id, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &speaker, nil
+}
+
+// FindSpeakersByIDs returns the speakers of the event among ids
+func (r *agendaRepository) FindSpeakersByIDs(eventID string, ids []string) ([]models.Speaker, error) {
+	speakers := []models.Speaker{}
+	if len(ids) == 0 {
+		return speakers, nil
+	}
+	if err := r.db.Where("event_id = ? AND id IN ?", eventID, ids).Find(&speakers).Error; err != nil {
+		return nil, err
+	}
+	return speakers, nil
+}
+
+func (r *agendaRepository) SaveSpeaker(speaker *models.Speaker) error {
+	if speaker.ID == "" {
+		return r.db.Omit(clause.Associations).Create(speaker).Error
+	}
+	return r.db.Omit(clause.Associations).Save(speaker).Error
+}
+
+// DeleteSpeaker removes a speaker from the event and from every session they presented
+func (r *agendaRepository) DeleteSpeaker(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM agenda_session_speakers WHERE speaker_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Speaker{}, "id = ?", id).Error
+	})
+}
+
+// FindPersonalSessions lists the sessions on a participant's agenda in schedule order
+func (r *agendaRepository) FindPersonalSessions(participantID string) ([]*models.AgendaSession, error) {
+	var sessions []*models.AgendaSession
+	err := r.sessions().
+		Where("id IN (SELECT session_id FROM personal_agenda_items WHERE participant_id = ? AND deleted_at IS NULL)", participantID).
+		Order("starts_at").
+		Order("id").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *agendaRepository) AddPersonalItem(participantID, sessionID string) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.PersonalAgendaItem{
+		ParticipantID: participantID,
+		SessionID:     sessionID,
+	}).Error
+}
+
+func (r *agendaRepository) RemovePersonalItem(participantID, sessionID string) error {
+	return r.db.Unscoped().
+		Where("participant_id = ? AND session_id = ?", participantID, sessionID).
+		Delete(&models.PersonalAgendaItem{}).Error
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/pkg/slug"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CategoryRepository defines the interface for category data operations
+type CategoryRepository interface {
+	BaseRepository[models.Category]
+	FindAll() ([]*models.Category, error)
+	FindBySlug(slug string) (*models.Category, error)
+	FindByIDs(ids []string) ([]*models.Category, error)
+	FindOrCreatePath(names []string) (*models.Category, error)
+	CountPublicEvents() (map[string]int64, error)
+	ReplaceEventCategories(event *models.Event, categories []*models.Category) error
+}
+
+type categoryRepository struct {
+	BaseRepository[models.Category]
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	baseRepo := NewBaseRepository[models.Category](db, models.Category{})
+	return &categoryRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *categoryRepository) FindAll() ([]*models.Category, error) {
+	var categories []*models.Category
+	if err := r.db.Order("slug").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.First(&category, "slug = ?", slug).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindByIDs(ids []string) ([]*models.Category, error) {
+	if len(ids) == 0 {
+		return []*models.Category{}, nil
+	}
+
+	var categories []*models.Category
+	if err := r.db.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// FindOrCreatePath walks a root-to-leaf list of names, creating missing nodes,
+// and returns the deepest category
+func (r *categoryRepository) FindOrCreatePath(names []string) (*models.Category, error) {
+	var parent *models.Category
+	segments := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		segment := slug.Make(name)
+		if segment == "" {
+			continue
+		}
+		segments = append(segments, segment)
+
+		category := models.Category{
+			Name: name,
+			Slug: strings.Join(segments, "/"),
+		}
+		if parent != nil {
+			category.ParentID = &parent.ID
+		}
+
+		err := r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoNothing: true,
+		}).Create(&category).Error
+		if err != nil {
+			return nil, err
+		}
+
+		// Re-read so concurrent imports converge on the same row
+		var stored models.Category
+		if err := r.db.First(&stored, "slug = ?", category.Slug).Error; err != nil {
+			return nil, err
+		}
+		parent = &stored
+	}
+
+	return parent, nil
+}
+
+// CountPublicEvents returns, per category ID, the number of non-draft events
+// linked to the category or any of its descendants
+func (r *categoryRepository) CountPublicEvents() (map[string]int64, error) {
+	type row struct {
+		ID    string
+		Count int64
+	}
+
+	var rows []row
+	err := r.db.Raw(`
+		SELECT c.id AS id, COUNT(DISTINCT e.id) AS count
+		FROM categories c
+		JOIN categories d ON (d.slug = c.slug OR d.slug LIKE c.slug || '/%') AND d.deleted_at IS NULL
+		LEFT JOIN event_categories ec ON ec.category_id = d.id
+		LEFT JOIN events e ON e.id = ec.event_id AND e.deleted_at IS NULL AND e.status <> ?
+		WHERE c.deleted_at IS NULL
+		GROUP BY c.id`, models.EventStatusDraft).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *categoryRepository) ReplaceEventCategories(event *models.Event, categories []*models.Category) error {
+	return r.db.Model(event).Association("Categories").Replace(categories)
+}
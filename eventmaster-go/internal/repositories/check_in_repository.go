@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckInCountRow counts the registered and checked-in participants of one ticket type;
+// TicketTypeID is nil for participants registered without a ticket type
+type CheckInCountRow struct {
+	TicketTypeID *string `json:"ticketTypeId"`
+	Registered   int64   `json:"registered"`
+	CheckedIn    int64   `json:"checkedIn"`
+}
+
+// CheckInRepository defines the interface for check-in data operations
+type CheckInRepository interface {
+	BaseRepository[models.CheckIn]
+	CreateOnce(checkIn *models.CheckIn) (*models.CheckIn, bool, error)
+	Reconcile(checkIn *models.CheckIn) (*models.CheckIn, error)
+	FindByEventID(eventID string) ([]*models.CheckIn, error)
+	FindByParticipantID(participantID string) (*models.CheckIn, error)
+	CountsByTicketType(eventID string) ([]*CheckInCountRow, error)
+	LastCheckInAt(eventID string) (*time.Time, error)
+}
+
+type checkInRepository struct {
+	BaseRepository[models.CheckIn]
+	db *gorm.DB
+}
+
+// NewCheckInRepository creates a new check-in repository
+func NewCheckInRepository(db *gorm.DB) CheckInRepository {
+	baseRepo := NewBaseRepository[models.CheckIn](db, models.CheckIn{})
+	return &checkInRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// CreateOnce saves the check-in unless the participant is already checked in, in which case
+// the earlier check-in is returned with created set to false. The unique participant index
+// settles concurrent scans of the same ticket.
+func (r *checkInRepository) CreateOnce(checkIn *models.CheckIn) (*models.CheckIn, bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "participant_id"}},
+		DoNothing: true,
+	}).Create(checkIn)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return checkIn, true, nil
+	}
+
+	existing, err := r.FindByParticipantID(checkIn.ParticipantID)
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+// Reconcile records a check-in scanned offline and returns the check-in kept for the
+// participant. The scan replaces a recorded check-in it precedes, in a single conditional
+// upsert, so uploads from several kiosks end in the same state whatever order they arrive in.
+func (r *checkInRepository) Reconcile(checkIn *models.CheckIn) (*models.CheckIn, error) {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "participant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"checked_in_at", "scanned_by", "device_id", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL: "check_ins.checked_in_at > excluded.checked_in_at OR " +
+				"(check_ins.checked_in_at = excluded.checked_in_at AND check_ins.device_id > excluded.device_id)",
+		}}},
+	}).Create(checkIn).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByParticipantID(checkIn.ParticipantID)
+}
+
+// FindByEventID lists the check-ins of an event, most recent first
+func (r *checkInRepository) FindByEventID(eventID string) ([]*models.CheckIn, error) {
+	var checkIns []*models.CheckIn
+	err := r.db.Where("event_id = ?", eventID).
+		Order("checked_in_at DESC").
+		Order("id").
+		Find(&checkIns).Error
+	if err != nil {
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+func (r *checkInRepository) FindByParticipantID(participantID string) (*models.CheckIn, error) {
+	var checkIn models.CheckIn
+	if err := r.db.First(&checkIn, "participant_id = ?", participantID).Error; err != nil {
+		return nil, err
+	}
+	return &checkIn, nil
+}
+
+// CountsByTicketType counts the active participants of an event and how many of them have
+// checked in, per ticket type
+func (r *checkInRepository) CountsByTicketType(eventID string) ([]*CheckInCountRow, error) {
+	var rows []*CheckInCountRow
+	err := r.db.Model(&models.Participant{}).
+		Select("participants.ticket_type_id, COUNT(*) AS registered, COUNT(check_ins.id) AS checked_in").
+		Joins("LEFT JOIN check_ins ON check_ins.participant_id = participants.id AND check_ins.deleted_at IS NULL").
+		Where("participants.event_id = ?", eventID).
+		Group("participants.ticket_type_id").
+		Order("participants.ticket_type_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// LastCheckInAt returns when the latest check-in of the event happened, or nil before the first
+func (r *checkInRepository) LastCheckInAt(eventID string) (*time.Time, error) {
+	var last *time.Time
+	err := r.db.Model(&models.CheckIn{}).
+		Select("MAX(checked_in_at)").
+		Where("event_id = ?", eventID).
+		Scan(&last).Error
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyCollaborator is returned when inviting an email that already collaborates on the event
+	ErrAlreadyCollaborator = errors.New("already a collaborator on this event")
+	// ErrInviteNotPending is returned when an invitation was accepted or withdrawn in the meantime
+	ErrInviteNotPending = errors.New("invitation is no longer pending")
+)
+
+// CollaboratorRepository defines the interface for event collaborator data operations
+type CollaboratorRepository interface {
+	BaseRepository[models.Collaborator]
+	FindRole(eventID, userID string) (models.CollaboratorRole, error)
+	FindByEventID(eventID string) ([]*models.Collaborator, error)
+	FindForEvent(eventID, id string) (*models.Collaborator, error)
+	FindByInviteTokenHash(tokenHash string) (*models.Collaborator, error)
+	FindActiveByUserID(userID string) ([]*models.Collaborator, error)
+	Invite(collaborator *models.Collaborator) error
+	Accept(collaborator *models.Collaborator, userID string, now time.Time) error
+	UpdateRole(collaborator *models.Collaborator, role models.CollaboratorRole) error
+	Remove(id string) error
+}
+
+// collaboratingEventIDs selects the events a user actively collaborates on with one of the
+// given roles. It takes the user ID, the active status and the roles as arguments.
+const collaboratingEventIDs = "SELECT c.event_id FROM collaborators c WHERE c.user_id = ? AND c.status = ? AND c.role IN ? AND c.deleted_at IS NULL"
+
+type collaboratorRepository struct {
+	BaseRepository[models.Collaborator]
+	db *gorm.DB
+}
+
+// NewCollaboratorRepository creates a new collaborator repository
+func NewCollaboratorRepository(db *gorm.DB) CollaboratorRepository {
+	baseRepo := NewBaseRepository[models.Collaborator](db, models.Collaborator{})
+	return &collaboratorRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// FindRole returns the role of an active collaborator, or gorm.ErrRecordNotFound when the user
+// does not collaborate on the event
+func (r *collaboratorRepository) FindRole(eventID, userID string) (models.CollaboratorRole, error) {
+	var collaborator models.Collaborator
+	err := r.db.Select("role").
+		Where("event_id = ? AND user_id = ? AND status = ?", eventID, userID, models.CollaboratorStatusActive).
+		First(&collaborator).Error
+	if err != nil {
+		return "", err
+	}
+	return collaborator.Role, nil
+}
+
+// FindByEventID lists the collaborators and pending invitations of an event, oldest first
+func (r *collaboratorRepository) FindByEventID(eventID string) ([]*models.Collaborator, error) {
+	var collaborators []*models.Collaborator
+	err := r.db.Where("event_id = ?", eventID).Order("created_at, id").Find(&collaborators).Error
+	return collaborators, err
+}
+
+// FindForEvent finds a collaborator only if it belongs to the event
+func (r *collaboratorRepository) FindForEvent(eventID, id string) (*models.Collaborator, error) {
+	var collaborator models.Collaborator
+	err := r.db.Where("id = ? AND event_id = ?", id, eventID).First(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *collaboratorRepository) FindByInviteTokenHash(tokenHash string) (*models.Collaborator, error) {
+	var collaborator models.Collaborator
+	err := r.db.Where("invite_token_hash = ?", tokenHash).First(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// FindActiveByUserID lists the events a user collaborates on, newest collaboration first
+func (r *collaboratorRepository) FindActiveByUserID(userID string) ([]*models.Collaborator, error) {
+	var collaborators []*models.Collaborator
+	err := r.db.Where("user_id = ? AND status = ?", userID, models.CollaboratorStatusActive).
+		Order("accepted_at DESC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+// Invite stores an invitation. Inviting an email with a pending invitation replaces its role,
+// token and expiry, so only the latest invitation email works; inviting an active collaborator
+// fails with ErrAlreadyCollaborator.
+func (r *collaboratorRepository) Invite(collaborator *models.Collaborator) error {
+	collaborator.Email = strings.ToLower(strings.TrimSpace(collaborator.Email))
+	collaborator.Status = models.CollaboratorStatusInvited
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "collaborator:"+collaborator.EventID+":"+collaborator.Email).Error; err != nil {
+			return err
+		}
+
+		var existing models.Collaborator
+		err := tx.Where("event_id = ? AND email = ?", collaborator.EventID, collaborator.Email).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(collaborator).Error
+		}
+		if err != nil {
+			return err
+		}
+		if existing.Status != models.CollaboratorStatusInvited {
+			return ErrAlreadyCollaborator
+		}
+
+		existing.Role = collaborator.Role
+		existing.InvitedBy = collaborator.InvitedBy
+		existing.InviteTokenHash = collaborator.InviteTokenHash
+		existing.InviteExpiresAt = collaborator.InviteExpiresAt
+		if err := tx.Save(&existing).Error; err != nil {
+			return err
+		}
+		*collaborator = existing
+		return nil
+	})
+}
+
+// Accept activates a pending invitation for userID. The invitation is claimed with a
+// conditional update, so a token can be used once; a user already collaborating on the event
+// gets ErrAlreadyCollaborator.
+func (r *collaboratorRepository) Accept(collaborator *models.Collaborator, userID string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&models.Collaborator{}).
+			Where("event_id = ? AND user_id = ? AND id <> ?", collaborator.EventID, userID, collaborator.ID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyCollaborator
+		}
+
+		result := tx.Model(&models.Collaborator{}).
+			Where("id = ? AND status = ? AND invite_token_hash = ?", collaborator.ID, models.CollaboratorStatusInvited, collaborator.InviteTokenHash).
+			Updates(map[string]any{
+				"status":            models.CollaboratorStatusActive,
+				"user_id":           userID,
+				"accepted_at":       now,
+				"invite_token_hash": nil,
+				"invite_expires_at": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInviteNotPending
+		}
+
+		collaborator.Status = models.CollaboratorStatusActive
+		collaborator.UserID = &userID
+		collaborator.AcceptedAt = &now
+		collaborator.InviteTokenHash = nil
+		collaborator.InviteExpiresAt = nil
+		return nil
+	})
+}
+
+func (r *collaboratorRepository) UpdateRole(collaborator *models.Collaborator, role models.CollaboratorRole) error {
+	err := r.db.Model(&models.Collaborator{}).Where("id = ?", collaborator.ID).Update("role", role).Error
+	if err != nil {
+		return err
+	}
+	collaborator.Role = role
+	return nil
+}
+
+// Remove deletes a collaborator or withdraws an invitation for good, so the email can be
+// invited again
+func (r *collaboratorRepository) Remove(id string) error {
+	return r.db.Unscoped().Delete(&models.Collaborator{}, "id = ?", id).Error
+}
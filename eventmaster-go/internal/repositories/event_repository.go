@@ -1,7 +1,11 @@
 package repositories
 
 import (
+	"errors"
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
+	"eventmaster-go/pkg/slug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +20,142 @@ type EventRepository interface {
 	FindByUserID(userID string) ([]*models.Event, error)
 	FindWithImages(id string) (*models.Event, error)
 	FindByExternalID(externalID string) (*models.Event, error)
-	FindPaginated(page, limit int, sortBy, sortOrder string) ([]*models.Event, int64, error)
+	FindPaginated(filter EventFilter, q query.Query, page, limit int) ([]*models.Event, int64, error)
+	StreamFiltered(filter EventFilter, q query.Query, fn func(row *EventExportRow) error) error
+	UpdateWithRevision(event *models.Event, revision *models.EventRevision) error
+	SoftDeleteWithParticipants(id string, expectedVersion int) error
+	FindDeleted(userID string) ([]*models.Event, error)
+	FindDeletedByID(id string) (*models.Event, error)
+	Restore(id string) error
+	Purge(id string) error
+	FindDeletedBefore(before time.Time) ([]string, error)
+	MigrateImportedDescriptions(name string, convert func(string) string) (int64, error)
+	FindBySlug(slug string) (*models.Event, error)
+	FindWithoutSlug(limit int) ([]*models.Event, error)
+	AssignSlug(event *models.Event) error
+	FindRelated(source *models.Event, now time.Time, limit int) ([]*RelatedEvent, error)
+	FindRecommendableIDs(ids []string) ([]string, error)
+}
+
+// RelatedEvent is an event recommended alongside another, with how well it matches
+type RelatedEvent struct {
+	Event *models.Event
+	// Score weighs topic, distance, date and audience overlap; higher is more related
+	Score      float64
+	DistanceKm float64
+}
+
+// ErrVersionConflict is returned when an event was modified since the version the caller read
+var ErrVersionConflict = errors.New("event was modified concurrently")
+
+// eventJoinTables lists the tables holding rows keyed by event_id that must be
+// cleared when an event is permanently removed
+var eventJoinTables = []string{
+	"event_images", "event_categories", "event_tags", "event_revisions",
+	"agenda_sessions", "agenda_tracks", "speakers", "ticket_types", "orders",
+	"promo_codes", "holds", "check_ins", "collaborators", "event_slugs", "share_links",
+}
+
+// eventSessionTables lists the tables holding rows keyed by the event's agenda sessions
+var eventSessionTables = map[string]string{
+	"agenda_session_speakers": "agenda_session_id",
+	"personal_agenda_items":   "session_id",
+}
+
+// eventOrderTables lists the tables holding rows keyed by the event's orders
+var eventOrderTables = []string{"order_items", "promo_redemptions"}
+
+// EventFields is the whitelist of event fields accepted by filter and sort parameters
+var EventFields = query.Fields{
+	"title":       {Column: "title", Type: query.TypeString},
+	"organizer":   {Column: "organizer", Type: query.TypeString},
+	"eventDate":   {Column: "event_date", Type: query.TypeTime},
+	"timeZone":    {Column: "time_zone", Type: query.TypeString},
+	"latitude":    {Column: "latitude", Type: query.TypeNumber},
+	"longitude":   {Column: "longitude", Type: query.TypeNumber},
+	"location":    {Column: "location", Type: query.TypeString, NoSort: true},
+	"eventType":   {Column: "event_type", Type: query.TypeString},
+	"venueId":     {Column: "venue_id", Type: query.TypeString, NoSort: true},
+	"organizerId": {Column: "organizer_id", Type: query.TypeString, NoSort: true},
+	"externalId":  {Column: "external_id", Type: query.TypeString},
+	"isExternal":  {Column: "is_external", Type: query.TypeBool},
+	"status": {Column: "status", Type: query.TypeString, Values: []string{
+		string(models.EventStatusDraft), string(models.EventStatusPublished),
+		string(models.EventStatusCancelled), string(models.EventStatusPostponed),
+	}},
+	"createdAt": {Column: "created_at", Type: query.TypeTime},
+	"updatedAt": {Column: "updated_at", Type: query.TypeTime},
+}
+
+// orderTicketTypes lists ticket types in the order the organizer arranged them
+func orderTicketTypes(db *gorm.DB) *gorm.DB {
+	return db.Order("position").Order("price_cents").Order("created_at")
+}
+
+// defaultEventOrder lists upcoming events first when no sort is requested
+var defaultEventOrder = query.Order{Column: "event_date"}
+
+// EventFilter narrows the set of events returned by list queries
+type EventFilter struct {
+	From *time.Time
+	To   *time.Time
+	// Status restricts results to one lifecycle state. Drafts are never listed
+	// unless Status is draft, and then only those ViewerID owns or collaborates on.
+	Status   models.EventStatus
+	ViewerID string
+	// CategorySlug matches the category and all of its descendants
+	CategorySlug string
+	TagSlug      string
+	VenueID      string
+	OrganizerID  string
+	// City matches the city of the linked venue, ignoring case
+	City string
+}
+
+func (f EventFilter) apply(query *gorm.DB) *gorm.DB {
+	switch f.Status {
+	case "":
+		query = query.Where("status <> ?", models.EventStatusDraft)
+	case models.EventStatusDraft:
+		if f.ViewerID == "" {
+			return query.Where("1 = 0")
+		}
+		query = query.Where(
+			"status = ? AND (user_id = ? OR events.id IN ("+collaboratingEventIDs+"))",
+			models.EventStatusDraft, f.ViewerID,
+			f.ViewerID, models.CollaboratorStatusActive, models.RolesIncluding(models.CollaboratorRoleViewer),
+		)
+	default:
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.CategorySlug != "" {
+		query = query.Where(
+			"events.id IN (SELECT ec.event_id FROM event_categories ec JOIN categories c ON c.id = ec.category_id WHERE c.slug = ? OR c.slug LIKE ?)",
+			f.CategorySlug, f.CategorySlug+"/%",
+		)
+	}
+	if f.TagSlug != "" {
+		query = query.Where(
+			"events.id IN (SELECT et.event_id FROM event_tags et JOIN tags t ON t.id = et.tag_id WHERE t.slug = ?)",
+			f.TagSlug,
+		)
+	}
+	if f.VenueID != "" {
+		query = query.Where("venue_id = ?", f.VenueID)
+	}
+	if f.OrganizerID != "" {
+		query = query.Where("organizer_id = ?", f.OrganizerID)
+	}
+	if f.City != "" {
+		query = query.Where("events.venue_id IN (SELECT v.id FROM venues v WHERE LOWER(v.city) = LOWER(?))", f.City)
+	}
+	if f.From != nil {
+		query = query.Where("event_date >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("event_date < ?", *f.To)
+	}
+	return query
 }
 
 type eventRepository struct {
@@ -38,6 +177,11 @@ func (r *eventRepository) FindByDateRange(start, end time.Time) ([]*models.Event
 	err := r.db.Where("event_date BETWEEN ? AND ?", start, end).
 		Preload("Images").
 		Preload("User").
+		Preload("Categories").
+		Preload("Tags").
+		Preload("TicketTypes", orderTicketTypes).
+		Preload("Venue").
+		Preload("OrganizerProfile.Logo").
 		Find(&events).Error
 	if err != nil {
 		return nil, err
@@ -50,6 +194,11 @@ func (r *eventRepository) FindByUserID(userID string) ([]*models.Event, error) {
 	err := r.db.Where("user_id = ?", userID).
 		Preload("Images").
 		Preload("User").
+		Preload("Categories").
+		Preload("Tags").
+		Preload("TicketTypes", orderTicketTypes).
+		Preload("Venue").
+		Preload("OrganizerProfile.Logo").
 		Find(&events).Error
 	if err != nil {
 		return nil, err
@@ -58,10 +207,30 @@ func (r *eventRepository) FindByUserID(userID string) ([]*models.Event, error) {
 }
 
 func (r *eventRepository) FindWithImages(id string) (*models.Event, error) {
+	return r.findWithDetails("id = ?", id)
+}
+
+// FindBySlug finds an event by its current slug or, failing that, by a slug it had before
+// its title changed. Callers compare the slugs to tell the two apart.
+func (r *eventRepository) FindBySlug(eventSlug string) (*models.Event, error) {
+	event, err := r.findWithDetails("slug = ?", eventSlug)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return event, err
+	}
+	return r.findWithDetails("id = (SELECT event_id FROM event_slugs WHERE slug = ?)", eventSlug)
+}
+
+func (r *eventRepository) findWithDetails(condition string, args ...interface{}) (*models.Event, error) {
 	var event models.Event
 	err := r.db.Preload("Images").
 		Preload("User").
-		First(&event, "id = ?", id).Error
+		Preload("Categories").
+		Preload("Tags").
+		Preload("TicketTypes", orderTicketTypes).
+		Preload("Venue").
+		Preload("OrganizerProfile.Logo").
+		Where(condition, args...).
+		First(&event).Error
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +250,9 @@ func (r *eventRepository) FindByExternalID(externalID string) (*models.Event, er
 	return &event, nil
 }
 
-func (r *eventRepository) FindPaginated(page, limit int, sortBy, sortOrder string) ([]*models.Event, int64, error) {
+// FindPaginated returns a page of events matching filter and q, ordered by q with
+// the ID as a tie-breaker so pages are stable
+func (r *eventRepository) FindPaginated(filter EventFilter, q query.Query, page, limit int) ([]*models.Event, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -92,39 +263,546 @@ func (r *eventRepository) FindPaginated(page, limit int, sortBy, sortOrder strin
 	offset := (page - 1) * limit
 
 	var total int64
-	if err := r.db.Model(&models.Event{}).Count(&total).Error; err != nil {
+	if err := q.Where(filter.apply(r.db.Model(&models.Event{}))).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	sortColumnMap := map[string]string{
-		"eventDate": "event_date",
-		"createdAt": "created_at",
-		"updatedAt": "updated_at",
-		"title":     "title",
+	var events []*models.Event
+	err := q.Where(filter.apply(r.db)).Preload("Images").
+		Preload("User").
+		Preload("Categories").
+		Preload("Tags").
+		Preload("TicketTypes", orderTicketTypes).
+		Preload("Venue").
+		Preload("OrganizerProfile.Logo").
+		Order(q.OrderBy(defaultEventOrder)).
+		Order("id").
+		Offset(offset).
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
 	}
 
-	columnName := "event_date"
-	if mapped, ok := sortColumnMap[sortBy]; ok {
-		columnName = mapped
-	} else if sortBy != "" {
-		columnName = sortBy
+	return events, total, nil
+}
+
+// Create stores a new event and gives it a slug made from its title
+func (r *eventRepository) Create(event *models.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		return assignSlug(tx, event)
+	})
+}
+
+// Update saves the event only if it still has the version it was read at,
+// returning ErrVersionConflict otherwise. A changed title moves the event to a new slug.
+func (r *eventRepository) Update(event *models.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, event); err != nil {
+			return err
+		}
+		return assignSlug(tx, event)
+	})
+}
+
+// EventExportRow is an event flattened for export, with its category and tag names
+// joined by semicolons
+type EventExportRow struct {
+	models.Event
+	CategoryNames string
+	TagNames      string
+}
+
+// StreamFiltered calls fn for every event matching filter in sort order. Rows are read
+// through a cursor so memory use does not grow with the size of the result.
+func (r *eventRepository) StreamFiltered(filter EventFilter, q query.Query, fn func(row *EventExportRow) error) error {
+	rows, err := q.Where(filter.apply(r.db.Model(&models.Event{}))).
+		Select(`events.*,
+			COALESCE((SELECT string_agg(c.name, ';' ORDER BY c.slug) FROM event_categories ec
+				JOIN categories c ON c.id = ec.category_id WHERE ec.event_id = events.id), '') AS category_names,
+			COALESCE((SELECT string_agg(t.name, ';' ORDER BY t.slug) FROM event_tags et
+				JOIN tags t ON t.id = et.tag_id WHERE et.event_id = events.id), '') AS tag_names`).
+		Order(q.OrderBy(defaultEventOrder)).
+		Order("events.id").
+		Rows()
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
 
-	orderClause := clause.OrderByColumn{
-		Column: clause.Column{Name: columnName},
-		Desc:   strings.EqualFold(sortOrder, "DESC"),
+	for rows.Next() {
+		var row EventExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// UpdateWithRevision saves the event and records the revision atomically. The categories and
+// tags are replaced when the revision changes them.
+func (r *eventRepository) UpdateWithRevision(event *models.Event, revision *models.EventRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, event); err != nil {
+			return err
+		}
+		if _, ok := revision.Changes["categoryIds"]; ok {
+			if err := tx.Model(event).Association("Categories").Replace(event.Categories); err != nil {
+				return err
+			}
+		}
+		if _, ok := revision.Changes["tags"]; ok {
+			if err := tx.Model(event).Association("Tags").Replace(event.Tags); err != nil {
+				return err
+			}
+		}
+		if err := assignSlug(tx, event); err != nil {
+			return err
+		}
+		return appendRevision(tx, revision)
+	})
+}
+
+// saveVersioned writes every column of event guarded by its current version and bumps it
+func saveVersioned(tx *gorm.DB, event *models.Event) error {
+	expected := event.Version
+	event.Version = expected + 1
+
+	result := tx.Model(event).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "ID", "Slug", "CreatedAt", "DeletedAt").
+		Updates(event)
+	if result.Error != nil {
+		event.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		event.Version = expected
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// SoftDeleteWithParticipants moves an event and its active participants to the trash
+// with a shared deletion timestamp so they can be restored together. A non-zero
+// expectedVersion makes the delete conditional on the event being unmodified.
+func (r *eventRepository) SoftDeleteWithParticipants(id string, expectedVersion int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		query := tx.Model(&models.Event{}).Where("id = ?", id)
+		if expectedVersion != 0 {
+			query = query.Where("version = ?", expectedVersion)
+		}
+		result := query.Update("deleted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if expectedVersion != 0 {
+				var count int64
+				if err := tx.Model(&models.Event{}).Where("id = ?", id).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return ErrVersionConflict
+				}
+			}
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&models.Participant{}).
+			Where("event_id = ?", id).
+			Update("deleted_at", now).Error
+	})
+}
+
+// FindDeleted lists trashed events, restricted to one owner unless userID is empty
+func (r *eventRepository) FindDeleted(userID string) ([]*models.Event, error) {
+	query := r.db.Unscoped().Where("deleted_at IS NOT NULL")
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
 	}
 
 	var events []*models.Event
-	err := r.db.Preload("Images").
-		Preload("User").
-		Order(orderClause).
-		Offset(offset).
+	err := query.Preload("Images").
+		Order("deleted_at DESC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *eventRepository) FindDeletedByID(id string) (*models.Event, error) {
+	var event models.Event
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&event, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Restore brings a trashed event back together with the participants and
+// images that were removed alongside it
+func (r *eventRepository) Restore(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&event, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&models.Participant{}).
+			Where("event_id = ? AND deleted_at = ?", id, event.DeletedAt.Time).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&models.Image{}).
+			Where("deleted_at IS NOT NULL AND id IN (SELECT image_id FROM event_images WHERE event_id = ?)", id).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&models.Event{}).
+			Where("id = ?", id).
+			Update("deleted_at", nil).Error
+	})
+}
+
+// Purge permanently removes a trashed event with its participants and associations
+func (r *eventRepository) Purge(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for table, column := range eventSessionTables {
+			err := tx.Exec("DELETE FROM "+table+" WHERE "+column+" IN (SELECT id FROM agenda_sessions WHERE event_id = ?)", id).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, table := range eventOrderTables {
+			err := tx.Exec("DELETE FROM "+table+" WHERE order_id IN (SELECT id FROM orders WHERE event_id = ?)", id).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, table := range eventJoinTables {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE event_id = ?", id).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Where("event_id = ?", id).Delete(&models.Participant{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Delete(&models.Event{}).Error
+	})
+}
+
+// FindDeletedBefore returns the IDs of events trashed before the given time
+func (r *eventRepository) FindDeletedBefore(before time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.Unscoped().Model(&models.Event{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// MigrateImportedDescriptions runs the description migration called name unless it has run
+// before. convert is applied to the description of every imported event, trashed ones
+// included, and each description it changes is saved as a new version with a revision by
+// the event's owner. It returns the number of events changed.
+func (r *eventRepository) MigrateImportedDescriptions(name string, convert func(string) string) (int64, error) {
+	var migrated int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The marker is claimed first so concurrent starts cannot both run the migration
+		marker := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DataMigration{Name: name})
+		if marker.Error != nil || marker.RowsAffected == 0 {
+			return marker.Error
+		}
+
+		// The taxonomy is loaded so the recorded snapshots carry it
+		var events []*models.Event
+		err := tx.Unscoped().
+			Preload("Categories").
+			Preload("Tags").
+			Where("external_id IS NOT NULL AND external_id <> ''").
+			Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			description := convert(event.Description)
+			if description == event.Description {
+				continue
+			}
+			before := event.Snapshot()
+			event.Description = description
+			after := event.Snapshot()
+
+			if err := saveVersioned(tx.Unscoped(), event); err != nil {
+				return err
+			}
+			revision := &models.EventRevision{
+				EventID:  event.ID,
+				UserID:   event.UserID,
+				Changes:  before.Diff(after),
+				Snapshot: after,
+			}
+			if err := appendRevision(tx, revision); err != nil {
+				return err
+			}
+			migrated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return migrated, nil
+}
+
+// FindWithoutSlug returns up to limit events, trashed ones included, that have no slug yet
+func (r *eventRepository) FindWithoutSlug(limit int) ([]*models.Event, error) {
+	var events []*models.Event
+	err := r.db.Unscoped().
+		Where("slug IS NULL").
+		Order("created_at").
 		Limit(limit).
 		Find(&events).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	return events, nil
+}
 
-	return events, total, nil
+// AssignSlug gives the event a slug made from its title unless its slug already is one
+func (r *eventRepository) AssignSlug(event *models.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return assignSlug(tx, event)
+	})
+}
+
+// maxSlugLength leaves room for a collision suffix within the slug column
+const maxSlugLength = 90
+
+// reservedSlugs are the static path segments under /api/events. The router matches them
+// before the :id parameter, so an event with one of them as its slug could not be found.
+var reservedSlugs = map[string]bool{
+	"export": true,
+	"import": true,
+}
+
+// assignSlug moves the event to a slug made from its title unless its slug already is one.
+// Slugs taken by other events, now or in the past, and reserved ones get the lowest free
+// numeric suffix. The slug the event leaves keeps pointing to it; returning to one of its
+// own earlier slugs reclaims it.
+func assignSlug(tx *gorm.DB, event *models.Event) error {
+	base := slug.Truncate(slug.Make(slug.Transliterate(event.Title)), maxSlugLength)
+	if base == "" {
+		base = "event"
+	}
+	if event.Slug != nil && *event.Slug == base && !reservedSlugs[base] {
+		return nil
+	}
+
+	// Serialize slug assignment so two events cannot pick the same free suffix
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "event-slugs").Error; err != nil {
+		return err
+	}
+
+	var taken []string
+	err := tx.Raw(`SELECT slug FROM events WHERE id <> ? AND (slug = ? OR slug LIKE ?)
+		UNION SELECT slug FROM event_slugs WHERE event_id <> ? AND (slug = ? OR slug LIKE ?)`,
+		event.ID, base, base+"-%", event.ID, base, base+"-%").
+		Scan(&taken).Error
+	if err != nil {
+		return err
+	}
+	inUse := make(map[string]bool, len(taken)+len(reservedSlugs))
+	for _, s := range taken {
+		inUse[s] = true
+	}
+	for s := range reservedSlugs {
+		inUse[s] = true
+	}
+	// A suffixed slug stays while the plain one is still someone else's
+	if event.Slug != nil && inUse[base] && slugHasBase(*event.Slug, base) {
+		return nil
+	}
+	next := base
+	for n := 2; inUse[next]; n++ {
+		next = base + "-" + strconv.Itoa(n)
+	}
+
+	if event.Slug != nil {
+		previous := &models.EventSlug{EventID: event.ID, Slug: *event.Slug}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(previous).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Unscoped().Where("event_id = ? AND slug = ?", event.ID, next).Delete(&models.EventSlug{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.Event{}).Where("id = ?", event.ID).UpdateColumn("slug", next).Error; err != nil {
+		return err
+	}
+	event.Slug = &next
+	return nil
+}
+
+// slugHasBase reports whether eventSlug is base with a collision suffix
+func slugHasBase(eventSlug, base string) bool {
+	suffix, ok := strings.CutPrefix(eventSlug, base+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// relatedEventsQuery scores upcoming public events against a source event. Each signal is
+// scaled to 0..1 and weighted:
+//
+//   - topic (3): the share of the source's categories the candidate has, or 0.8 for the
+//     same event type when that is higher
+//   - audience (2): people registered for both events, by email, saturating towards 1
+//   - distance (2): 1 at the same place, halving every 50 km
+//   - date (1): 1 on the same day, halving every 30 days
+//
+// Candidates sharing nothing with the source and over 300 km away are left out. A latitude
+// band roughly that wide is checked first so the per-candidate subqueries run on few rows.
+const relatedEventsQuery = `
+WITH src_categories AS (
+	SELECT category_id FROM event_categories WHERE event_id = @id
+), src_emails AS (
+	SELECT DISTINCT LOWER(email) AS email FROM participants WHERE event_id = @id AND deleted_at IS NULL
+), candidates AS (
+	SELECT e.id, e.event_date, e.event_type,
+		6371 * 2 * ASIN(SQRT(
+			POWER(SIN(RADIANS(e.latitude::float8 - @lat) / 2), 2) +
+			COS(RADIANS(@lat)) * COS(RADIANS(e.latitude::float8)) *
+			POWER(SIN(RADIANS(e.longitude::float8 - @lng) / 2), 2)
+		)) AS distance_km
+	FROM events e
+	WHERE e.deleted_at IS NULL AND e.id <> @id
+		AND e.status IN @statuses AND e.event_date >= @now
+		AND (
+			e.latitude BETWEEN @lat - 2.7 AND @lat + 2.7
+			OR (e.event_type <> '' AND e.event_type = @eventType)
+			OR EXISTS (SELECT 1 FROM event_categories ec
+				WHERE ec.event_id = e.id AND ec.category_id IN (SELECT category_id FROM src_categories))
+			OR EXISTS (SELECT 1 FROM participants p
+				WHERE p.event_id = e.id AND p.deleted_at IS NULL AND LOWER(p.email) IN (SELECT email FROM src_emails))
+		)
+), features AS (
+	SELECT c.id, c.distance_km,
+		(c.event_type <> '' AND c.event_type = @eventType) AS same_type,
+		(SELECT COUNT(*) FROM event_categories ec
+			WHERE ec.event_id = c.id AND ec.category_id IN (SELECT category_id FROM src_categories)) AS shared_categories,
+		(SELECT COUNT(DISTINCT LOWER(p.email)) FROM participants p
+			WHERE p.event_id = c.id AND p.deleted_at IS NULL AND LOWER(p.email) IN (SELECT email FROM src_emails)) AS shared_participants,
+		ABS(EXTRACT(EPOCH FROM c.event_date - @eventDate))::float8 / 86400 AS days_apart
+	FROM candidates c
+)
+SELECT id, distance_km,
+	3 * GREATEST(shared_categories::float8 / GREATEST((SELECT COUNT(*) FROM src_categories), 1),
+		CASE WHEN same_type THEN 0.8 ELSE 0 END)
+	+ 2 * shared_participants::float8 / (shared_participants + 3)
+	+ 2 * POWER(0.5, distance_km / 50)
+	+ 1 * POWER(0.5, days_apart / 30) AS score
+FROM features
+WHERE distance_km <= 300 OR same_type OR shared_categories > 0 OR shared_participants > 0
+ORDER BY score DESC, id
+LIMIT @limit`
+
+// FindRelated returns up to limit upcoming, published or postponed events most related to
+// source, best match first
+func (r *eventRepository) FindRelated(source *models.Event, now time.Time, limit int) ([]*RelatedEvent, error) {
+	eventDate := now
+	if source.EventDate != nil {
+		eventDate = *source.EventDate
+	}
+
+	var scored []struct {
+		ID         string
+		DistanceKm float64
+		Score      float64
+	}
+	err := r.db.Raw(relatedEventsQuery, map[string]interface{}{
+		"id":        source.ID,
+		"lat":       source.Latitude,
+		"lng":       source.Longitude,
+		"eventType": source.EventType,
+		"eventDate": eventDate,
+		"now":       now,
+		"statuses":  []models.EventStatus{models.EventStatusPublished, models.EventStatusPostponed},
+		"limit":     limit,
+	}).Scan(&scored).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(scored) == 0 {
+		return []*RelatedEvent{}, nil
+	}
+
+	ids := make([]string, len(scored))
+	for i, row := range scored {
+		ids[i] = row.ID
+	}
+	var events []*models.Event
+	err = r.db.Preload("Images").
+		Preload("Categories").
+		Preload("Tags").
+		Preload("TicketTypes", orderTicketTypes).
+		Preload("Venue").
+		Preload("OrganizerProfile.Logo").
+		Where("id IN ?", ids).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Event, len(events))
+	for _, event := range events {
+		byID[event.ID] = event
+	}
+
+	related := make([]*RelatedEvent, 0, len(scored))
+	for _, row := range scored {
+		if event, ok := byID[row.ID]; ok {
+			related = append(related, &RelatedEvent{Event: event, Score: row.Score, DistanceKm: row.DistanceKm})
+		}
+	}
+	return related, nil
+}
+
+// FindRecommendableIDs returns those of ids that belong to published or postponed events
+// that are not trashed, the events FindRelated may return
+func (r *eventRepository) FindRecommendableIDs(ids []string) ([]string, error) {
+	var found []string
+	if len(ids) == 0 {
+		return found, nil
+	}
+	err := r.db.Model(&models.Event{}).
+		Where("id IN ? AND status IN ?", ids, []models.EventStatus{models.EventStatusPublished, models.EventStatusPostponed}).
+		Pluck("id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
 }
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EventRevisionRepository defines the interface for event revision data operations
+type EventRevisionRepository interface {
+	Append(revision *models.EventRevision) error
+	FindByEventID(eventID string) ([]*models.EventRevision, error)
+	FindByNumber(eventID string, number int) (*models.EventRevision, error)
+}
+
+type eventRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRevisionRepository creates a new event revision repository
+func NewEventRevisionRepository(db *gorm.DB) EventRevisionRepository {
+	return &eventRevisionRepository{db: db}
+}
+
+// Append stores a revision with the next sequential number for its event
+func (r *eventRevisionRepository) Append(revision *models.EventRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return appendRevision(tx, revision)
+	})
+}
+
+func (r *eventRevisionRepository) FindByEventID(eventID string) ([]*models.EventRevision, error) {
+	var revisions []*models.EventRevision
+	err := r.db.Where("event_id = ?", eventID).
+		Order("number DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *eventRevisionRepository) FindByNumber(eventID string, number int) (*models.EventRevision, error) {
+	var revision models.EventRevision
+	err := r.db.First(&revision, "event_id = ? AND number = ?", eventID, number).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// appendRevision numbers and inserts a revision inside tx. The event row is locked
+// so concurrent edits of the same event receive distinct numbers.
+func appendRevision(tx *gorm.DB, revision *models.EventRevision) error {
+	if err := tx.Exec("SELECT 1 FROM events WHERE id = ? FOR UPDATE", revision.EventID).Error; err != nil {
+		return err
+	}
+
+	var last int
+	err := tx.Model(&models.EventRevision{}).
+		Where("event_id = ?", revision.EventID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	revision.Number = last + 1
+	return tx.Create(revision).Error
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EventTemplateRepository defines the interface for event template data operations
+type EventTemplateRepository interface {
+	BaseRepository[models.EventTemplate]
+	FindByUserID(userID string) ([]*models.EventTemplate, error)
+}
+
+type eventTemplateRepository struct {
+	BaseRepository[models.EventTemplate]
+	db *gorm.DB
+}
+
+// NewEventTemplateRepository creates a new event template repository
+func NewEventTemplateRepository(db *gorm.DB) EventTemplateRepository {
+	baseRepo := NewBaseRepository[models.EventTemplate](db, models.EventTemplate{})
+	return &eventTemplateRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *eventTemplateRepository) FindByUserID(userID string) ([]*models.EventTemplate, error) {
+	var templates []*models.EventTemplate
+	err := r.db.Where("user_id = ?", userID).
+		Order("name").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
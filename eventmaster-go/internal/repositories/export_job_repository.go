@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportJobRepository defines the interface for export job data operations
+type ExportJobRepository interface {
+	BaseRepository[models.ExportJob]
+	FindExpired(before time.Time) ([]*models.ExportJob, error)
+	Purge(id string) error
+}
+
+type exportJobRepository struct {
+	BaseRepository[models.ExportJob]
+	db *gorm.DB
+}
+
+// NewExportJobRepository creates a new export job repository
+func NewExportJobRepository(db *gorm.DB) ExportJobRepository {
+	baseRepo := NewBaseRepository[models.ExportJob](db, models.ExportJob{})
+	return &exportJobRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *exportJobRepository) FindExpired(before time.Time) ([]*models.ExportJob, error) {
+	var jobs []*models.ExportJob
+	err := r.db.Where("expires_at < ?", before).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *exportJobRepository) Purge(id string) error {
+	return r.db.Unscoped().Delete(&models.ExportJob{}, "id = ?", id).Error
+}
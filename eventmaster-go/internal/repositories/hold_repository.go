@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrHoldNotActive is returned when a hold has expired or was already converted or released
+var ErrHoldNotActive = errors.New("hold has expired or was already used")
+
+// HoldRepository defines the interface for ticket hold data operations. Every change of a
+// hold's status moves the reserved counter of its ticket type in the same transaction.
+type HoldRepository interface {
+	BaseRepository[models.Hold]
+	Place(hold *models.Hold) error
+	Convert(hold *models.Hold, participant *models.Participant, now time.Time) error
+	Release(hold *models.Hold) error
+	ExpireBefore(now time.Time) (int64, error)
+}
+
+type holdRepository struct {
+	BaseRepository[models.Hold]
+	db *gorm.DB
+}
+
+// NewHoldRepository creates a new hold repository
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	baseRepo := NewBaseRepository[models.Hold](db, models.Hold{})
+	return &holdRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// Place reserves a ticket for the hold. An email keeps at most one active hold per ticket type:
+// placing another returns the existing hold, without extending it, instead of taking a second
+// ticket.
+func (r *holdRepository) Place(hold *models.Hold) error {
+	hold.Email = strings.ToLower(strings.TrimSpace(hold.Email))
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "hold:"+hold.TicketTypeID+":"+hold.Email).Error; err != nil {
+			return err
+		}
+
+		var existing models.Hold
+		err := tx.Where("ticket_type_id = ? AND email = ? AND status = ? AND expires_at > ?",
+			hold.TicketTypeID, hold.Email, models.HoldStatusActive, time.Now()).
+			First(&existing).Error
+		if err == nil {
+			*hold = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := reserveTickets(tx, hold.TicketTypeID, 1); err != nil {
+			return err
+		}
+		hold.Status = models.HoldStatusActive
+		return tx.Create(hold).Error
+	})
+}
+
+// Convert turns an active hold into the participant, moving its ticket from reserved to sold.
+// The hold is claimed with a conditional update, so it converts at most once and never after
+// it expired.
+func (r *holdRepository) Convert(hold *models.Hold, participant *models.Participant, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Hold{}).
+			Where("id = ? AND status = ? AND expires_at > ?", hold.ID, models.HoldStatusActive, now).
+			Updates(map[string]any{"status": models.HoldStatusConverted, "converted_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrHoldNotActive
+		}
+
+		if err := convertReservation(tx, hold.TicketTypeID, 1); err != nil {
+			return err
+		}
+		if err := tx.Create(participant).Error; err != nil {
+			return err
+		}
+		hold.Status = models.HoldStatusConverted
+		hold.ConvertedAt = &now
+		hold.ParticipantID = &participant.ID
+		return tx.Model(&models.Hold{}).Where("id = ?", hold.ID).Update("participant_id", participant.ID).Error
+	})
+}
+
+// Release gives the ticket of an active hold back before it expires
+func (r *holdRepository) Release(hold *models.Hold) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Hold{}).
+			Where("id = ? AND status = ?", hold.ID, models.HoldStatusActive).
+			Update("status", models.HoldStatusReleased)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrHoldNotActive
+		}
+		hold.Status = models.HoldStatusReleased
+		return releaseTickets(tx, hold.TicketTypeID, 1, "reserved")
+	})
+}
+
+// ExpireBefore marks every active hold that expired by now as expired and releases their tickets,
+// returning how many holds expired
+func (r *holdRepository) ExpireBefore(now time.Time) (int64, error) {
+	var expired []models.Hold
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&expired).
+			Clauses(clause.Returning{}).
+			Where("status = ? AND expires_at <= ?", models.HoldStatusActive, now).
+			Update("status", models.HoldStatusExpired).Error
+		if err != nil {
+			return err
+		}
+
+		perTicketType := make(map[string]int)
+		for _, hold := range expired {
+			perTicketType[hold.TicketTypeID]++
+		}
+		for ticketTypeID, quantity := range perTicketType {
+			if err := releaseTickets(tx, ticketTypeID, quantity, "reserved"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(expired)), nil
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrderStateChanged is returned when an order is no longer in the state a transition starts from,
+// for instance because a duplicate webhook already moved it
+var ErrOrderStateChanged = errors.New("order is no longer in the expected state")
+
+// ErrOrderRefunded is returned when restoring a participant whose order was refunded
+var ErrOrderRefunded = errors.New("the participant's order was refunded")
+
+// OrderRepository defines the interface for order data operations. Every transition moves the
+// ticket counters of the order's lines in the same transaction as the status change.
+type OrderRepository interface {
+	BaseRepository[models.Order]
+	FindWithItems(id string) (*models.Order, error)
+	FindByProviderRef(provider, ref string) (*models.Order, error)
+	FindByEventID(eventID string) ([]*models.Order, error)
+	FindStalePending(createdBefore time.Time) ([]*models.Order, error)
+	CreatePending(order *models.Order, promoCode *models.PromoCode) error
+	SetProviderRef(id, provider, ref string) error
+	MarkPaid(order *models.Order, participants []models.Participant) error
+	MarkFailed(order *models.Order) error
+	MarkRefunded(order *models.Order) error
+	MarkLatePaymentRefunded(order *models.Order) error
+}
+
+type orderRepository struct {
+	BaseRepository[models.Order]
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	baseRepo := NewBaseRepository[models.Order](db, models.Order{})
+	return &orderRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *orderRepository) FindWithItems(id string) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.Preload("Items").First(&order, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) FindByProviderRef(provider, ref string) (*models.Order, error) {
+	var order models.Order
+	err := r.db.Preload("Items").First(&order, "provider = ? AND provider_ref = ?", provider, ref).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindByEventID lists the orders of an event, newest first
+func (r *orderRepository) FindByEventID(eventID string) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := r.db.Preload("Items").
+		Where("event_id = ?", eventID).
+		Order("created_at DESC").
+		Order("id").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// FindStalePending lists the orders still pending that were created before the given time
+func (r *orderRepository) FindStalePending(createdBefore time.Time) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := r.db.Preload("Items").
+		Where("status = ? AND created_at < ?", models.OrderStatusPending, createdBefore).
+		Order("created_at").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CreatePending saves a pending order, holds its tickets and redeems its promo code, if any.
+// Nothing is saved when any line cannot be held or the code cannot be redeemed.
+func (r *orderRepository) CreatePending(order *models.Order, promoCode *models.PromoCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range order.Items {
+			if err := reserveTickets(tx, item.TicketTypeID, item.Quantity); err != nil {
+				return err
+			}
+		}
+		order.Status = models.OrderStatusPending
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if promoCode == nil {
+			return nil
+		}
+		return redeemPromoCode(tx, promoCode, &models.PromoRedemption{
+			OrderID:       order.ID,
+			Email:         order.Email,
+			DiscountCents: order.DiscountCents,
+		})
+	})
+}
+
+func (r *orderRepository) SetProviderRef(id, provider, ref string) error {
+	return r.db.Model(&models.Order{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"provider": provider, "provider_ref": ref}).Error
+}
+
+// MarkPaid moves a pending order to paid, turns its held tickets into sold ones and
+// creates its participants
+func (r *orderRepository) MarkPaid(order *models.Order, participants []models.Participant) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionOrder(tx, order, models.OrderStatusPaid, "paid_at", now); err != nil {
+			return err
+		}
+		for _, item := range order.Items {
+			if err := convertReservation(tx, item.TicketTypeID, item.Quantity); err != nil {
+				return err
+			}
+		}
+		if len(participants) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&participants).Error
+	})
+}
+
+// MarkFailed moves a pending order to failed and releases its held tickets and promo code
+func (r *orderRepository) MarkFailed(order *models.Order) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionOrder(tx, order, models.OrderStatusFailed, "failed_at", time.Now()); err != nil {
+			return err
+		}
+		if err := releaseRedemption(tx, order); err != nil {
+			return err
+		}
+		for _, item := range order.Items {
+			if err := releaseTickets(tx, item.TicketTypeID, item.Quantity, "reserved"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MarkRefunded moves a paid order to refunded, trashes the participants it created and
+// puts their tickets back on sale. Participants trashed before gave their ticket back then,
+// so only the tickets of the participants trashed here are released.
+func (r *orderRepository) MarkRefunded(order *models.Order) error {
+	if order.Status != models.OrderStatusPaid {
+		return ErrOrderStateChanged
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionOrder(tx, order, models.OrderStatusRefunded, "refunded_at", time.Now()); err != nil {
+			return err
+		}
+		released := make(map[string]bool, len(order.Items))
+		for _, item := range order.Items {
+			if released[item.TicketTypeID] {
+				continue
+			}
+			released[item.TicketTypeID] = true
+			result := tx.Where("order_id = ? AND ticket_type_id = ?", order.ID, item.TicketTypeID).Delete(&models.Participant{})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			if err := releaseTickets(tx, item.TicketTypeID, int(result.RowsAffected), "sold"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MarkLatePaymentRefunded moves a failed order to refunded once a payment that succeeded
+// after the order expired was refunded. Its tickets went back on sale when it failed, so
+// no counters change.
+func (r *orderRepository) MarkLatePaymentRefunded(order *models.Order) error {
+	if order.Status != models.OrderStatusFailed {
+		return ErrOrderStateChanged
+	}
+	return transitionOrder(r.db, order, models.OrderStatusRefunded, "refunded_at", time.Now())
+}
+
+// transitionOrder changes the status with a conditional update so that concurrent or repeated
+// transitions of the same order apply exactly once
+func transitionOrder(tx *gorm.DB, order *models.Order, next models.OrderStatus, timestampColumn string, at time.Time) error {
+	if !order.Status.CanTransitionTo(next) {
+		return ErrOrderStateChanged
+	}
+
+	result := tx.Model(&models.Order{}).
+		Where("id = ? AND status = ?", order.ID, order.Status).
+		Updates(map[string]any{"status": next, timestampColumn: at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderStateChanged
+	}
+	order.Status = next
+	return nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrganizerRepository defines the interface for organizer profile data operations
+type OrganizerRepository interface {
+	BaseRepository[models.OrganizerProfile]
+	FindWithDetails(id string) (*models.OrganizerProfile, error)
+	FindByManager(userID string) ([]*models.OrganizerProfile, error)
+	CreateWithManager(profile *models.OrganizerProfile, userID string) error
+	FindOrCreateManaged(name, userID string) (*models.OrganizerProfile, error)
+	IsManager(id, userID string) (bool, error)
+	AddManager(id, userID string) error
+	RemoveManager(id, userID string) error
+	CountEvents(id string) (int64, error)
+	FindUnlinkedOrganizers() ([]UnlinkedOrganizer, error)
+	LinkEvents(id, userID, name string) (int64, error)
+}
+
+// UnlinkedOrganizer is an organizer name some user's events carry without a linked profile
+type UnlinkedOrganizer struct {
+	UserID    string
+	Organizer string
+}
+
+const organizerManagersTable = "organizer_managers"
+
+type organizerRepository struct {
+	BaseRepository[models.OrganizerProfile]
+	db *gorm.DB
+}
+
+// NewOrganizerRepository creates a new organizer profile repository
+func NewOrganizerRepository(db *gorm.DB) OrganizerRepository {
+	baseRepo := NewBaseRepository[models.OrganizerProfile](db, models.OrganizerProfile{})
+	return &organizerRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// FindWithDetails loads a profile with its logo and managers
+func (r *organizerRepository) FindWithDetails(id string) (*models.OrganizerProfile, error) {
+	var profile models.OrganizerProfile
+	err := r.db.Preload("Logo").Preload("Managers").First(&profile, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// FindByManager lists the profiles userID manages, by name
+func (r *organizerRepository) FindByManager(userID string) ([]*models.OrganizerProfile, error) {
+	var profiles []*models.OrganizerProfile
+	err := r.db.Preload("Logo").
+		Where("id IN (SELECT organizer_profile_id FROM organizer_managers WHERE user_id = ?)", userID).
+		Order("name").
+		Order("id").
+		Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// Update saves the profile fields without touching its logo or managers
+func (r *organizerRepository) Update(profile *models.OrganizerProfile) error {
+	return r.db.Omit(clause.Associations).Save(profile).Error
+}
+
+// CreateWithManager inserts the profile and makes userID its first manager
+func (r *organizerRepository) CreateWithManager(profile *models.OrganizerProfile, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(profile).Error; err != nil {
+			return err
+		}
+		return addManager(tx, profile.ID, userID)
+	})
+}
+
+// FindOrCreateManaged returns the profile named name, ignoring case, that userID manages,
+// creating it when there is none
+func (r *organizerRepository) FindOrCreateManaged(name, userID string) (*models.OrganizerProfile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("organizer name is empty")
+	}
+
+	var profile models.OrganizerProfile
+	err := r.db.
+		Where("LOWER(name) = LOWER(?)", name).
+		Where("id IN (SELECT organizer_profile_id FROM organizer_managers WHERE user_id = ?)", userID).
+		Order("created_at").
+		First(&profile).Error
+	if err == nil {
+		return &profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	profile = models.OrganizerProfile{Name: name}
+	if err := r.CreateWithManager(&profile, userID); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *organizerRepository) IsManager(id, userID string) (bool, error) {
+	var count int64
+	err := r.db.Table(organizerManagersTable).
+		Where("organizer_profile_id = ? AND user_id = ?", id, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *organizerRepository) AddManager(id, userID string) error {
+	return addManager(r.db, id, userID)
+}
+
+func (r *organizerRepository) RemoveManager(id, userID string) error {
+	return r.db.Exec("DELETE FROM organizer_managers WHERE organizer_profile_id = ? AND user_id = ?", id, userID).Error
+}
+
+// CountEvents counts the events, including trashed ones, that link to the profile
+func (r *organizerRepository) CountEvents(id string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Event{}).Where("organizer_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// FindUnlinkedOrganizers lists each distinct owner and organizer name among events,
+// including trashed ones, that have no profile yet
+func (r *organizerRepository) FindUnlinkedOrganizers() ([]UnlinkedOrganizer, error) {
+	var rows []UnlinkedOrganizer
+	err := r.db.Unscoped().Model(&models.Event{}).
+		Select("DISTINCT user_id, organizer").
+		Where("organizer_id IS NULL AND TRIM(organizer) <> ''").
+		Order("user_id").
+		Order("organizer").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// LinkEvents points every unlinked event of userID carrying organizer name at the profile
+func (r *organizerRepository) LinkEvents(id, userID, name string) (int64, error) {
+	result := r.db.Unscoped().Model(&models.Event{}).
+		Where("organizer_id IS NULL AND user_id = ? AND organizer = ?", userID, name).
+		UpdateColumn("organizer_id", id)
+	return result.RowsAffected, result.Error
+}
+
+func addManager(tx *gorm.DB, id, userID string) error {
+	return tx.Exec(
+		"INSERT INTO organizer_managers (organizer_profile_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		id, userID,
+	).Error
+}
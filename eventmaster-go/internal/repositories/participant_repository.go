@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
 	"gorm.io/gorm"
 	"time"
 )
@@ -9,11 +10,35 @@ import (
 // ParticipantRepository defines the interface for participant data operations
 type ParticipantRepository interface {
 	BaseRepository[models.Participant]
-	FindByEventID(eventID string) ([]*models.Participant, error)
+	FindByEventID(eventID string, q query.Query) ([]*models.Participant, error)
+	StreamByEventID(eventID string, fn func(participant *models.Participant) error) error
 	FindByEmail(email string) ([]*models.Participant, error)
+	FindByOrderID(orderID string) ([]*models.Participant, error)
 	CountByEventID(eventID string) (int64, error)
 	CreateInBatches(participants []models.Participant, batchSize int) error
-	RegistrationsPerDay(eventID string) ([]*RegistrationsPerDayResult, error)
+	CreateWithTicket(participant *models.Participant) error
+	DeleteWithTicket(participant *models.Participant) error
+	RegistrationsPerDay(eventID, timeZone string) ([]*RegistrationsPerDayResult, error)
+	FindDeleted(userID string, role models.CollaboratorRole) ([]*models.Participant, error)
+	FindDeletedByID(id string) (*models.Participant, error)
+	Restore(id string) error
+	Purge(id string) error
+	PurgeDeletedBefore(before time.Time) (int64, error)
+}
+
+// participantJoinTables lists the tables holding rows keyed by participant_id that must be
+// cleared when a participant is permanently removed
+var participantJoinTables = []string{"personal_agenda_items", "check_ins"}
+
+// ParticipantFields is the whitelist of participant fields accepted by filter and sort parameters
+var ParticipantFields = query.Fields{
+	"fullName":    {Column: "full_name", Type: query.TypeString},
+	"email":       {Column: "email", Type: query.TypeString},
+	"dateOfBirth": {Column: "date_of_birth", Type: query.TypeTime},
+	"sourceOfDiscovery": {Column: "source_of_discovery", Type: query.TypeString, Values: []string{
+		string(models.SourceSocialMedia), string(models.SourceFriends), string(models.SourceFoundMyself),
+	}},
+	"createdAt": {Column: "created_at", Type: query.TypeTime},
 }
 
 type participantRepository struct {
@@ -30,15 +55,42 @@ func NewParticipantRepository(db *gorm.DB) ParticipantRepository {
 	}
 }
 
-func (r *participantRepository) FindByEventID(eventID string) ([]*models.Participant, error) {
+func (r *participantRepository) FindByEventID(eventID string, q query.Query) ([]*models.Participant, error) {
 	var participants []*models.Participant
-	err := r.db.Where("event_id = ?", eventID).Find(&participants).Error
+	err := q.Where(r.db.Where("event_id = ?", eventID)).
+		Order(q.OrderBy(query.Order{Column: "created_at"})).
+		Order("id").
+		Find(&participants).Error
 	if err != nil {
 		return nil, err
 	}
 	return participants, nil
 }
 
+// StreamByEventID calls fn for every active participant of an event in registration order,
+// reading rows through a cursor instead of loading them all
+func (r *participantRepository) StreamByEventID(eventID string, fn func(participant *models.Participant) error) error {
+	rows, err := r.db.Model(&models.Participant{}).
+		Where("event_id = ?", eventID).
+		Order("created_at, id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var participant models.Participant
+		if err := r.db.ScanRows(rows, &participant); err != nil {
+			return err
+		}
+		if err := fn(&participant); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *participantRepository) FindByEmail(email string) ([]*models.Participant, error) {
 	var participants []*models.Participant
 	err := r.db.Where("email = ?", email).Find(&participants).Error
@@ -48,6 +100,15 @@ func (r *participantRepository) FindByEmail(email string) ([]*models.Participant
 	return participants, nil
 }
 
+func (r *participantRepository) FindByOrderID(orderID string) ([]*models.Participant, error) {
+	var participants []*models.Participant
+	err := r.db.Where("order_id = ?", orderID).Order("created_at, id").Find(&participants).Error
+	if err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
 func (r *participantRepository) CountByEventID(eventID string) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Participant{}).Where("event_id = ?", eventID).Count(&count).Error
@@ -67,17 +128,46 @@ func (r *participantRepository) CreateInBatches(participants []models.Participan
 	return r.db.CreateInBatches(participants, batchSize).Error
 }
 
+// CreateWithTicket saves a participant, first claiming one ticket of its ticket type when it has
+// one. Both writes share a transaction, so a failed insert gives the ticket back.
+func (r *participantRepository) CreateWithTicket(participant *models.Participant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if participant.TicketTypeID != nil {
+			if err := claimTickets(tx, *participant.TicketTypeID, 1); err != nil {
+				return err
+			}
+		}
+		return tx.Create(participant).Error
+	})
+}
+
+// DeleteWithTicket trashes a participant and gives its ticket back in the same transaction
+func (r *participantRepository) DeleteWithTicket(participant *models.Participant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(participant)
+		if result.Error != nil || result.RowsAffected == 0 || participant.TicketTypeID == nil {
+			return result.Error
+		}
+		return releaseTickets(tx, *participant.TicketTypeID, 1, "sold")
+	})
+}
+
 type RegistrationsPerDayResult struct {
 	Date  time.Time `json:"date"`
 	Count int64     `json:"count"`
 }
 
-func (r *participantRepository) RegistrationsPerDay(eventID string) ([]*RegistrationsPerDayResult, error) {
+// RegistrationsPerDay buckets registrations by calendar day in the given IANA time zone
+func (r *participantRepository) RegistrationsPerDay(eventID, timeZone string) ([]*RegistrationsPerDayResult, error) {
+	if timeZone == "" {
+		timeZone = models.DefaultTimeZone
+	}
+
 	var results []*RegistrationsPerDayResult
 	err := r.db.Model(&models.Participant{}).
-		Select("DATE(created_at) AS date, COUNT(*) AS count").
+		Select("DATE(created_at AT TIME ZONE ?) AS date, COUNT(*) AS count", timeZone).
 		Where("event_id = ?", eventID).
-		Group("DATE(created_at)").
+		Group("date").
 		Order("date").
 		Scan(&results).Error
 	if err != nil {
@@ -85,3 +175,102 @@ func (r *participantRepository) RegistrationsPerDay(eventID string) ([]*Registra
 	}
 	return results, nil
 }
+
+// FindDeleted lists participants trashed individually from active events, restricted
+// to events userID owns or collaborates on with at least role unless userID is empty
+func (r *participantRepository) FindDeleted(userID string, role models.CollaboratorRole) ([]*models.Participant, error) {
+	query := r.db.Unscoped().
+		Joins("JOIN events ON events.id = participants.event_id AND events.deleted_at IS NULL").
+		Where("participants.deleted_at IS NOT NULL")
+	if userID != "" {
+		query = query.Where(
+			"(events.user_id = ? OR events.id IN ("+collaboratingEventIDs+"))",
+			userID, userID, models.CollaboratorStatusActive, models.RolesIncluding(role),
+		)
+	}
+
+	var participants []*models.Participant
+	err := query.Order("participants.deleted_at DESC").Find(&participants).Error
+	if err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (r *participantRepository) FindDeletedByID(id string) (*models.Participant, error) {
+	var participant models.Participant
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&participant, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// Restore brings a trashed participant back and takes its ticket again, failing with
+// ErrSoldOut when the ticket type sold out in the meantime and ErrOrderRefunded when the
+// participant's order was refunded
+func (r *participantRepository) Restore(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var participant models.Participant
+		err := tx.Unscoped().
+			Where("deleted_at IS NOT NULL").
+			First(&participant, "id = ?", id).Error
+		if err != nil {
+			return err
+		}
+		if participant.OrderID != nil {
+			var refunded int64
+			err := tx.Model(&models.Order{}).
+				Where("id = ? AND status = ?", *participant.OrderID, models.OrderStatusRefunded).
+				Count(&refunded).Error
+			if err != nil {
+				return err
+			}
+			if refunded > 0 {
+				return ErrOrderRefunded
+			}
+		}
+		result := tx.Unscoped().Model(&models.Participant{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil || result.RowsAffected == 0 || participant.TicketTypeID == nil {
+			return result.Error
+		}
+		return claimTickets(tx, *participant.TicketTypeID, 1)
+	})
+}
+
+func (r *participantRepository) Purge(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range participantJoinTables {
+			err := tx.Exec("DELETE FROM "+table+" WHERE participant_id IN (SELECT id FROM participants WHERE id = ? AND deleted_at IS NOT NULL)", id).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Delete(&models.Participant{}).Error
+	})
+}
+
+// PurgeDeletedBefore permanently removes participants trashed before the given time
+func (r *participantRepository) PurgeDeletedBefore(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range participantJoinTables {
+			err := tx.Exec("DELETE FROM "+table+" WHERE participant_id IN (SELECT id FROM participants WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before).Error
+			if err != nil {
+				return err
+			}
+		}
+		result := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Delete(&models.Participant{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
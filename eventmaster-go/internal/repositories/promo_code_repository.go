@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPromoCodeExhausted is returned when a promo code has been used as often as allowed
+	ErrPromoCodeExhausted = errors.New("promo code has been fully redeemed")
+	// ErrPromoEmailLimit is returned when a buyer has used a promo code as often as allowed per email
+	ErrPromoEmailLimit = errors.New("promo code has already been used with this email")
+	// ErrRedemptionsAboveMax is returned when a redemption cap would drop below the redemptions made
+	ErrRedemptionsAboveMax = errors.New("maxRedemptions is lower than the redemptions already made")
+	// ErrPromoCodeInUse is returned when deleting a promo code that has been redeemed
+	ErrPromoCodeInUse = errors.New("promo code has been redeemed")
+)
+
+// PromoCodeRepository defines the interface for promo code data operations
+type PromoCodeRepository interface {
+	BaseRepository[models.PromoCode]
+	FindByEventID(eventID string) ([]*models.PromoCode, error)
+	FindForEvent(eventID, id string) (*models.PromoCode, error)
+	FindByCode(eventID, code string) (*models.PromoCode, error)
+	UpdateDetails(promoCode *models.PromoCode) error
+	DeleteUnredeemed(id string) error
+	RedemptionReport(eventID string) ([]*RedemptionReportRow, error)
+}
+
+// RedemptionReportRow counts the redemptions of a promo code by buyers who found the event
+// through one source of discovery
+type RedemptionReportRow struct {
+	PromoCodeID       string                   `json:"promoCodeId"`
+	SourceOfDiscovery models.SourceOfDiscovery `json:"sourceOfDiscovery"`
+	Redemptions       int64                    `json:"redemptions"`
+	PaidOrders        int64                    `json:"paidOrders"`
+	DiscountCents     int64                    `json:"discountCents"`
+	RevenueCents      int64                    `json:"revenueCents"`
+}
+
+type promoCodeRepository struct {
+	BaseRepository[models.PromoCode]
+	db *gorm.DB
+}
+
+// NewPromoCodeRepository creates a new promo code repository
+func NewPromoCodeRepository(db *gorm.DB) PromoCodeRepository {
+	baseRepo := NewBaseRepository[models.PromoCode](db, models.PromoCode{})
+	return &promoCodeRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *promoCodeRepository) FindByEventID(eventID string) ([]*models.PromoCode, error) {
+	var promoCodes []*models.PromoCode
+	err := r.db.Where("event_id = ?", eventID).Order("code").Find(&promoCodes).Error
+	if err != nil {
+		return nil, err
+	}
+	return promoCodes, nil
+}
+
+func (r *promoCodeRepository) FindForEvent(eventID, id string) (*models.PromoCode, error) {
+	var promoCode models.PromoCode
+	if err := r.db.First(&promoCode, "id = ? AND event_id = ?", id, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &promoCode, nil
+}
+
+func (r *promoCodeRepository) FindByCode(eventID, code string) (*models.PromoCode, error) {
+	var promoCode models.PromoCode
+	err := r.db.First(&promoCode, "event_id = ? AND code = ?", eventID, strings.ToUpper(strings.TrimSpace(code))).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promoCode, nil
+}
+
+// UpdateDetails saves everything but the redemption counter, refusing to lower the cap
+// below the redemptions made at the moment of the write
+func (r *promoCodeRepository) UpdateDetails(promoCode *models.PromoCode) error {
+	query := r.db.Model(promoCode).
+		Select("*").
+		Omit("ID", "CreatedAt", "DeletedAt", "EventID", "Redeemed")
+	if promoCode.MaxRedemptions != nil {
+		query = query.Where("redeemed <= ?", *promoCode.MaxRedemptions)
+	}
+
+	result := query.Updates(promoCode)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRedemptionsAboveMax
+	}
+	return nil
+}
+
+// DeleteUnredeemed removes a promo code nobody has used
+func (r *promoCodeRepository) DeleteUnredeemed(id string) error {
+	result := r.db.Where("id = ? AND redeemed = 0", id).Delete(&models.PromoCode{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPromoCodeInUse
+	}
+	return nil
+}
+
+// RedemptionReport groups the redemptions of an event's promo codes by code and by the
+// buyers' source of discovery. Revenue only counts paid orders.
+func (r *promoCodeRepository) RedemptionReport(eventID string) ([]*RedemptionReportRow, error) {
+	var rows []*RedemptionReportRow
+	err := r.db.Model(&models.PromoRedemption{}).
+		Select(`promo_redemptions.promo_code_id,
+			orders.source_of_discovery,
+			COUNT(*) AS redemptions,
+			COUNT(*) FILTER (WHERE orders.status = ?) AS paid_orders,
+			COALESCE(SUM(promo_redemptions.discount_cents), 0) AS discount_cents,
+			COALESCE(SUM(orders.total_cents) FILTER (WHERE orders.status = ?), 0) AS revenue_cents`,
+			models.OrderStatusPaid, models.OrderStatusPaid).
+		Joins("JOIN orders ON orders.id = promo_redemptions.order_id").
+		Where("orders.event_id = ?", eventID).
+		Group("promo_redemptions.promo_code_id, orders.source_of_discovery").
+		Order("promo_redemptions.promo_code_id").
+		Order("orders.source_of_discovery").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// redeemPromoCode records the use of a promo code by an order. The per-email count runs under
+// a lock on the code and email, and the cap is enforced by a conditional update, so concurrent
+// checkouts can never redeem a code more often than allowed.
+func redeemPromoCode(tx *gorm.DB, promoCode *models.PromoCode, redemption *models.PromoRedemption) error {
+	redemption.PromoCodeID = promoCode.ID
+	redemption.Email = strings.ToLower(strings.TrimSpace(redemption.Email))
+
+	if promoCode.PerEmailLimit != nil {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "promo:"+promoCode.ID+":"+redemption.Email).Error; err != nil {
+			return err
+		}
+		var used int64
+		err := tx.Model(&models.PromoRedemption{}).
+			Where("promo_code_id = ? AND email = ?", promoCode.ID, redemption.Email).
+			Count(&used).Error
+		if err != nil {
+			return err
+		}
+		if used >= int64(*promoCode.PerEmailLimit) {
+			return ErrPromoEmailLimit
+		}
+	}
+
+	result := tx.Model(&models.PromoCode{}).
+		Where("id = ? AND (max_redemptions IS NULL OR redeemed < max_redemptions)", promoCode.ID).
+		UpdateColumn("redeemed", gorm.Expr("redeemed + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPromoCodeExhausted
+	}
+
+	return tx.Create(redemption).Error
+}
+
+// releaseRedemption makes the promo code use of an order available again
+func releaseRedemption(tx *gorm.DB, order *models.Order) error {
+	if order.PromoCodeID == nil {
+		return nil
+	}
+	result := tx.Unscoped().Where("order_id = ?", order.ID).Delete(&models.PromoRedemption{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return tx.Model(&models.PromoCode{}).
+		Where("id = ?", *order.PromoCodeID).
+		UpdateColumn("redeemed", gorm.Expr("GREATEST(redeemed - 1, 0)")).Error
+}
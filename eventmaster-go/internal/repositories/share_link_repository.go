@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrShareCodeExhausted is returned when no free share code was found within a few attempts
+var ErrShareCodeExhausted = errors.New("could not generate a unique share code")
+
+// shareCodeAttempts bounds the retries of CreateWithCode; collisions are rare to begin with
+const shareCodeAttempts = 5
+
+// ShareLinkRepository defines the interface for event share link data operations
+type ShareLinkRepository interface {
+	BaseRepository[models.ShareLink]
+	FindByEventID(eventID string) ([]*models.ShareLink, error)
+	FindForEvent(eventID, id string) (*models.ShareLink, error)
+	FindByCode(code string) (*models.ShareLink, error)
+	CreateWithCode(link *models.ShareLink, generate func() (string, error)) error
+	RecordClick(code string, now time.Time) (*models.ShareLink, error)
+	Remove(id string) error
+}
+
+type shareLinkRepository struct {
+	BaseRepository[models.ShareLink]
+	db *gorm.DB
+}
+
+// NewShareLinkRepository creates a new share link repository
+func NewShareLinkRepository(db *gorm.DB) ShareLinkRepository {
+	baseRepo := NewBaseRepository[models.ShareLink](db, models.ShareLink{})
+	return &shareLinkRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// FindByEventID lists the share links of an event, most followed first
+func (r *shareLinkRepository) FindByEventID(eventID string) ([]*models.ShareLink, error) {
+	var links []*models.ShareLink
+	err := r.db.Where("event_id = ?", eventID).Order("clicks DESC, created_at, id").Find(&links).Error
+	return links, err
+}
+
+// FindForEvent finds a share link only if it belongs to the event
+func (r *shareLinkRepository) FindForEvent(eventID, id string) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := r.db.Where("id = ? AND event_id = ?", id, eventID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByCode finds the share link with the code
+func (r *shareLinkRepository) FindByCode(code string) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := r.db.Where("code = ?", code).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CreateWithCode stores the link under a code from generate, drawing a new one while the
+// code is already taken
+func (r *shareLinkRepository) CreateWithCode(link *models.ShareLink, generate func() (string, error)) error {
+	for attempt := 0; attempt < shareCodeAttempts; attempt++ {
+		code, err := generate()
+		if err != nil {
+			return err
+		}
+		link.Code = code
+		result := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).Create(link)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+	}
+	return ErrShareCodeExhausted
+}
+
+// RecordClick counts a visit of the link with the code and returns the updated link
+func (r *shareLinkRepository) RecordClick(code string, now time.Time) (*models.ShareLink, error) {
+	var link models.ShareLink
+	result := r.db.Model(&link).
+		Clauses(clause.Returning{}).
+		Where("code = ?", code).
+		Updates(map[string]interface{}{
+			"clicks":          gorm.Expr("clicks + 1"),
+			"last_clicked_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &link, nil
+}
+
+// Remove deletes a share link for good so its code stops redirecting
+func (r *shareLinkRepository) Remove(id string) error {
+	return r.db.Unscoped().Delete(&models.ShareLink{}, "id = ?", id).Error
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"eventmaster-go/internal/models"
+	"eventmaster-go/pkg/slug"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository defines the interface for tag data operations
+type TagRepository interface {
+	BaseRepository[models.Tag]
+	FindOrCreateByNames(names []string) ([]*models.Tag, error)
+	FindAllWithCounts() ([]*models.Tag, map[string]int64, error)
+	ReplaceEventTags(event *models.Event, tags []*models.Tag) error
+}
+
+type tagRepository struct {
+	BaseRepository[models.Tag]
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	baseRepo := NewBaseRepository[models.Tag](db, models.Tag{})
+	return &tagRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// FindOrCreateByNames resolves tag names case-insensitively, creating any that are missing
+func (r *tagRepository) FindOrCreateByNames(names []string) ([]*models.Tag, error) {
+	slugs := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		tagSlug := slug.Make(name)
+		if tagSlug == "" {
+			continue
+		}
+		if _, exists := seen[tagSlug]; exists {
+			continue
+		}
+		seen[tagSlug] = struct{}{}
+		slugs = append(slugs, tagSlug)
+
+		tag := models.Tag{Name: name, Slug: tagSlug}
+		err := r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoNothing: true,
+		}).Create(&tag).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(slugs) == 0 {
+		return []*models.Tag{}, nil
+	}
+
+	var tags []*models.Tag
+	if err := r.db.Where("slug IN ?", slugs).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FindAllWithCounts returns every tag with the number of non-draft events using it
+func (r *tagRepository) FindAllWithCounts() ([]*models.Tag, map[string]int64, error) {
+	var tags []*models.Tag
+	if err := r.db.Order("slug").Find(&tags).Error; err != nil {
+		return nil, nil, err
+	}
+
+	type row struct {
+		ID    string
+		Count int64
+	}
+
+	var rows []row
+	err := r.db.Raw(`
+		SELECT et.tag_id AS id, COUNT(DISTINCT e.id) AS count
+		FROM event_tags et
+		JOIN events e ON e.id = et.event_id AND e.deleted_at IS NULL AND e.status <> ?
+		GROUP BY et.tag_id`, models.EventStatusDraft).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return tags, counts, nil
+}
+
+func (r *tagRepository) ReplaceEventTags(event *models.Event, tags []*models.Tag) error {
+	return r.db.Model(event).Association("Tags").Replace(tags)
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"errors"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSoldOut is returned when fewer tickets are left than were requested
+	ErrSoldOut = errors.New("not enough tickets left")
+	// ErrQuantityBelowSold is returned when a ticket quantity would drop below the tickets already sold
+	ErrQuantityBelowSold = errors.New("quantity is lower than the tickets already sold")
+	// ErrTicketTypeInUse is returned when deleting a ticket type that has sold tickets
+	ErrTicketTypeInUse = errors.New("ticket type has sold tickets")
+)
+
+// TicketTypeRepository defines the interface for ticket type data operations
+type TicketTypeRepository interface {
+	BaseRepository[models.TicketType]
+	FindByEventID(eventID string) ([]*models.TicketType, error)
+	FindForEvent(eventID, id string) (*models.TicketType, error)
+	UpdateDetails(ticketType *models.TicketType) error
+	DeleteUnsold(id string) error
+}
+
+type ticketTypeRepository struct {
+	BaseRepository[models.TicketType]
+	db *gorm.DB
+}
+
+// NewTicketTypeRepository creates a new ticket type repository
+func NewTicketTypeRepository(db *gorm.DB) TicketTypeRepository {
+	baseRepo := NewBaseRepository[models.TicketType](db, models.TicketType{})
+	return &ticketTypeRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+func (r *ticketTypeRepository) FindByEventID(eventID string) ([]*models.TicketType, error) {
+	var ticketTypes []*models.TicketType
+	err := orderTicketTypes(r.db.Where("event_id = ?", eventID)).Find(&ticketTypes).Error
+	if err != nil {
+		return nil, err
+	}
+	return ticketTypes, nil
+}
+
+func (r *ticketTypeRepository) FindForEvent(eventID, id string) (*models.TicketType, error) {
+	var ticketType models.TicketType
+	if err := r.db.First(&ticketType, "id = ? AND event_id = ?", id, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &ticketType, nil
+}
+
+// UpdateDetails saves everything but the counters, refusing to lower the quantity
+// below the tickets sold or held at the moment of the write
+func (r *ticketTypeRepository) UpdateDetails(ticketType *models.TicketType) error {
+	query := r.db.Model(ticketType).
+		Select("*").
+		Omit("ID", "CreatedAt", "DeletedAt", "EventID", "Sold", "Reserved")
+	if ticketType.Quantity != nil {
+		query = query.Where("sold + reserved <= ?", *ticketType.Quantity)
+	}
+
+	result := query.Updates(ticketType)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuantityBelowSold
+	}
+	return nil
+}
+
+// DeleteUnsold removes a ticket type nobody has bought or holds
+func (r *ticketTypeRepository) DeleteUnsold(id string) error {
+	result := r.db.Where("id = ? AND sold = 0 AND reserved = 0", id).Delete(&models.TicketType{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTicketTypeInUse
+	}
+	return nil
+}
+
+// claimTickets takes quantity tickets of a type in a single conditional update, so
+// concurrent buyers can never push the sold and held tickets past the quantity
+func claimTickets(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return takeTickets(tx, ticketTypeID, quantity, "sold")
+}
+
+// reserveTickets holds quantity tickets of a type for a pending order
+func reserveTickets(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return takeTickets(tx, ticketTypeID, quantity, "reserved")
+}
+
+func takeTickets(tx *gorm.DB, ticketTypeID string, quantity int, counter string) error {
+	result := tx.Model(&models.TicketType{}).
+		Where("id = ? AND (quantity IS NULL OR sold + reserved + ? <= quantity)", ticketTypeID, quantity).
+		UpdateColumn(counter, gorm.Expr(counter+" + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSoldOut
+	}
+	return nil
+}
+
+// convertReservation turns held tickets into sold ones
+func convertReservation(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return tx.Model(&models.TicketType{}).
+		Where("id = ?", ticketTypeID).
+		UpdateColumns(map[string]any{
+			"reserved": gorm.Expr("GREATEST(reserved - ?, 0)", quantity),
+			"sold":     gorm.Expr("sold + ?", quantity),
+		}).Error
+}
+
+// releaseTickets gives held or sold tickets back to the pool
+func releaseTickets(tx *gorm.DB, ticketTypeID string, quantity int, counter string) error {
+	return tx.Model(&models.TicketType{}).
+		Where("id = ?", ticketTypeID).
+		UpdateColumn(counter, gorm.Expr("GREATEST("+counter+" - ?, 0)", quantity)).Error
+}
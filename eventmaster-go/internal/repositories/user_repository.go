@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
 
 	"gorm.io/gorm"
 )
@@ -14,6 +15,14 @@ type UserRepository interface {
 	FindByEmail(email string) (*models.User, error)
 	FindWithAssociations(id string) (*models.User, error)
 	AttachRoleByName(user *models.User, roleName string) error
+	FindPaginated(q query.Query, page, limit int) ([]*models.User, int64, error)
+}
+
+// UserFields is the whitelist of user fields accepted by filter and sort parameters
+var UserFields = query.Fields{
+	"email":     {Column: "email", Type: query.TypeString},
+	"createdAt": {Column: "created_at", Type: query.TypeTime},
+	"updatedAt": {Column: "updated_at", Type: query.TypeTime},
 }
 
 type userRepository struct {
@@ -48,6 +57,34 @@ func (r *userRepository) FindWithAssociations(id string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindPaginated returns a page of users with their roles, newest first unless q sorts otherwise
+func (r *userRepository) FindPaginated(q query.Query, page, limit int) ([]*models.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	var total int64
+	if err := q.Where(r.db.Model(&models.User{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*models.User
+	err := q.Where(r.db).Preload("Roles").
+		Order(q.OrderBy(query.Order{Column: "created_at", Desc: true})).
+		Order("id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
 func (r *userRepository) AttachRoleByName(user *models.User, roleName string) error {
 	if user == nil || user.ID == "" {
 		return errors.New("user must have an ID before attaching roles")
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"eventmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VenueRepository defines the interface for venue data operations
+type VenueRepository interface {
+	BaseRepository[models.Venue]
+	Search(term, city string, limit int) ([]*models.Venue, error)
+	FindOrCreateByExternalID(venue *models.Venue) (*models.Venue, error)
+	CountEvents(id string) (int64, error)
+}
+
+type venueRepository struct {
+	BaseRepository[models.Venue]
+	db *gorm.DB
+}
+
+// NewVenueRepository creates a new venue repository
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	baseRepo := NewBaseRepository[models.Venue](db, models.Venue{})
+	return &venueRepository{
+		BaseRepository: baseRepo,
+		db:             db,
+	}
+}
+
+// Search returns venues whose name or city contains term, optionally restricted to a city,
+// with name prefix matches ranked first for the venue picker
+func (r *venueRepository) Search(term, city string, limit int) ([]*models.Venue, error) {
+	query := r.db.Model(&models.Venue{})
+	if term = strings.TrimSpace(term); term != "" {
+		pattern := "%" + escapeLike(term) + "%"
+		query = query.Where("name ILIKE ? OR city ILIKE ?", pattern, pattern).
+			Order(clause.Expr{SQL: "CASE WHEN name ILIKE ? THEN 0 ELSE 1 END", Vars: []interface{}{escapeLike(term) + "%"}})
+	}
+	if city = strings.TrimSpace(city); city != "" {
+		query = query.Where("LOWER(city) = LOWER(?)", city)
+	}
+
+	var venues []*models.Venue
+	err := query.Order("name").Order("id").Limit(limit).Find(&venues).Error
+	if err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+// FindOrCreateByExternalID returns the venue with venue.ExternalID, inserting venue
+// if there is none. Concurrent imports of the same venue converge on one row. The
+// external ID stays taken while a venue is trashed, so a trashed match is restored.
+func (r *venueRepository) FindOrCreateByExternalID(venue *models.Venue) (*models.Venue, error) {
+	if venue.ExternalID == nil || *venue.ExternalID == "" {
+		return nil, errors.New("venue has no external ID")
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "external_id"}},
+		DoNothing: true,
+	}).Create(venue).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var existing models.Venue
+	if err := r.db.Unscoped().Where("external_id = ?", *venue.ExternalID).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing.DeletedAt.Valid {
+		if err := r.db.Unscoped().Model(&existing).Update("deleted_at", nil).Error; err != nil {
+			return nil, err
+		}
+		existing.DeletedAt = gorm.DeletedAt{}
+	}
+	return &existing, nil
+}
+
+// CountEvents counts the events, including trashed ones, that link to the venue
+func (r *venueRepository) CountEvents(id string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Event{}).Where("venue_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
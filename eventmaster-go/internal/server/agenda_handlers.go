@@ -0,0 +1,434 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// TrackRequest represents the request body for creating or replacing an agenda track
+type TrackRequest struct {
+	Name     string `json:"name" validate:"required,min=1,max=255"`
+	Color    string `json:"color" validate:"omitempty,hexcolor,len=7"`
+	Position int    `json:"position" validate:"gte=0,lte=1000"`
+}
+
+// SpeakerRequest represents the request body for creating or replacing a speaker
+type SpeakerRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=255"`
+	Headline string `json:"headline" validate:"omitempty,max=255"`
+	Bio      string `json:"bio" validate:"omitempty,max=5000"`
+	PhotoID  string `json:"photoId" validate:"omitempty,uuid4"`
+}
+
+// CreateSessionRequest represents the request body for scheduling an agenda session
+type CreateSessionRequest struct {
+	Title       string    `json:"title" validate:"required,min=2,max=255"`
+	Description string    `json:"description" validate:"omitempty,max=5000"`
+	Room        string    `json:"room" validate:"omitempty,max=255"`
+	StartsAt    time.Time `json:"startsAt" validate:"required"`
+	EndsAt      time.Time `json:"endsAt" validate:"required,gtfield=StartsAt"`
+	TrackID     string    `json:"trackId" validate:"omitempty,uuid4"`
+	SpeakerIDs  []string  `json:"speakerIds" validate:"omitempty,unique,max=20,dive,uuid4"`
+}
+
+// UpdateSessionRequest represents the request body for updating a session; omitted fields are
+// kept, an empty trackId removes the track and a speakerIds list replaces the speakers
+type UpdateSessionRequest struct {
+	Title       *string    `json:"title" validate:"omitempty,min=2,max=255"`
+	Description *string    `json:"description" validate:"omitempty,max=5000"`
+	Room        *string    `json:"room" validate:"omitempty,max=255"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	TrackID     *string    `json:"trackId" validate:"omitempty,uuid4|len=0"`
+	SpeakerIDs  []string   `json:"speakerIds" validate:"omitempty,unique,max=20,dive,uuid4"`
+}
+
+// PersonalAgendaRequest names the session a participant adds to their agenda
+type PersonalAgendaRequest struct {
+	SessionID string `json:"sessionId" validate:"required,uuid4"`
+}
+
+// AgendaResponse is the full programme of an event
+type AgendaResponse struct {
+	EventID  string                          `json:"eventId"`
+	Tracks   []*models.AgendaTrackResponse   `json:"tracks"`
+	Speakers []*models.SpeakerResponse       `json:"speakers"`
+	Sessions []*models.AgendaSessionResponse `json:"sessions"`
+}
+
+// PersonalAgendaEntry is a session on a participant's agenda with the IDs of the other
+// picked sessions it clashes with
+type PersonalAgendaEntry struct {
+	*models.AgendaSessionResponse
+	ConflictsWith []string `json:"conflictsWith"`
+}
+
+// PersonalAgendaResponse lists the sessions a participant picked in schedule order
+type PersonalAgendaResponse struct {
+	ParticipantID string                 `json:"participantId"`
+	Sessions      []*PersonalAgendaEntry `json:"sessions"`
+}
+
+// RegisterAgendaHandlers registers agenda and personal agenda HTTP handlers
+func (s *Server) RegisterAgendaHandlers(agendaService services.AgendaService) {
+	agendaGroup := s.apiGroup.Group("/events/:id/agenda")
+
+	agendaGroup.GET("", s.handleGetAgenda(agendaService), s.optionalAuth)
+
+	protected := agendaGroup.Group("")
+	protected.Use(s.requireAuth)
+	{
+		protected.POST("/tracks", s.handleCreateTrack(agendaService))
+		protected.PUT("/tracks/:trackId", s.handleUpdateTrack(agendaService))
+		protected.DELETE("/tracks/:trackId", s.handleDeleteTrack(agendaService))
+		protected.POST("/speakers", s.handleCreateSpeaker(agendaService))
+		protected.PUT("/speakers/:speakerId", s.handleUpdateSpeaker(agendaService))
+		protected.DELETE("/speakers/:speakerId", s.handleDeleteSpeaker(agendaService))
+		protected.POST("/sessions", s.handleCreateSession(agendaService))
+		protected.PUT("/sessions/:sessionId", s.handleUpdateSession(agendaService))
+		protected.DELETE("/sessions/:sessionId", s.handleDeleteSession(agendaService))
+	}
+
+	// Personal agendas are addressed by participant ID like the rest of the participant API
+	// and belong to the user registered with the participant's email
+	personalGroup := s.apiGroup.Group("/participant/:id/agenda")
+	personalGroup.Use(s.requireAuth)
+	personalGroup.GET("", s.handleGetPersonalAgenda(agendaService))
+	personalGroup.POST("", s.handleAddToPersonalAgenda(agendaService))
+	personalGroup.DELETE("/:sessionId", s.handleRemoveFromPersonalAgenda(agendaService))
+}
+
+func (s *Server) handleGetAgenda(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		agenda, err := svc.GetAgenda(c.Param("id"))
+		if err != nil {
+			return agendaError(err, "fetch agenda")
+		}
+		if !s.canViewEvent(c, agenda.Event) {
+			return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+
+		resp := &AgendaResponse{
+			EventID:  agenda.Event.ID,
+			Tracks:   make([]*models.AgendaTrackResponse, len(agenda.Tracks)),
+			Speakers: make([]*models.SpeakerResponse, len(agenda.Speakers)),
+			Sessions: sessionResponses(agenda.Sessions),
+		}
+		for i, track := range agenda.Tracks {
+			resp.Tracks[i] = track.ToResponse()
+		}
+		for i, speaker := range agenda.Speakers {
+			resp.Speakers[i] = speaker.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleCreateTrack(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req TrackRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		track, err := svc.CreateTrack(actorFromContext(c), c.Param("id"), &models.AgendaTrack{
+			Name:     req.Name,
+			Color:    req.Color,
+			Position: req.Position,
+		})
+		if err != nil {
+			return agendaError(err, "create track")
+		}
+
+		return c.JSON(http.StatusCreated, track.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdateTrack(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req TrackRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		track, err := svc.UpdateTrack(actorFromContext(c), c.Param("id"), c.Param("trackId"), func(track *models.AgendaTrack) {
+			track.Name = req.Name
+			track.Color = req.Color
+			track.Position = req.Position
+		})
+		if err != nil {
+			return agendaError(err, "update track")
+		}
+
+		return c.JSON(http.StatusOK, track.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteTrack(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteTrack(actorFromContext(c), c.Param("id"), c.Param("trackId")); err != nil {
+			return agendaError(err, "delete track")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleCreateSpeaker(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req SpeakerRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		speaker := &models.Speaker{
+			Name:     req.Name,
+			Headline: req.Headline,
+			Bio:      req.Bio,
+		}
+		if req.PhotoID != "" {
+			speaker.PhotoID = &req.PhotoID
+		}
+
+		created, err := svc.CreateSpeaker(actorFromContext(c), c.Param("id"), speaker)
+		if err != nil {
+			return agendaError(err, "create speaker")
+		}
+
+		return c.JSON(http.StatusCreated, created.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdateSpeaker(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req SpeakerRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		speaker, err := svc.UpdateSpeaker(actorFromContext(c), c.Param("id"), c.Param("speakerId"), func(speaker *models.Speaker) {
+			speaker.Name = req.Name
+			speaker.Headline = req.Headline
+			speaker.Bio = req.Bio
+			speaker.PhotoID = nil
+			if req.PhotoID != "" {
+				speaker.PhotoID = &req.PhotoID
+			}
+		})
+		if err != nil {
+			return agendaError(err, "update speaker")
+		}
+
+		return c.JSON(http.StatusOK, speaker.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteSpeaker(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteSpeaker(actorFromContext(c), c.Param("id"), c.Param("speakerId")); err != nil {
+			return agendaError(err, "delete speaker")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleCreateSession(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CreateSessionRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		session := &models.AgendaSession{
+			Title:       req.Title,
+			Description: req.Description,
+			Room:        req.Room,
+			StartsAt:    req.StartsAt,
+			EndsAt:      req.EndsAt,
+		}
+		if req.TrackID != "" {
+			session.TrackID = &req.TrackID
+		}
+
+		created, err := svc.CreateSession(actorFromContext(c), c.Param("id"), session, req.SpeakerIDs)
+		if err != nil {
+			return sessionPayloadError(err, "create session")
+		}
+
+		return c.JSON(http.StatusCreated, created.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdateSession(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req UpdateSessionRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		session, err := svc.UpdateSession(actorFromContext(c), c.Param("id"), c.Param("sessionId"), services.SessionChanges{
+			Apply: func(session *models.AgendaSession) {
+				if req.Title != nil {
+					session.Title = *req.Title
+				}
+				if req.Description != nil {
+					session.Description = *req.Description
+				}
+				if req.Room != nil {
+					session.Room = *req.Room
+				}
+				if req.StartsAt != nil {
+					session.StartsAt = *req.StartsAt
+				}
+				if req.EndsAt != nil {
+					session.EndsAt = *req.EndsAt
+				}
+				if req.TrackID != nil {
+					session.TrackID = nil
+					if *req.TrackID != "" {
+						session.TrackID = req.TrackID
+					}
+				}
+			},
+			SpeakerIDs: req.SpeakerIDs,
+		})
+		if err != nil {
+			return sessionPayloadError(err, "update session")
+		}
+
+		return c.JSON(http.StatusOK, session.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteSession(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteSession(actorFromContext(c), c.Param("id"), c.Param("sessionId")); err != nil {
+			return agendaError(err, "delete session")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleGetPersonalAgenda(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sessions, err := svc.GetPersonalAgenda(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return agendaError(err, "fetch personal agenda")
+		}
+
+		return c.JSON(http.StatusOK, personalAgendaResponse(c.Param("id"), sessions))
+	}
+}
+
+func (s *Server) handleAddToPersonalAgenda(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req PersonalAgendaRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		sessions, err := svc.AddToPersonalAgenda(actorFromContext(c), c.Param("id"), req.SessionID)
+		if err != nil {
+			return agendaError(err, "update personal agenda")
+		}
+
+		return c.JSON(http.StatusOK, personalAgendaResponse(c.Param("id"), sessions))
+	}
+}
+
+func (s *Server) handleRemoveFromPersonalAgenda(svc services.AgendaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sessions, err := svc.RemoveFromPersonalAgenda(actorFromContext(c), c.Param("id"), c.Param("sessionId"))
+		if err != nil {
+			return agendaError(err, "update personal agenda")
+		}
+
+		return c.JSON(http.StatusOK, personalAgendaResponse(c.Param("id"), sessions))
+	}
+}
+
+func sessionResponses(sessions []*models.AgendaSession) []*models.AgendaSessionResponse {
+	responses := make([]*models.AgendaSessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToResponse()
+	}
+	return responses
+}
+
+// personalAgendaResponse flags every pair of picked sessions whose times overlap
+func personalAgendaResponse(participantID string, sessions []*models.AgendaSession) *PersonalAgendaResponse {
+	entries := make([]*PersonalAgendaEntry, len(sessions))
+	for i, session := range sessions {
+		entry := &PersonalAgendaEntry{AgendaSessionResponse: session.ToResponse(), ConflictsWith: []string{}}
+		for j, other := range sessions {
+			if i != j && session.Overlaps(other.StartsAt, other.EndsAt) {
+				entry.ConflictsWith = append(entry.ConflictsWith, other.ID)
+			}
+		}
+		entries[i] = entry
+	}
+	return &PersonalAgendaResponse{ParticipantID: participantID, Sessions: entries}
+}
+
+// sessionPayloadError reports tracks and speakers named in a session body as bad input
+func sessionPayloadError(err error, action string) error {
+	if errors.Is(err, services.ErrTrackNotFound) || errors.Is(err, services.ErrSpeakerNotFound) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return agendaError(err, action)
+}
+
+func agendaError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "not found")
+	case errors.Is(err, services.ErrSessionNotFound), errors.Is(err, services.ErrTrackNotFound),
+		errors.Is(err, services.ErrSpeakerNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrRoomOverlap):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidSchedule), errors.Is(err, services.ErrImageNotFound):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
+	"eventmaster-go/internal/repositories"
 	"eventmaster-go/internal/services"
 	"net/http"
 	"time"
@@ -116,3 +118,29 @@ func (s *Server) handleLogout(authService services.AuthService) echo.HandlerFunc
 		})
 	}
 }
+
+// handleListUsers lists accounts for administrators, accepting filter[...] and sort
+// parameters over repositories.UserFields
+func (s *Server) handleListUsers(authService services.AuthService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, err := query.Parse(c.QueryParams(), repositories.UserFields)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		users, totalCount, err := authService.ListUsers(q, parseQueryInt(c, "page", 1), parseQueryInt(c, "limit", defaultEventListLimit))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch users")
+		}
+
+		responses := make([]*models.UserResponse, len(users))
+		for i, user := range users {
+			responses[i] = user.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"users":      responses,
+			"totalCount": totalCount,
+		})
+	}
+}
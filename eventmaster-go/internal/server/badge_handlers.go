@@ -0,0 +1,65 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/services"
+	"eventmaster-go/pkg/badge"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterBadgeHandlers registers printable ticket and badge sheet HTTP handlers. Ticket PDFs
+// are looked up by code like the tickets themselves; badge sheets are for organizers.
+func (s *Server) RegisterBadgeHandlers(badgeService services.BadgeService) {
+	s.apiGroup.GET("/badge-templates", s.handleListBadgeTemplates(badgeService))
+	s.apiGroup.GET("/tickets/:code/ticket.pdf", s.handleTicketPDF(badgeService))
+	s.apiGroup.GET("/events/:id/badges.pdf", s.handleBadgeSheet(badgeService), s.requireAuth)
+}
+
+func (s *Server) handleListBadgeTemplates(svc services.BadgeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, svc.Templates())
+	}
+}
+
+func (s *Server) handleTicketPDF(svc services.BadgeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		pdf, err := svc.TicketPDF(c.Param("code"))
+		if err != nil {
+			return ticketError(err, "render ticket")
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="ticket.pdf"`)
+		c.Response().Header().Set("Cache-Control", "private, max-age=300")
+		return c.Blob(http.StatusOK, badge.ContentType, pdf)
+	}
+}
+
+// handleBadgeSheet renders badges for the participants of an event. The template query
+// parameter picks the layout and ticketTypeId limits the sheet to one ticket type.
+func (s *Server) handleBadgeSheet(svc services.BadgeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eventID := c.Param("id")
+		pdf, err := svc.BadgeSheet(actorFromContext(c), eventID, c.QueryParam("template"), c.QueryParam("ticketTypeId"))
+		if err != nil {
+			return badgeError(err, "render badges")
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="badges-%s.pdf"`, eventID))
+		c.Response().Header().Set("Cache-Control", "no-store")
+		return c.Blob(http.StatusOK, badge.ContentType, pdf)
+	}
+}
+
+func badgeError(err error, action string) error {
+	switch {
+	case errors.Is(err, badge.ErrUnknownTemplate), errors.Is(err, services.ErrTicketTypeNotFound):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, badge.ErrNoCards):
+		return echo.NewHTTPError(http.StatusConflict, "no participants to print badges for")
+	default:
+		return ticketError(err, action)
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"eventmaster-go/pkg/ical"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	calendarProdID          = "-//EventMaster//Events Calendar//EN"
+	calendarUIDDomain       = "eventmaster"
+	calendarFeedName        = "EventMaster Events"
+	calendarRefreshInterval = 6 * time.Hour
+	calendarDefaultLimit    = 100
+	calendarMaxLimit        = 500
+)
+
+func (s *Server) handleGetEventICS(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+		if id == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
+		}
+
+		event, err := svc.GetEventByID(id)
+		if err != nil || !s.canViewEvent(c, event) {
+			return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+
+		calendar := &ical.Calendar{
+			ProdID: calendarProdID,
+			Events: []ical.Event{s.toCalendarEvent(event)},
+		}
+
+		filename := fmt.Sprintf("event-%s.ics", event.ID)
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+		return c.Blob(http.StatusOK, ical.ContentType, []byte(calendar.Encode()))
+	}
+}
+
+// handleGetEventsFeed serves a subscribable calendar that accepts the same filters as the events list
+func (s *Server) handleGetEventsFeed(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		params, err := parseEventListParams(c, calendarDefaultLimit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if params.Limit > calendarMaxLimit {
+			params.Limit = calendarMaxLimit
+		}
+
+		events, _, err := svc.GetPaginatedEvents(params.Filter, params.Query, params.Page, params.Limit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch events")
+		}
+
+		calendar := &ical.Calendar{
+			ProdID:          calendarProdID,
+			Name:            calendarFeedName,
+			RefreshInterval: calendarRefreshInterval,
+			Events:          make([]ical.Event, 0, len(events)),
+		}
+		for _, event := range events {
+			calendar.Events = append(calendar.Events, s.toCalendarEvent(event))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="events.ics"`)
+		return c.Blob(http.StatusOK, ical.ContentType, []byte(calendar.Encode()))
+	}
+}
+
+func (s *Server) toCalendarEvent(event *models.Event) ical.Event {
+	entry := ical.Event{
+		UID:          fmt.Sprintf("%s@%s", event.ID, calendarUIDDomain),
+		Summary:      event.Title,
+		Description:  event.Description,
+		Created:      event.CreatedAt,
+		LastModified: event.UpdatedAt,
+		Location:     event.Location,
+		Latitude:     event.Latitude,
+		Longitude:    event.Longitude,
+		HasGeo:       event.Latitude != 0 || event.Longitude != 0,
+		Status:       calendarStatus(event.Status),
+	}
+
+	// SEQUENCE starts at 0 and increases with each revision of the event
+	if event.Version > 1 {
+		entry.Sequence = event.Version - 1
+	}
+
+	if event.EventDate != nil {
+		entry.Start = *event.EventDate
+	}
+
+	if event.IsExternal && event.ExternalURL != "" {
+		entry.URL = event.ExternalURL
+	} else if s.config.PublicURL != "" {
+		entry.URL = fmt.Sprintf("%s/events/%s", strings.TrimRight(s.config.PublicURL, "/"), event.ID)
+	}
+
+	for _, category := range event.Categories {
+		entry.Categories = append(entry.Categories, category.Name)
+	}
+	for _, tag := range event.Tags {
+		entry.Categories = append(entry.Categories, tag.Name)
+	}
+	if len(entry.Categories) == 0 && event.EventType != "" {
+		entry.Categories = []string{event.EventType}
+	}
+
+	return entry
+}
+
+// calendarStatus maps an event lifecycle state to a VEVENT STATUS value
+func calendarStatus(status models.EventStatus) string {
+	switch status {
+	case models.EventStatusCancelled:
+		return "CANCELLED"
+	case models.EventStatusPostponed, models.EventStatusDraft:
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
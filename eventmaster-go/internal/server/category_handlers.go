@@ -0,0 +1,69 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateCategoryRequest represents the request body for creating a category
+type CreateCategoryRequest struct {
+	Name     string  `json:"name" validate:"required,min=1,max=100"`
+	ParentID *string `json:"parentId" validate:"omitempty,uuid4"`
+}
+
+// RegisterCategoryHandlers registers taxonomy-related HTTP handlers
+func (s *Server) RegisterCategoryHandlers(categoryService services.CategoryService) {
+	s.apiGroup.GET("/categories", s.handleGetCategories(categoryService))
+	s.apiGroup.GET("/tags", s.handleGetTags(categoryService))
+	s.apiGroup.POST("/categories", s.handleCreateCategory(categoryService), s.requireAuth, s.requireAdmin)
+}
+
+func (s *Server) handleGetCategories(svc services.CategoryService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tree, err := svc.GetCategoryTree()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch categories")
+		}
+
+		return c.JSON(http.StatusOK, tree)
+	}
+}
+
+func (s *Server) handleGetTags(svc services.CategoryService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tags, err := svc.GetTags()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch tags")
+		}
+
+		return c.JSON(http.StatusOK, tags)
+	}
+}
+
+func (s *Server) handleCreateCategory(svc services.CategoryService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CreateCategoryRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		category, err := svc.CreateCategory(req.Name, req.ParentID)
+		switch {
+		case errors.Is(err, services.ErrCategoryExists):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrCategoryNotFound), errors.Is(err, services.ErrInvalidName):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case err != nil:
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create category")
+		}
+
+		return c.JSON(http.StatusCreated, category.ToResponse())
+	}
+}
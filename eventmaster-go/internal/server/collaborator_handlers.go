@@ -0,0 +1,182 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// InviteCollaboratorRequest represents the request body for inviting a collaborator
+type InviteCollaboratorRequest struct {
+	Email string                  `json:"email" validate:"required,email,max=255"`
+	Role  models.CollaboratorRole `json:"role" validate:"required,oneof=owner editor check_in viewer"`
+}
+
+// UpdateCollaboratorRequest represents the request body for changing a collaborator's role
+type UpdateCollaboratorRequest struct {
+	Role models.CollaboratorRole `json:"role" validate:"required,oneof=owner editor check_in viewer"`
+}
+
+// AcceptInviteRequest carries the token from an invitation email
+type AcceptInviteRequest struct {
+	Token string `json:"token" validate:"required,max=100"`
+}
+
+// EventRoleResponse is the caller's role on an event; empty when they have none
+type EventRoleResponse struct {
+	EventID string                  `json:"eventId"`
+	Role    models.CollaboratorRole `json:"role"`
+}
+
+// RegisterCollaboratorHandlers registers event collaborator and invitation HTTP handlers
+func (s *Server) RegisterCollaboratorHandlers(collaboratorService services.CollaboratorService) {
+	collaboratorGroup := s.apiGroup.Group("/events/:id/collaborators")
+	collaboratorGroup.Use(s.requireAuth)
+	{
+		collaboratorGroup.GET("", s.handleListCollaborators(collaboratorService))
+		collaboratorGroup.POST("", s.handleInviteCollaborator(collaboratorService))
+		collaboratorGroup.GET("/me", s.handleMyEventRole(collaboratorService))
+		collaboratorGroup.PUT("/:collaboratorId", s.handleUpdateCollaborator(collaboratorService))
+		collaboratorGroup.DELETE("/:collaboratorId", s.handleRemoveCollaborator(collaboratorService))
+	}
+
+	s.apiGroup.POST("/collaborator-invites/accept", s.handleAcceptInvite(collaboratorService), s.requireAuth)
+	s.apiGroup.GET("/collaborations", s.handleSharedWithMe(collaboratorService), s.requireAuth)
+}
+
+func (s *Server) handleListCollaborators(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		collaborators, err := svc.ListCollaborators(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return collaboratorError(err, "list collaborators")
+		}
+
+		return c.JSON(http.StatusOK, collaboratorResponses(collaborators))
+	}
+}
+
+// handleInviteCollaborator emails an invitation; the token is only ever sent to the invitee
+func (s *Server) handleInviteCollaborator(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req InviteCollaboratorRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		collaborator, err := svc.Invite(actorFromContext(c), c.Param("id"), req.Email, req.Role)
+		if err != nil {
+			return collaboratorError(err, "invite collaborator")
+		}
+
+		return c.JSON(http.StatusCreated, collaborator.ToResponse())
+	}
+}
+
+func (s *Server) handleMyEventRole(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		eventID := c.Param("id")
+		role, err := svc.MyRole(actorFromContext(c), eventID)
+		if err != nil {
+			return collaboratorError(err, "fetch role")
+		}
+
+		return c.JSON(http.StatusOK, &EventRoleResponse{EventID: eventID, Role: role})
+	}
+}
+
+func (s *Server) handleUpdateCollaborator(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req UpdateCollaboratorRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		collaborator, err := svc.UpdateRole(actorFromContext(c), c.Param("id"), c.Param("collaboratorId"), req.Role)
+		if err != nil {
+			return collaboratorError(err, "update collaborator")
+		}
+
+		return c.JSON(http.StatusOK, collaborator.ToResponse())
+	}
+}
+
+func (s *Server) handleRemoveCollaborator(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.RemoveCollaborator(actorFromContext(c), c.Param("id"), c.Param("collaboratorId")); err != nil {
+			return collaboratorError(err, "remove collaborator")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleAcceptInvite(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req AcceptInviteRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		collaborator, err := svc.AcceptInvite(actorFromContext(c), req.Token)
+		if err != nil {
+			return collaboratorError(err, "accept invitation")
+		}
+
+		return c.JSON(http.StatusOK, collaborator.ToResponse())
+	}
+}
+
+// handleSharedWithMe lists the events the caller collaborates on with their role
+func (s *Server) handleSharedWithMe(svc services.CollaboratorService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		collaborators, err := svc.SharedWithMe(actorFromContext(c))
+		if err != nil {
+			return collaboratorError(err, "list collaborations")
+		}
+
+		return c.JSON(http.StatusOK, collaboratorResponses(collaborators))
+	}
+}
+
+func collaboratorResponses(collaborators []*models.Collaborator) []*models.CollaboratorResponse {
+	resp := make([]*models.CollaboratorResponse, len(collaborators))
+	for i, collaborator := range collaborators {
+		resp[i] = collaborator.ToResponse()
+	}
+	return resp
+}
+
+func collaboratorError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrCollaboratorNotFound), errors.Is(err, services.ErrInviteNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrInviteEmailMismatch):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrAlreadyCollaborator), errors.Is(err, services.ErrInviteNotPending):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInviteExpired):
+		return echo.NewHTTPError(http.StatusGone, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"eventmaster-go/internal/models"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// eventETag is a strong validator derived from the event version. Ticket sales, ticket type
+// edits and edits of the linked venue or organizer profile change the representation
+// without bumping the version, so events with any of those append a hash of them as
+// "version.hash".
+func eventETag(event *models.Event) string {
+	if len(event.TicketTypes) == 0 && event.Venue == nil && event.OrganizerProfile == nil {
+		return `"` + strconv.Itoa(event.Version) + `"`
+	}
+	h := fnv.New64a()
+	for _, ticketType := range event.TicketTypes {
+		fmt.Fprintf(h, "%s:%d:%d:%d;", ticketType.ID, ticketType.UpdatedAt.UnixNano(), ticketType.Sold, ticketType.Reserved)
+	}
+	if event.Venue != nil {
+		fmt.Fprintf(h, "venue:%s:%d;", event.Venue.ID, event.Venue.UpdatedAt.UnixNano())
+	}
+	if profile := event.OrganizerProfile; profile != nil {
+		fmt.Fprintf(h, "organizer:%s:%d;", profile.ID, profile.UpdatedAt.UnixNano())
+		if profile.Logo != nil {
+			fmt.Fprintf(h, "logo:%s;", profile.Logo.ID)
+		}
+	}
+	return fmt.Sprintf(`"%d.%x"`, event.Version, h.Sum64())
+}
+
+// etagVersion returns the event version a strong tag made by eventETag was derived from
+func etagVersion(etag string) (int, bool) {
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+	version, _, _ := strings.Cut(etag[1:len(etag)-1], ".")
+	n, err := strconv.Atoi(version)
+	return n, err == nil
+}
+
+// notModified sets the ETag header and reports whether the client's If-None-Match
+// already covers the current representation
+func notModified(c echo.Context, etag string) bool {
+	c.Response().Header().Set(headerETag, etag)
+
+	header := c.Request().Header.Get(headerIfNoneMatch)
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		// If-None-Match uses the weak comparison
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedEventVersion checks the If-Match precondition of a write against the current
+// event and returns the version the write must be conditioned on. It returns 428 when the
+// header is required but missing and 412 when no listed tag matches.
+func (s *Server) expectedEventVersion(c echo.Context, event *models.Event) (int, error) {
+	header := c.Request().Header.Get(headerIfMatch)
+	if header == "" {
+		if s.config.RequireIfMatch {
+			return 0, echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required")
+		}
+		return event.Version, nil
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return event.Version, nil
+		}
+		// If-Match uses the strong comparison, so weak tags never match. Only the version
+		// counts: ticket sales since the tag was issued do not conflict with an edit.
+		if version, ok := etagVersion(candidate); ok && version == event.Version {
+			return event.Version, nil
+		}
+	}
+	return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "event has been modified")
+}
@@ -1,25 +1,51 @@
 package server
 
 import (
+	"errors"
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
+	"eventmaster-go/internal/repositories"
 	"eventmaster-go/internal/services"
+	"eventmaster-go/pkg/slug"
+	"fmt"
 	"math"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-// CreateEventRequest represents the request body for creating an event
+// CreateEventRequest represents the request body for creating an event.
+// Fields omitted alongside templateId are taken from the template.
 type CreateEventRequest struct {
-	Title       string     `json:"title" validate:"required,min=2,max=255"`
+	TemplateID  string     `json:"templateId" validate:"omitempty,uuid4"`
+	Title       string     `json:"title" validate:"required_without=TemplateID,omitempty,min=2,max=255"`
 	Description string     `json:"description" validate:"omitempty,max=5000"`
 	Organizer   string     `json:"organizer" validate:"omitempty,max=255"`
 	EventDate   *time.Time `json:"eventDate" validate:"required"`
-	Latitude    float64    `json:"latitude" validate:"required,gte=-90,lte=90"`
-	Longitude   float64    `json:"longitude" validate:"required,gte=-180,lte=180"`
+	TimeZone    string     `json:"timeZone" validate:"omitempty,timezone"`
+	Latitude    float64    `json:"latitude" validate:"required_without_all=TemplateID VenueID,gte=-90,lte=90"`
+	Longitude   float64    `json:"longitude" validate:"required_without_all=TemplateID VenueID,gte=-180,lte=180"`
+	Location    string     `json:"location" validate:"omitempty,max=1000"`
+	VenueID     string     `json:"venueId" validate:"omitempty,uuid4"`
+	// OrganizerID links a profile the user manages; without it the organizer name picks one
+	OrganizerID string     `json:"organizerId" validate:"omitempty,uuid4"`
 	ImageIDs    []string   `json:"images" validate:"omitempty,dive,uuid4"`
+	Status      models.EventStatus `json:"status" validate:"omitempty,oneof=draft published"`
+	CategoryIDs []string   `json:"categoryIds" validate:"omitempty,unique,dive,uuid4"`
+	Tags        []string   `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// CloneEventRequest represents the request body for cloning an event.
+// eventDate sets the new start outright; shiftDays moves the original start instead.
+type CloneEventRequest struct {
+	Title     string     `json:"title" validate:"omitempty,min=2,max=255"`
+	EventDate *time.Time `json:"eventDate" validate:"omitempty"`
+	ShiftDays int        `json:"shiftDays" validate:"excluded_with=EventDate,gte=-3650,lte=3650"`
 }
 
 // UpdateEventRequest represents the request body for updating an event
@@ -28,9 +54,17 @@ type UpdateEventRequest struct {
 	Description *string     `json:"description" validate:"omitempty,max=5000"`
 	Organizer   *string     `json:"organizer" validate:"omitempty,max=255"`
 	EventDate   *time.Time  `json:"eventDate" validate:"omitempty"`
+	TimeZone    *string     `json:"timeZone" validate:"omitempty,timezone"`
 	Latitude    *float64    `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
 	Longitude   *float64    `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	Location    *string     `json:"location" validate:"omitempty,max=1000"`
+	// VenueID links the event to a venue; an empty string unlinks it
+	VenueID     *string     `json:"venueId" validate:"omitempty,uuid4|len=0"`
+	// OrganizerID links the event to an organizer profile; an empty string unlinks it
+	OrganizerID *string     `json:"organizerId" validate:"omitempty,uuid4|len=0"`
 	ImageIDs    []string    `json:"imageIds" validate:"omitempty,dive,uuid4"`
+	CategoryIDs []string    `json:"categoryIds" validate:"omitempty,unique,dive,uuid4"`
+	Tags        []string    `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50"`
 }
 
 // EventResponse represents the event response
@@ -51,9 +85,12 @@ type EventResponse struct {
 func (s *Server) RegisterEventHandlers(eventService services.EventService) {
 	eventGroup := s.apiGroup.Group("/events")
 	
-	// Public routes
-	eventGroup.GET("", s.handleGetEvents(eventService))
-	eventGroup.GET("/:id", s.handleGetEvent(eventService))
+	// Public routes; drafts are only visible to their owner
+	eventGroup.GET("", s.handleGetEvents(eventService), s.optionalAuth)
+	// GET /events/:id also accepts the event's slug in place of its ID
+	eventGroup.GET("/:id", s.handleGetEvent(eventService), s.optionalAuth)
+	eventGroup.GET("/:id/ics", s.handleGetEventICS(eventService), s.optionalAuth)
+	s.apiGroup.GET("/events.ics", s.handleGetEventsFeed(eventService), s.optionalAuth)
 
 	// Protected routes (require authentication)
 	protected := eventGroup.Group("")
@@ -62,6 +99,13 @@ func (s *Server) RegisterEventHandlers(eventService services.EventService) {
 		protected.POST("", s.handleCreateEvent(eventService))
 		protected.PUT("/:id", s.handleUpdateEvent(eventService))
 		protected.DELETE("/:id", s.handleDeleteEvent(eventService))
+		protected.POST("/:id/clone", s.handleCloneEvent(eventService))
+		protected.POST("/:id/publish", s.handlePublishEvent(eventService))
+		protected.POST("/:id/cancel", s.handleCancelEvent(eventService))
+		protected.POST("/:id/postpone", s.handlePostponeEvent(eventService))
+		protected.GET("/:id/revisions", s.handleGetEventRevisions(eventService))
+		protected.GET("/:id/revisions/:number", s.handleGetEventRevision(eventService))
+		protected.POST("/:id/revisions/:number/revert", s.handleRevertEventRevision(eventService))
 	}
 }
 
@@ -86,19 +130,79 @@ func (s *Server) handleCreateEvent(svc services.EventService) echo.HandlerFunc {
 			Description: req.Description,
 			Organizer:   req.Organizer,
 			EventDate:   req.EventDate,
+			TimeZone:    req.TimeZone,
 			Latitude:    req.Latitude,
 			Longitude:   req.Longitude,
+			Location:    req.Location,
+			Status:      req.Status,
+		}
+		if req.TemplateID != "" {
+			event.TemplateID = &req.TemplateID
+		}
+		if req.VenueID != "" {
+			event.VenueID = &req.VenueID
+		}
+		if req.OrganizerID != "" {
+			event.OrganizerID = &req.OrganizerID
+		}
+		for _, categoryID := range req.CategoryIDs {
+			event.Categories = append(event.Categories, models.Category{Base: models.Base{ID: categoryID}})
+		}
+		for _, tag := range req.Tags {
+			event.Tags = append(event.Tags, models.Tag{Name: tag})
 		}
 
 		createdEvent, err := svc.CreateEvent(event, userID, req.ImageIDs)
+		if errors.Is(err, services.ErrCategoryNotFound) || errors.Is(err, services.ErrImageNotFound) ||
+			errors.Is(err, services.ErrTemplateNotFound) || errors.Is(err, services.ErrTitleRequired) ||
+			errors.Is(err, services.ErrVenueNotFound) || errors.Is(err, services.ErrOrganizerNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create event: "+err.Error())
 		}
 
+		c.Response().Header().Set(headerETag, eventETag(createdEvent))
 		return c.JSON(http.StatusCreated, createdEvent.ToResponse())
 	}
 }
 
+func (s *Server) handleCloneEvent(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+		if userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+		}
+
+		id := c.Param("id")
+		source, err := svc.GetEventByID(id)
+		if err != nil || !s.canViewEvent(c, source) {
+			return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+
+		var req CloneEventRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		clone, err := svc.CloneEvent(id, userID, services.CloneOptions{
+			Title:     req.Title,
+			EventDate: req.EventDate,
+			ShiftDays: req.ShiftDays,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to clone event")
+		}
+
+		c.Response().Header().Set(headerETag, eventETag(clone))
+		return c.JSON(http.StatusCreated, clone.ToResponse())
+	}
+}
+
 func (s *Server) handleGetEvent(svc services.EventService) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		id := c.Param("id")
@@ -106,10 +210,30 @@ func (s *Server) handleGetEvent(svc services.EventService) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
 		}
 
-		event, err := svc.GetEventByID(id)
-		if err != nil {
+		// The event may be addressed by its ID or its slug
+		var event *models.Event
+		var err error
+		if _, parseErr := uuid.Parse(id); parseErr == nil {
+			event, err = svc.GetEventByID(id)
+		} else {
+			event, err = svc.GetEventBySlug(id)
+		}
+		if err != nil || !s.canViewEvent(c, event) {
 			return echo.NewHTTPError(http.StatusNotFound, "event not found")
 		}
+		// Slugs the event had before its title changed redirect to the current one. The
+		// redirect is temporary so browsers do not cache it past the slug being reclaimed.
+		if event.Slug != nil && *event.Slug != id && event.ID != id {
+			location := "/api/events/" + *event.Slug
+			if query := c.Request().URL.RawQuery; query != "" {
+				location += "?" + query
+			}
+			return c.Redirect(http.StatusFound, location)
+		}
+
+		if notModified(c, eventETag(event)) {
+			return c.NoContent(http.StatusNotModified)
+		}
 
 		return c.JSON(http.StatusOK, event.ToResponse())
 	}
@@ -117,18 +241,12 @@ func (s *Server) handleGetEvent(svc services.EventService) echo.HandlerFunc {
 
 func (s *Server) handleGetEvents(svc services.EventService) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		page := parseQueryInt(c, "page", 1)
-		limit := parseQueryInt(c, "limit", 10)
-		sortBy := c.QueryParam("sortBy")
-		if sortBy == "" {
-			sortBy = "event_date"
-		}
-		sortOrder := c.QueryParam("sortOrder")
-		if sortOrder == "" {
-			sortOrder = "ASC"
+		params, err := parseEventListParams(c, defaultEventListLimit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 
-		events, totalCount, err := svc.GetPaginatedEvents(page, limit, sortBy, sortOrder)
+		events, totalCount, err := svc.GetPaginatedEvents(params.Filter, params.Query, params.Page, params.Limit)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch events")
 		}
@@ -145,6 +263,142 @@ func (s *Server) handleGetEvents(svc services.EventService) echo.HandlerFunc {
 	}
 }
 
+const defaultEventListLimit = 10
+
+// categorySlugPattern matches category path slugs such as music/rock
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9-]+(/[a-z0-9-]+)*$`)
+
+// eventListParams holds the query parameters shared by every endpoint that lists events
+type eventListParams struct {
+	Page   int
+	Limit  int
+	Filter repositories.EventFilter
+	// Query holds the filter[...] and sort parameters
+	Query query.Query
+}
+
+// parseEventListParams reads pagination, sorting and filters from the query string.
+// The from/to filters accept RFC 3339 instants or plain dates; plain dates are
+// interpreted as whole days in the zone given by tz (UTC by default), with to inclusive.
+// Fields referenced by filter[...] and sort must be in repositories.EventFields.
+func parseEventListParams(c echo.Context, defaultLimit int) (eventListParams, error) {
+	params := eventListParams{
+		Page:  parseQueryInt(c, "page", 1),
+		Limit: parseQueryInt(c, "limit", defaultLimit),
+	}
+
+	q, err := query.Parse(c.QueryParams(), repositories.EventFields)
+	if err != nil {
+		return params, err
+	}
+	// sortBy and sortOrder predate the sort parameter and are still honoured
+	if len(q.Orders) == 0 && (c.QueryParam("sortBy") != "" || c.QueryParam("sortOrder") != "") {
+		spec := c.QueryParam("sortBy")
+		if spec == "" {
+			spec = "eventDate"
+		}
+		if strings.EqualFold(c.QueryParam("sortOrder"), "DESC") {
+			spec = "-" + spec
+		}
+		if q.Orders, err = query.ParseSort(spec, repositories.EventFields); err != nil {
+			return params, err
+		}
+	}
+	params.Query = q
+
+	if status := models.EventStatus(c.QueryParam("status")); status != "" {
+		if !status.IsValid() {
+			return params, fmt.Errorf("invalid status: %s", status)
+		}
+		params.Filter.Status = status
+	}
+	params.Filter.ViewerID, _ = c.Get("userID").(string)
+
+	if category := c.QueryParam("category"); category != "" {
+		if !categorySlugPattern.MatchString(category) {
+			return params, fmt.Errorf("invalid category: %s", category)
+		}
+		params.Filter.CategorySlug = category
+	}
+	if tag := c.QueryParam("tag"); tag != "" {
+		params.Filter.TagSlug = slug.Make(tag)
+	}
+	if venue := c.QueryParam("venue"); venue != "" {
+		if _, err := uuid.Parse(venue); err != nil {
+			return params, fmt.Errorf("invalid venue: %s", venue)
+		}
+		params.Filter.VenueID = venue
+	}
+	if organizer := c.QueryParam("organizer"); organizer != "" {
+		if _, err := uuid.Parse(organizer); err != nil {
+			return params, fmt.Errorf("invalid organizer: %s", organizer)
+		}
+		params.Filter.OrganizerID = organizer
+	}
+	params.Filter.City = strings.TrimSpace(c.QueryParam("city"))
+
+	loc, err := parseQueryLocation(c, "tz")
+	if err != nil {
+		return params, err
+	}
+
+	if value := c.QueryParam("from"); value != "" {
+		from, _, err := parseDateBoundary(value, loc)
+		if err != nil {
+			return params, fmt.Errorf("invalid from: %w", err)
+		}
+		params.Filter.From = &from
+	}
+
+	if value := c.QueryParam("to"); value != "" {
+		to, isDate, err := parseDateBoundary(value, loc)
+		if err != nil {
+			return params, fmt.Errorf("invalid to: %w", err)
+		}
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		}
+		params.Filter.To = &to
+	}
+
+	return params, nil
+}
+
+// parseQueryLocation resolves an IANA time zone query parameter, defaulting to UTC
+func parseQueryLocation(c echo.Context, name string) (*time.Location, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: unknown time zone %q", name, value)
+	}
+	return loc, nil
+}
+
+// parseDateBoundary parses an RFC 3339 instant or a YYYY-MM-DD date at midnight in loc
+func parseDateBoundary(value string, loc *time.Location) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, false, nil
+	}
+
+	parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected RFC 3339 timestamp or YYYY-MM-DD date")
+	}
+	return parsed, true, nil
+}
+
+// canViewEvent hides drafts from everyone but the event's collaborators
+func (s *Server) canViewEvent(c echo.Context, event *models.Event) bool {
+	if event.Status.IsPublic() {
+		return true
+	}
+	return s.eventAccess.Authorize(actorFromContext(c), event, services.PermissionView) == nil
+}
+
 func parseQueryInt(c echo.Context, name string, defaultValue int) int {
 	value := c.QueryParam(name)
 	if value == "" {
@@ -171,14 +425,19 @@ func (s *Server) handleUpdateEvent(svc services.EventService) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
 		}
 
-		// Verify the event exists and belongs to the user
+		// Verify the event exists and the user may update it
 		event, err := svc.GetEventByID(id)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "event not found")
 		}
 
-		if event.UserID != userID {
-			return echo.NewHTTPError(http.StatusForbidden, "not authorized to update this event")
+		if err := s.authorizeEvent(c, event, services.PermissionEdit, "update"); err != nil {
+			return err
+		}
+
+		expectedVersion, err := s.expectedEventVersion(c, event)
+		if err != nil {
+			return err
 		}
 
 		var req UpdateEventRequest
@@ -203,21 +462,62 @@ func (s *Server) handleUpdateEvent(svc services.EventService) echo.HandlerFunc {
 		if req.EventDate != nil {
 			event.EventDate = req.EventDate
 		}
+		if req.TimeZone != nil {
+			event.TimeZone = *req.TimeZone
+		}
 		if req.Latitude != nil {
 			event.Latitude = *req.Latitude
 		}
 		if req.Longitude != nil {
 			event.Longitude = *req.Longitude
 		}
+		if req.Location != nil {
+			event.Location = *req.Location
+		}
+		if req.VenueID != nil {
+			event.VenueID = nil
+			if *req.VenueID != "" {
+				event.VenueID = req.VenueID
+			}
+		}
+		if req.OrganizerID != nil {
+			event.OrganizerID = nil
+			if *req.OrganizerID != "" {
+				event.OrganizerID = req.OrganizerID
+			}
+		}
 		if req.ImageIDs != nil {
 			// TODO: handle image association updates similar to NestJS if needed
 		}
 
-		updatedEvent, err := svc.UpdateEvent(id, event)
+		// Categories and tags are saved with the fields, in the same revision
+		if req.CategoryIDs != nil {
+			event.Categories = make([]models.Category, len(req.CategoryIDs))
+			for i, categoryID := range req.CategoryIDs {
+				event.Categories[i] = models.Category{Base: models.Base{ID: categoryID}}
+			}
+		}
+		if req.Tags != nil {
+			event.Tags = make([]models.Tag, len(req.Tags))
+			for i, tag := range req.Tags {
+				event.Tags[i] = models.Tag{Name: tag}
+			}
+		}
+
+		event.Version = expectedVersion
+		updatedEvent, err := svc.UpdateEvent(id, event, userID)
+		if errors.Is(err, services.ErrVersionConflict) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "event has been modified")
+		}
+		if errors.Is(err, services.ErrVenueNotFound) || errors.Is(err, services.ErrOrganizerNotFound) ||
+			errors.Is(err, services.ErrCategoryNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "failed to update event")
 		}
 
+		c.Response().Header().Set(headerETag, eventETag(updatedEvent))
 		return c.JSON(http.StatusOK, updatedEvent.ToResponse())
 	}
 }
@@ -234,17 +534,26 @@ func (s *Server) handleDeleteEvent(svc services.EventService) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
 		}
 
-		// Verify the event exists and belongs to the user
+		// Verify the event exists and the user may delete it
 		event, err := svc.GetEventByID(id)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusNotFound, "event not found")
 		}
 
-		if event.UserID != userID {
-			return echo.NewHTTPError(http.StatusForbidden, "not authorized to delete this event")
+		if err := s.authorizeEvent(c, event, services.PermissionManage, "delete"); err != nil {
+			return err
 		}
 
-		if err := svc.DeleteEvent(id); err != nil {
+		expectedVersion, err := s.expectedEventVersion(c, event)
+		if err != nil {
+			return err
+		}
+
+		err = svc.DeleteEvent(id, expectedVersion)
+		if errors.Is(err, services.ErrVersionConflict) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, "event has been modified")
+		}
+		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete event")
 		}
 
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PublishEventRequest represents the optional body for publishing or rescheduling an event
+type PublishEventRequest struct {
+	EventDate *time.Time `json:"eventDate"`
+}
+
+// CancelEventRequest represents the request body for cancelling an event
+type CancelEventRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+// PostponeEventRequest represents the request body for postponing an event
+type PostponeEventRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+func (s *Server) handlePublishEvent(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := s.loadEvent(c, svc, services.PermissionEdit, "publish")
+		if err != nil {
+			return err
+		}
+
+		var req PublishEventRequest
+		if c.Request().ContentLength > 0 {
+			if err := c.Bind(&req); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+			}
+		}
+
+		updated, err := svc.PublishEvent(event.ID, req.EventDate)
+		if err != nil {
+			return statusTransitionError(err, "publish")
+		}
+
+		return c.JSON(http.StatusOK, updated.ToResponse())
+	}
+}
+
+func (s *Server) handleCancelEvent(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := s.loadEvent(c, svc, services.PermissionEdit, "cancel")
+		if err != nil {
+			return err
+		}
+
+		var req CancelEventRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		updated, err := svc.CancelEvent(event.ID, req.Reason)
+		if err != nil {
+			return statusTransitionError(err, "cancel")
+		}
+
+		return c.JSON(http.StatusOK, updated.ToResponse())
+	}
+}
+
+func (s *Server) handlePostponeEvent(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := s.loadEvent(c, svc, services.PermissionEdit, "postpone")
+		if err != nil {
+			return err
+		}
+
+		var req PostponeEventRequest
+		if c.Request().ContentLength > 0 {
+			if err := c.Bind(&req); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+			}
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		updated, err := svc.PostponeEvent(event.ID, req.Reason)
+		if err != nil {
+			return statusTransitionError(err, "postpone")
+		}
+
+		return c.JSON(http.StatusOK, updated.ToResponse())
+	}
+}
+
+// loadEvent fetches the event named in the path and checks that the caller holds permission on it
+func (s *Server) loadEvent(c echo.Context, svc services.EventService, permission services.Permission, action string) (*models.Event, error) {
+	userID, _ := c.Get("userID").(string)
+	if userID == "" {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
+	}
+
+	event, err := svc.GetEventByID(id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "event not found")
+	}
+
+	if err := s.authorizeEvent(c, event, permission, action); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// authorizeEvent checks the caller's collaborator role on an event, answering 403 when it
+// does not grant permission
+func (s *Server) authorizeEvent(c echo.Context, event *models.Event, permission services.Permission, action string) error {
+	err := s.eventAccess.Authorize(actorFromContext(c), event, permission)
+	if errors.Is(err, services.ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action+" this event")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to check permissions")
+	}
+	return nil
+}
+
+func statusTransitionError(err error, action string) error {
+	if errors.Is(err, services.ErrInvalidStatusTransition) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action+" event")
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"eventmaster-go/pkg/tabular"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// RegisterExportHandlers registers handlers for streamed downloads and background export jobs
+func (s *Server) RegisterExportHandlers(exportService services.ExportService) {
+	s.apiGroup.GET("/events/export", s.handleExportEvents(exportService), s.optionalAuth)
+	s.apiGroup.GET("/participant/event/:eventId/export", s.handleExportParticipants(exportService), s.requireAuth)
+
+	exportGroup := s.apiGroup.Group("/exports")
+	exportGroup.Use(s.requireAuth)
+
+	exportGroup.POST("/events", s.handleStartEventsExport(exportService))
+	exportGroup.POST("/participants/:eventId", s.handleStartParticipantsExport(exportService))
+	exportGroup.GET("/:id", s.handleGetExportJob(exportService))
+	exportGroup.GET("/:id/download", s.handleDownloadExport(exportService))
+}
+
+// handleExportEvents streams every event matching the list filters; pagination parameters are ignored
+func (s *Server) handleExportEvents(svc services.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		format, err := tabular.ParseFormat(c.QueryParam("format"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		params, err := parseEventListParams(c, defaultEventListLimit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		startDownload(c, format, "events")
+		if _, err := svc.ExportEvents(c.Response(), format, params.Filter, params.Query); err != nil {
+			// The status line is already sent, so the client sees a truncated file
+			log.Printf("Events export failed: %v", err)
+		}
+		return nil
+	}
+}
+
+func (s *Server) handleExportParticipants(svc services.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		format, err := tabular.ParseFormat(c.QueryParam("format"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		event, err := svc.FindExportableEvent(actorFromContext(c), c.Param("eventId"))
+		if err != nil {
+			return exportError(err, "export participants")
+		}
+
+		startDownload(c, format, "participants-"+event.ID)
+		if _, err := svc.ExportParticipants(c.Response(), format, event); err != nil {
+			log.Printf("Participants export failed: event=%s err=%v", event.ID, err)
+		}
+		return nil
+	}
+}
+
+func (s *Server) handleStartEventsExport(svc services.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		format, err := tabular.ParseFormat(c.QueryParam("format"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		params, err := parseEventListParams(c, defaultEventListLimit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		job, err := svc.StartEventsExport(actorFromContext(c), format, params.Filter, params.Query)
+		if err != nil {
+			return exportError(err, "start export")
+		}
+
+		return c.JSON(http.StatusAccepted, exportJobResponse(job))
+	}
+}
+
+func (s *Server) handleStartParticipantsExport(svc services.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		format, err := tabular.ParseFormat(c.QueryParam("format"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		job, err := svc.StartParticipantsExport(actorFromContext(c), format, c.Param("eventId"))
+		if err != nil {
+			return exportError(err, "start export")
+		}
+
+		return c.JSON(http.StatusAccepted, exportJobResponse(job))
+	}
+}
+
+func (s *Server) handleGetExportJob(svc services.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		job, err := svc.GetExportJob(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return exportError(err, "fetch export")
+		}
+
+		return c.JSON(http.StatusOK, exportJobResponse(job))
+	}
+}
+
+func (s *Server) handleDownloadExport(svc services.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		job, file, err := svc.OpenExportFile(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return exportError(err, "download export")
+		}
+		defer file.Close()
+
+		format := tabular.Format(job.Format)
+		name := string(job.Kind)
+		if job.EventID != nil {
+			name += "-" + *job.EventID
+		}
+
+		var modTime time.Time
+		if job.CompletedAt != nil {
+			modTime = *job.CompletedAt
+		}
+		c.Response().Header().Set(echo.HeaderContentType, format.ContentType())
+		c.Response().Header().Set(echo.HeaderContentDisposition, exportDisposition(name, format))
+		http.ServeContent(c.Response(), c.Request(), "", modTime, file)
+		return nil
+	}
+}
+
+// startDownload sends the headers of a streamed export. Nothing can be reported
+// through the status code after this point.
+func startDownload(c echo.Context, format tabular.Format, name string) {
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, format.ContentType())
+	header.Set(echo.HeaderContentDisposition, exportDisposition(name, format))
+	header.Set("X-Content-Type-Options", "nosniff")
+	c.Response().WriteHeader(http.StatusOK)
+}
+
+func exportDisposition(name string, format tabular.Format) string {
+	return fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().UTC().Format("20060102"), format.Extension())
+}
+
+func exportJobResponse(job *models.ExportJob) *models.ExportJobResponse {
+	resp := job.ToResponse()
+	if job.Status == models.ExportStatusCompleted {
+		resp.DownloadURL = "/api/exports/" + job.ID + "/download"
+	}
+	return resp
+}
+
+func exportError(err error, action string) error {
+	switch {
+	case errors.Is(err, services.ErrExportNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "not found")
+	case errors.Is(err, services.ErrExportNotReady):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
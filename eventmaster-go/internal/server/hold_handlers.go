@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// HoldRequest represents the request body for holding a ticket while registering
+type HoldRequest struct {
+	TicketTypeID string `json:"ticketTypeId" validate:"required,uuid4"`
+	Email        string `json:"email" validate:"required,email"`
+}
+
+// RegisterHoldHandlers registers ticket hold HTTP handlers. Holds are placed before
+// registering, so like registration they need no account.
+func (s *Server) RegisterHoldHandlers(holdService services.HoldService) {
+	s.apiGroup.POST("/events/:id/holds", s.handlePlaceHold(holdService))
+
+	holdGroup := s.apiGroup.Group("/holds")
+	holdGroup.GET("/:id", s.handleGetHold(holdService))
+	holdGroup.DELETE("/:id", s.handleReleaseHold(holdService))
+}
+
+func (s *Server) handlePlaceHold(svc services.HoldService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req HoldRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		hold, err := svc.PlaceHold(c.Param("id"), req.TicketTypeID, req.Email)
+		if err != nil {
+			return holdError(err, "hold ticket")
+		}
+
+		return c.JSON(http.StatusCreated, hold.ToResponse())
+	}
+}
+
+func (s *Server) handleGetHold(svc services.HoldService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		hold, err := svc.GetHold(c.Param("id"))
+		if err != nil {
+			return holdError(err, "fetch hold")
+		}
+
+		return c.JSON(http.StatusOK, hold.ToResponse())
+	}
+}
+
+func (s *Server) handleReleaseHold(svc services.HoldService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.ReleaseHold(c.Param("id")); err != nil {
+			return holdError(err, "release hold")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func holdError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrHoldNotFound), errors.Is(err, services.ErrTicketTypeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrEventNotOpen), errors.Is(err, services.ErrTicketNotOnSale),
+		errors.Is(err, services.ErrSoldOut), errors.Is(err, services.ErrHoldNotActive):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrPaymentRequired):
+		return echo.NewHTTPError(http.StatusPaymentRequired, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"eventmaster-go/internal/importer"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxImportRows      = 5000
+	maxImportFileBytes = 10 << 20
+)
+
+// ImportEventRow is the shape each imported row is validated against: the rules of
+// CreateEventRequest plus the upsert key and the fields only imports can set
+type ImportEventRow struct {
+	CreateEventRequest
+	ExternalID string `json:"externalId" validate:"omitempty,max=255"`
+	EventType  string `json:"eventType" validate:"omitempty,max=255"`
+}
+
+// importDateLayouts are accepted for eventDate besides RFC 3339, read in the row's time zone
+var importDateLayouts = []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// RegisterImportHandlers registers the bulk event import endpoint
+func (s *Server) RegisterImportHandlers(importService services.EventImportService) {
+	s.apiGroup.POST("/events/import", s.handleImportEvents(importService), s.requireAuth)
+}
+
+// handleImportEvents accepts a multipart "file" upload or a raw CSV/JSON body.
+// The mapping, format and dryRun options are read from form fields or query parameters.
+func (s *Server) handleImportEvents(svc services.EventImportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		mapping, err := importer.ParseMapping(c.FormValue("mapping"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		dryRun := false
+		if value := c.FormValue("dryRun"); value != "" {
+			dryRun, err = strconv.ParseBool(value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid dryRun: expected true or false")
+			}
+		}
+
+		var body io.Reader
+		var filename, contentType string
+		if file, err := c.FormFile("file"); err == nil {
+			src, err := file.Open()
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "failed to read uploaded file")
+			}
+			defer src.Close()
+			body, filename, contentType = src, file.Filename, file.Header.Get(echo.HeaderContentType)
+		} else {
+			body, contentType = c.Request().Body, c.Request().Header.Get(echo.HeaderContentType)
+		}
+
+		format, err := importer.DetectFormat(c.FormValue("format"), filename, contentType)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		// Reading one byte past the limit tells an oversized file from one that fits exactly;
+		// a cut-off file must not be parsed, as its last row would be imported half empty
+		data, err := io.ReadAll(io.LimitReader(body, maxImportFileBytes+1))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "failed to read import file")
+		}
+		if len(data) > maxImportFileBytes {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("import files are limited to %d MB", maxImportFileBytes>>20))
+		}
+
+		records, err := importer.Read(bytes.NewReader(data), format, mapping, maxImportRows)
+		if errors.Is(err, importer.ErrTooManyRows) {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("import is limited to %d rows", maxImportRows))
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		report, err := svc.ImportEvents(BuildImportRows(records, s.echo.Validator), userID, dryRun)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to import events")
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// BuildImportRows converts mapped records into service rows, validating each one with
+// the same rules as single event creation. Invalid rows keep their errors for the report.
+func BuildImportRows(records []importer.Record, v echo.Validator) []services.EventImportRow {
+	rows := make([]services.EventImportRow, len(records))
+	for i, record := range records {
+		req, errs := parseImportRecord(record)
+		row := services.EventImportRow{Row: record.Row, ExternalID: req.ExternalID, Errors: errs}
+
+		if len(errs) == 0 {
+			if err := v.Validate(req); err != nil {
+				row.Errors = validationMessages(err)
+			}
+		}
+
+		if len(row.Errors) == 0 {
+			row.Event = importRowToEvent(req)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+func parseImportRecord(record importer.Record) (*ImportEventRow, []string) {
+	values := record.Values
+	req := &ImportEventRow{
+		ExternalID: values["externalId"],
+		EventType:  values["eventType"],
+	}
+	req.TemplateID = values["templateId"]
+	req.VenueID = values["venueId"]
+	req.Location = values["location"]
+	req.Title = values["title"]
+	req.Description = values["description"]
+	req.Organizer = values["organizer"]
+	req.TimeZone = values["timeZone"]
+	req.Status = models.EventStatus(values["status"])
+	req.CategoryIDs = importer.SplitList(values["categoryIds"])
+	req.Tags = importer.SplitList(values["tags"])
+
+	var errs []string
+	coordinates := []struct {
+		field  string
+		target *float64
+	}{
+		{"latitude", &req.Latitude},
+		{"longitude", &req.Longitude},
+	}
+	for _, coordinate := range coordinates {
+		value := values[coordinate.field]
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %q is not a number", coordinate.field, value))
+			continue
+		}
+		*coordinate.target = parsed
+	}
+
+	if value := values["eventDate"]; value != "" {
+		eventDate, err := parseImportDate(value, req.TimeZone)
+		if err != nil {
+			errs = append(errs, "eventDate: "+err.Error())
+		} else {
+			req.EventDate = &eventDate
+		}
+	}
+
+	return req, errs
+}
+
+func parseImportDate(value, timeZone string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	loc := time.UTC
+	if timeZone != "" {
+		if zone, err := time.LoadLocation(timeZone); err == nil {
+			loc = zone
+		}
+	}
+	for _, layout := range importDateLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC 3339 timestamp or YYYY-MM-DD[ HH:MM] date", value)
+}
+
+func importRowToEvent(req *ImportEventRow) *models.Event {
+	event := &models.Event{
+		Title:       req.Title,
+		Description: req.Description,
+		Organizer:   req.Organizer,
+		EventDate:   req.EventDate,
+		TimeZone:    req.TimeZone,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		Location:    req.Location,
+		EventType:   req.EventType,
+		Status:      req.Status,
+	}
+	if req.TemplateID != "" {
+		event.TemplateID = &req.TemplateID
+	}
+	if req.VenueID != "" {
+		event.VenueID = &req.VenueID
+	}
+	for _, categoryID := range req.CategoryIDs {
+		event.Categories = append(event.Categories, models.Category{Base: models.Base{ID: categoryID}})
+	}
+	for _, tag := range req.Tags {
+		event.Tags = append(event.Tags, models.Tag{Name: tag})
+	}
+	return event
+}
+
+// validationMessages flattens validator errors into one message per field
+func validationMessages(err error) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		messages[i] = fmt.Sprintf("%s: failed %q validation", fieldError.Field(), fieldError.Tag())
+	}
+	return messages
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxWebhookBody caps the size of payment webhook payloads
+const maxWebhookBody = 64 << 10
+
+// CheckoutRequest represents the request body for buying tickets
+type CheckoutRequest struct {
+	FullName          string                   `json:"fullName" validate:"required"`
+	Email             string                   `json:"email" validate:"required,email"`
+	DateOfBirth       *time.Time               `json:"dateOfBirth"`
+	SourceOfDiscovery models.SourceOfDiscovery `json:"sourceOfDiscovery" validate:"required,oneof=social_media friends found_myself"`
+	Items             []CheckoutItemRequest    `json:"items" validate:"required,min=1,max=20,dive"`
+	PromoCode         string                   `json:"promoCode" validate:"omitempty,max=64"`
+}
+
+// CheckoutItemRequest is a line of a checkout: how many tickets of a type to buy
+type CheckoutItemRequest struct {
+	TicketTypeID string `json:"ticketTypeId" validate:"required,uuid4"`
+	Quantity     int    `json:"quantity" validate:"required,gte=1,lte=100"`
+}
+
+// CheckoutResponse is a created order with where to pay for it; paid orders have no checkout URL
+type CheckoutResponse struct {
+	Order       *models.OrderResponse `json:"order"`
+	CheckoutURL string                `json:"checkoutUrl,omitempty"`
+}
+
+// FakePaymentRequest settles a payment at the fake provider
+type FakePaymentRequest struct {
+	Outcome services.PaymentOutcome `json:"outcome" validate:"required,oneof=succeeded failed"`
+}
+
+// RegisterOrderHandlers registers order, checkout and payment webhook handlers. The fake
+// provider's checkout endpoint is only registered when fakeProvider is not nil.
+func (s *Server) RegisterOrderHandlers(orderService services.OrderService, fakeProvider *services.FakePaymentProvider) {
+	s.apiGroup.POST("/events/:id/orders", s.handleCheckout(orderService))
+	s.apiGroup.GET("/events/:id/orders", s.handleListEventOrders(orderService), s.requireAuth)
+
+	orderGroup := s.apiGroup.Group("/orders")
+	orderGroup.GET("/:id", s.handleGetOrder(orderService))
+	orderGroup.POST("/:id/refund", s.handleRefundOrder(orderService), s.requireAuth)
+
+	paymentGroup := s.apiGroup.Group("/payments")
+	paymentGroup.POST("/webhook/:provider", s.handlePaymentWebhook(orderService))
+	if fakeProvider != nil {
+		paymentGroup.POST("/fake/:reference", s.handleFakePayment(fakeProvider))
+	}
+}
+
+func (s *Server) handleCheckout(svc services.OrderService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CheckoutRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		order := &models.Order{
+			EventID:           c.Param("id"),
+			Email:             req.Email,
+			FullName:          req.FullName,
+			DateOfBirth:       req.DateOfBirth,
+			SourceOfDiscovery: req.SourceOfDiscovery,
+			PromoCode:         req.PromoCode,
+			Items:             make([]models.OrderItem, len(req.Items)),
+		}
+		for i, item := range req.Items {
+			order.Items[i] = models.OrderItem{TicketTypeID: item.TicketTypeID, Quantity: item.Quantity}
+		}
+
+		order, session, err := svc.Checkout(c.Request().Context(), order)
+		if err != nil {
+			return orderError(err, "check out")
+		}
+
+		resp := &CheckoutResponse{Order: order.ToResponse()}
+		if session != nil {
+			resp.CheckoutURL = session.CheckoutURL
+		}
+		return c.JSON(http.StatusCreated, resp)
+	}
+}
+
+func (s *Server) handleListEventOrders(svc services.OrderService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		orders, err := svc.ListEventOrders(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return orderError(err, "list orders")
+		}
+
+		resp := make([]*models.OrderResponse, len(orders))
+		for i, order := range orders {
+			resp[i] = order.ToResponse()
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleGetOrder(svc services.OrderService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		order, err := svc.GetOrder(c.Param("id"))
+		if err != nil {
+			return orderError(err, "fetch order")
+		}
+
+		return c.JSON(http.StatusOK, order.ToResponse())
+	}
+}
+
+func (s *Server) handleRefundOrder(svc services.OrderService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		order, err := svc.RefundOrder(c.Request().Context(), actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return orderError(err, "refund order")
+		}
+
+		return c.JSON(http.StatusOK, order.ToResponse())
+	}
+}
+
+// handlePaymentWebhook receives payment outcomes from providers. Errors make the provider retry,
+// so only payloads that can never succeed are rejected with a client error.
+func (s *Server) handlePaymentWebhook(svc services.OrderService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := svc.HandleWebhook(c.Param("provider"), c.Request().Header, body); err != nil {
+			return orderError(err, "process payment webhook")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// handleFakePayment stands in for the checkout page of a real provider
+func (s *Server) handleFakePayment(provider *services.FakePaymentProvider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req FakePaymentRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if err := provider.Complete(c.Param("reference"), req.Outcome); err != nil {
+			return orderError(err, "complete payment")
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+func orderError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrOrderNotFound), errors.Is(err, services.ErrPaymentNotFound),
+		errors.Is(err, services.ErrUnknownProvider):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrPromoCodeNotFound), errors.Is(err, services.ErrPromoCodeNotApplicable):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPromoCodeNotValid), errors.Is(err, services.ErrPromoCodeExhausted),
+		errors.Is(err, services.ErrPromoEmailLimit):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrEventNotOpen), errors.Is(err, services.ErrTicketNotOnSale),
+		errors.Is(err, services.ErrSoldOut), errors.Is(err, services.ErrOrderNotRefundable),
+		errors.Is(err, services.ErrOrderStateChanged):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrEmptyOrder), errors.Is(err, services.ErrInvalidOrderItem),
+		errors.Is(err, services.ErrMixedCurrency), errors.Is(err, services.ErrTicketTypeNotFound),
+		errors.Is(err, services.ErrInvalidWebhook):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OrganizerLinkRequest is a labelled link on an organizer profile
+type OrganizerLinkRequest struct {
+	Label string `json:"label" validate:"required,max=100"`
+	URL   string `json:"url" validate:"required,url,max=1000"`
+}
+
+// CreateOrganizerRequest represents the request body for creating an organizer profile
+type CreateOrganizerRequest struct {
+	Name         string                 `json:"name" validate:"required,min=2,max=255"`
+	Bio          string                 `json:"bio" validate:"omitempty,max=5000"`
+	LogoID       string                 `json:"logoId" validate:"omitempty,uuid4"`
+	ContactEmail string                 `json:"contactEmail" validate:"omitempty,email,max=100"`
+	ContactPhone string                 `json:"contactPhone" validate:"omitempty,max=50"`
+	Website      string                 `json:"website" validate:"omitempty,url,max=1000"`
+	Links        []OrganizerLinkRequest `json:"links" validate:"omitempty,max=20,dive"`
+}
+
+// UpdateOrganizerRequest represents the request body for updating an organizer profile;
+// omitted fields are kept and an empty logoId removes the logo
+type UpdateOrganizerRequest struct {
+	Name         *string                `json:"name" validate:"omitempty,min=2,max=255"`
+	Bio          *string                `json:"bio" validate:"omitempty,max=5000"`
+	LogoID       *string                `json:"logoId" validate:"omitempty,uuid4|len=0"`
+	ContactEmail *string                `json:"contactEmail" validate:"omitempty,email|len=0,max=100"`
+	ContactPhone *string                `json:"contactPhone" validate:"omitempty,max=50"`
+	Website      *string                `json:"website" validate:"omitempty,url|len=0,max=1000"`
+	Links        []OrganizerLinkRequest `json:"links" validate:"omitempty,max=20,dive"`
+}
+
+// AddOrganizerManagerRequest names the registered user to add as a manager
+type AddOrganizerManagerRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// OrganizerPageResponse is the public page of an organizer profile
+type OrganizerPageResponse struct {
+	Organizer      *models.OrganizerProfileResponse `json:"organizer"`
+	UpcomingEvents []*models.EventResponse          `json:"upcomingEvents"`
+	UpcomingTotal  int64                            `json:"upcomingTotal"`
+	PastEvents     []*models.EventResponse          `json:"pastEvents"`
+	PastTotal      int64                            `json:"pastTotal"`
+}
+
+// OrganizerManagersResponse lists the users managing an organizer profile
+type OrganizerManagersResponse struct {
+	OrganizerID string                            `json:"organizerId"`
+	Managers    []models.OrganizerManagerResponse `json:"managers"`
+}
+
+// RegisterOrganizerHandlers registers organizer profile HTTP handlers
+func (s *Server) RegisterOrganizerHandlers(organizerService services.OrganizerService) {
+	organizerGroup := s.apiGroup.Group("/organizers")
+
+	organizerGroup.GET("/mine", s.handleListMyOrganizers(organizerService), s.requireAuth)
+	organizerGroup.GET("/:id", s.handleGetOrganizerPage(organizerService))
+
+	protected := organizerGroup.Group("")
+	protected.Use(s.requireAuth)
+	{
+		protected.POST("", s.handleCreateOrganizer(organizerService))
+		protected.PUT("/:id", s.handleUpdateOrganizer(organizerService))
+		protected.DELETE("/:id", s.handleDeleteOrganizer(organizerService))
+		protected.GET("/:id/managers", s.handleListOrganizerManagers(organizerService))
+		protected.POST("/:id/managers", s.handleAddOrganizerManager(organizerService))
+		protected.DELETE("/:id/managers/:userId", s.handleRemoveOrganizerManager(organizerService))
+	}
+}
+
+// handleListMyOrganizers backs the organizer picker of the event form
+func (s *Server) handleListMyOrganizers(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		profiles, err := svc.ListManagedOrganizers(userID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list organizers")
+		}
+
+		responses := make([]*models.OrganizerProfileResponse, len(profiles))
+		for i, profile := range profiles {
+			responses[i] = profile.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, responses)
+	}
+}
+
+// handleGetOrganizerPage returns the public page of a profile; limit caps each event list
+func (s *Server) handleGetOrganizerPage(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		page, err := svc.GetOrganizerPage(c.Param("id"), parseQueryInt(c, "limit", 0))
+		if err != nil {
+			return organizerError(err, "fetch organizer")
+		}
+
+		return c.JSON(http.StatusOK, &OrganizerPageResponse{
+			Organizer:      page.Profile.ToResponse(),
+			UpcomingEvents: eventResponses(page.Upcoming),
+			UpcomingTotal:  page.UpcomingTotal,
+			PastEvents:     eventResponses(page.Past),
+			PastTotal:      page.PastTotal,
+		})
+	}
+}
+
+func (s *Server) handleCreateOrganizer(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		var req CreateOrganizerRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		profile := &models.OrganizerProfile{
+			Name:         req.Name,
+			Bio:          req.Bio,
+			ContactEmail: req.ContactEmail,
+			ContactPhone: req.ContactPhone,
+			Website:      req.Website,
+			Links:        organizerLinks(req.Links),
+		}
+		if req.LogoID != "" {
+			profile.LogoID = &req.LogoID
+		}
+
+		created, err := svc.CreateOrganizer(profile, userID)
+		if err != nil {
+			return organizerError(err, "create organizer")
+		}
+
+		return c.JSON(http.StatusCreated, created.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdateOrganizer(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req UpdateOrganizerRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		profile, err := svc.UpdateOrganizer(actorFromContext(c), c.Param("id"), func(profile *models.OrganizerProfile) {
+			if req.Name != nil {
+				profile.Name = *req.Name
+			}
+			if req.Bio != nil {
+				profile.Bio = *req.Bio
+			}
+			if req.LogoID != nil {
+				profile.LogoID = nil
+				if *req.LogoID != "" {
+					profile.LogoID = req.LogoID
+				}
+			}
+			if req.ContactEmail != nil {
+				profile.ContactEmail = *req.ContactEmail
+			}
+			if req.ContactPhone != nil {
+				profile.ContactPhone = *req.ContactPhone
+			}
+			if req.Website != nil {
+				profile.Website = *req.Website
+			}
+			if req.Links != nil {
+				profile.Links = organizerLinks(req.Links)
+			}
+		})
+		if err != nil {
+			return organizerError(err, "update organizer")
+		}
+
+		return c.JSON(http.StatusOK, profile.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteOrganizer(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteOrganizer(actorFromContext(c), c.Param("id")); err != nil {
+			return organizerError(err, "delete organizer")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleListOrganizerManagers(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		profile, err := svc.ListManagers(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return organizerError(err, "list organizer managers")
+		}
+
+		return c.JSON(http.StatusOK, &OrganizerManagersResponse{
+			OrganizerID: profile.ID,
+			Managers:    profile.ManagerResponses(),
+		})
+	}
+}
+
+func (s *Server) handleAddOrganizerManager(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req AddOrganizerManagerRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		profile, err := svc.AddManager(actorFromContext(c), c.Param("id"), req.Email)
+		if err != nil {
+			return organizerError(err, "add organizer manager")
+		}
+
+		return c.JSON(http.StatusOK, &OrganizerManagersResponse{
+			OrganizerID: profile.ID,
+			Managers:    profile.ManagerResponses(),
+		})
+	}
+}
+
+func (s *Server) handleRemoveOrganizerManager(svc services.OrganizerService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		profile, err := svc.RemoveManager(actorFromContext(c), c.Param("id"), c.Param("userId"))
+		if err != nil {
+			return organizerError(err, "remove organizer manager")
+		}
+
+		return c.JSON(http.StatusOK, &OrganizerManagersResponse{
+			OrganizerID: profile.ID,
+			Managers:    profile.ManagerResponses(),
+		})
+	}
+}
+
+func eventResponses(events []*models.Event) []*models.EventResponse {
+	responses := make([]*models.EventResponse, len(events))
+	for i, event := range events {
+		responses[i] = event.ToResponse()
+	}
+	return responses
+}
+
+func organizerLinks(links []OrganizerLinkRequest) []models.OrganizerLink {
+	result := make([]models.OrganizerLink, len(links))
+	for i, link := range links {
+		result[i] = models.OrganizerLink{Label: link.Label, URL: link.URL}
+	}
+	return result
+}
+
+func organizerError(err error, action string) error {
+	switch {
+	case errors.Is(err, services.ErrOrganizerNotFound), errors.Is(err, services.ErrUserNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrOrganizerInUse), errors.Is(err, services.ErrLastManager):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrImageNotFound):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -1,20 +1,27 @@
 package server
 
 import (
+	"errors"
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
+	"eventmaster-go/internal/repositories"
 	"eventmaster-go/internal/services"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
-// RegisterParticipantRequest represents the request body for registering a participant
+// RegisterParticipantRequest represents the request body for registering a participant.
+// HoldID completes a registration started with a ticket hold; the held ticket type is used.
 type RegisterParticipantRequest struct {
 	FullName          string                   `json:"fullName" validate:"required"`
 	Email             string                   `json:"email" validate:"required,email"`
 	DateOfBirth       *time.Time               `json:"dateOfBirth"`
 	SourceOfDiscovery models.SourceOfDiscovery `json:"sourceOfDiscovery" validate:"required,oneof=social_media friends found_myself"`
+	TicketTypeID      string                   `json:"ticketTypeId" validate:"omitempty,uuid4"`
+	HoldID            string                   `json:"holdId" validate:"omitempty,uuid4"`
 }
 
 // ParticipantResponse represents the participant response
@@ -25,22 +32,30 @@ type ParticipantResponse struct {
 	DateOfBirth       *time.Time               `json:"dateOfBirth"`
 	SourceOfDiscovery models.SourceOfDiscovery `json:"sourceOfDiscovery"`
 	EventID           string                   `json:"eventId"`
+	TicketTypeID      *string                  `json:"ticketTypeId,omitempty"`
 	CreatedAt         time.Time                `json:"createdAt"`
 	UpdatedAt         time.Time                `json:"updatedAt"`
 }
 
-// RegisterParticipantHandlers registers participant-related HTTP handlers
+// RegistrationResponse is a new participant with their ticket
+type RegistrationResponse struct {
+	*models.ParticipantResponse
+	Ticket *TicketResponse `json:"ticket"`
+}
+
+// RegisterParticipantHandlers registers participant-related HTTP handlers. Anyone may
+// register; participant data is only shown to the event's collaborators.
 func (s *Server) RegisterParticipantHandlers(participantService services.ParticipantService) {
 	participantGroup := s.apiGroup.Group("/participant")
 
-	participantGroup.GET("/event/:eventId", s.handleGetEventParticipants(participantService))
-	participantGroup.GET("/event/:eventId/registrations-per-day", s.handleRegistrationsPerDay(participantService))
 	participantGroup.POST("/event/:eventId", s.handleRegisterParticipant(participantService))
-	participantGroup.GET("/:id", s.handleGetParticipant(participantService))
 
 	protected := participantGroup.Group("")
 	protected.Use(s.requireAuth)
 	{
+		protected.GET("/event/:eventId", s.handleGetEventParticipants(participantService))
+		protected.GET("/event/:eventId/registrations-per-day", s.handleRegistrationsPerDay(participantService))
+		protected.GET("/:id", s.handleGetParticipant(participantService))
 		protected.DELETE("/:id", s.handleDeleteParticipant(participantService))
 	}
 }
@@ -68,13 +83,39 @@ func (s *Server) handleRegisterParticipant(svc services.ParticipantService) echo
 			SourceOfDiscovery: req.SourceOfDiscovery,
 			EventID:           eventID,
 		}
+		if req.TicketTypeID != "" {
+			participant.TicketTypeID = &req.TicketTypeID
+		}
 
-		savedParticipant, err := svc.RegisterParticipant(participant)
+		var savedParticipant *models.Participant
+		var err error
+		if req.HoldID != "" {
+			savedParticipant, err = svc.RegisterWithHold(req.HoldID, participant)
+		} else {
+			savedParticipant, err = svc.RegisterParticipant(participant)
+		}
+		switch {
+		case errors.Is(err, services.ErrEventNotOpen), errors.Is(err, services.ErrTicketNotOnSale),
+			errors.Is(err, services.ErrSoldOut), errors.Is(err, services.ErrHoldNotActive):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrHoldNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrTicketTypeRequired), errors.Is(err, services.ErrTicketTypeNotFound):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrPaymentRequired):
+			return echo.NewHTTPError(http.StatusPaymentRequired, err.Error())
+		}
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "failed to register participant: "+err.Error())
 		}
 
-		return c.JSON(http.StatusCreated, savedParticipant.ToResponse())
+		return c.JSON(http.StatusCreated, &RegistrationResponse{
+			ParticipantResponse: savedParticipant.ToResponse(),
+			Ticket: newTicketResponse(&services.Ticket{
+				Participant: savedParticipant,
+				Code:        svc.TicketCode(savedParticipant),
+			}),
+		})
 	}
 }
 
@@ -85,9 +126,14 @@ func (s *Server) handleGetEventParticipants(svc services.ParticipantService) ech
 			return echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
 		}
 
-		participants, err := svc.GetEventParticipants(eventID)
+		q, err := query.Parse(c.QueryParams(), repositories.ParticipantFields)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		participants, err := svc.GetEventParticipants(actorFromContext(c), eventID, q)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch participants")
+			return participantError(err, "fetch participants")
 		}
 
 		// Convert to response objects
@@ -101,6 +147,7 @@ func (s *Server) handleGetEventParticipants(svc services.ParticipantService) ech
 				DateOfBirth:       resp.DateOfBirth,
 				SourceOfDiscovery: resp.SourceOfDiscovery,
 				EventID:           resp.EventID,
+				TicketTypeID:      resp.TicketTypeID,
 				CreatedAt:         resp.CreatedAt,
 				UpdatedAt:         resp.UpdatedAt,
 			}
@@ -117,9 +164,9 @@ func (s *Server) handleGetParticipant(svc services.ParticipantService) echo.Hand
 			return echo.NewHTTPError(http.StatusBadRequest, "participant ID is required")
 		}
 
-		participant, err := svc.GetParticipantByID(id)
+		participant, err := svc.GetParticipantByID(actorFromContext(c), id)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusNotFound, "participant not found")
+			return participantError(err, "fetch participant")
 		}
 
 		resp := participant.ToResponse()
@@ -134,8 +181,8 @@ func (s *Server) handleDeleteParticipant(svc services.ParticipantService) echo.H
 			return echo.NewHTTPError(http.StatusBadRequest, "participant ID is required")
 		}
 
-		if err := svc.DeleteParticipant(id); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete participant")
+		if err := svc.DeleteParticipant(actorFromContext(c), id); err != nil {
+			return participantError(err, "delete participant")
 		}
 
 		return c.NoContent(http.StatusNoContent)
@@ -149,11 +196,29 @@ func (s *Server) handleRegistrationsPerDay(svc services.ParticipantService) echo
 			return echo.NewHTTPError(http.StatusBadRequest, "event ID is required")
 		}
 
-		data, err := svc.RegistrationsPerDay(eventID)
+		timeZone := c.QueryParam("tz")
+		if timeZone != "" {
+			if _, err := time.LoadLocation(timeZone); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid tz: unknown time zone")
+			}
+		}
+
+		data, err := svc.RegistrationsPerDay(actorFromContext(c), eventID, timeZone)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch registration analytics")
+			return participantError(err, "fetch registration analytics")
 		}
 
 		return c.JSON(http.StatusOK, data)
 	}
 }
+
+func participantError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "not found")
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+	"eventmaster-go/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// PromoCodeRequest represents the request body for creating or replacing a promo code. The
+// discount value is a percentage for percent codes and an amount in minor units for fixed ones.
+type PromoCodeRequest struct {
+	Code           string              `json:"code" validate:"required,min=3,max=64"`
+	TicketTypeID   string              `json:"ticketTypeId" validate:"omitempty,uuid4"`
+	DiscountType   models.DiscountType `json:"discountType" validate:"required,oneof=percent fixed"`
+	DiscountValue  int64               `json:"discountValue" validate:"required,gte=1"`
+	Currency       string              `json:"currency" validate:"omitempty,iso4217"`
+	MaxRedemptions *int                `json:"maxRedemptions" validate:"omitempty,gte=0"`
+	PerEmailLimit  *int                `json:"perEmailLimit" validate:"omitempty,gte=1"`
+	ValidFrom      *time.Time          `json:"validFrom"`
+	ValidUntil     *time.Time          `json:"validUntil"`
+}
+
+// PromoCodeReportResponse is the redemption report of one promo code
+type PromoCodeReportResponse struct {
+	*models.PromoCodeResponse
+	Redemptions   int64                               `json:"redemptions"`
+	PaidOrders    int64                               `json:"paidOrders"`
+	DiscountCents int64                               `json:"discountCents"`
+	RevenueCents  int64                               `json:"revenueCents"`
+	BySource      []*repositories.RedemptionReportRow `json:"bySource"`
+}
+
+// RegisterPromoCodeHandlers registers promo code HTTP handlers; all of them are for organizers
+func (s *Server) RegisterPromoCodeHandlers(promoCodeService services.PromoCodeService) {
+	promoGroup := s.apiGroup.Group("/events/:id/promo-codes")
+	promoGroup.Use(s.requireAuth)
+	{
+		promoGroup.GET("", s.handleListPromoCodes(promoCodeService))
+		promoGroup.GET("/report", s.handlePromoCodeReport(promoCodeService))
+		promoGroup.POST("", s.handleCreatePromoCode(promoCodeService))
+		promoGroup.PUT("/:promoCodeId", s.handleUpdatePromoCode(promoCodeService))
+		promoGroup.DELETE("/:promoCodeId", s.handleDeletePromoCode(promoCodeService))
+	}
+}
+
+func (s *Server) handleListPromoCodes(svc services.PromoCodeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		promoCodes, err := svc.ListPromoCodes(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return promoCodeError(err, "list promo codes")
+		}
+
+		resp := make([]*models.PromoCodeResponse, len(promoCodes))
+		for i, promoCode := range promoCodes {
+			resp[i] = promoCode.ToResponse()
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handlePromoCodeReport(svc services.PromoCodeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		reports, err := svc.RedemptionReport(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return promoCodeError(err, "fetch promo code report")
+		}
+
+		resp := make([]*PromoCodeReportResponse, len(reports))
+		for i, report := range reports {
+			resp[i] = &PromoCodeReportResponse{
+				PromoCodeResponse: report.PromoCode.ToResponse(),
+				Redemptions:       report.Redemptions,
+				PaidOrders:        report.PaidOrders,
+				DiscountCents:     report.DiscountCents,
+				RevenueCents:      report.RevenueCents,
+				BySource:          report.BySource,
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleCreatePromoCode(svc services.PromoCodeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req PromoCodeRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		promoCode := &models.PromoCode{}
+		applyPromoCodeRequest(promoCode, &req)
+		promoCode, err := svc.CreatePromoCode(actorFromContext(c), c.Param("id"), promoCode)
+		if err != nil {
+			return promoCodeError(err, "create promo code")
+		}
+
+		return c.JSON(http.StatusCreated, promoCode.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdatePromoCode(svc services.PromoCodeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req PromoCodeRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		promoCode, err := svc.UpdatePromoCode(actorFromContext(c), c.Param("id"), c.Param("promoCodeId"), func(promoCode *models.PromoCode) {
+			applyPromoCodeRequest(promoCode, &req)
+		})
+		if err != nil {
+			return promoCodeError(err, "update promo code")
+		}
+
+		return c.JSON(http.StatusOK, promoCode.ToResponse())
+	}
+}
+
+func (s *Server) handleDeletePromoCode(svc services.PromoCodeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeletePromoCode(actorFromContext(c), c.Param("id"), c.Param("promoCodeId")); err != nil {
+			return promoCodeError(err, "delete promo code")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// applyPromoCodeRequest copies the request onto a promo code, leaving its redemption count alone
+func applyPromoCodeRequest(promoCode *models.PromoCode, req *PromoCodeRequest) {
+	promoCode.Code = req.Code
+	promoCode.TicketTypeID = nil
+	if req.TicketTypeID != "" {
+		promoCode.TicketTypeID = &req.TicketTypeID
+	}
+	promoCode.DiscountType = req.DiscountType
+	promoCode.DiscountValue = req.DiscountValue
+	promoCode.Currency = req.Currency
+	promoCode.MaxRedemptions = req.MaxRedemptions
+	promoCode.PerEmailLimit = req.PerEmailLimit
+	promoCode.ValidFrom = req.ValidFrom
+	promoCode.ValidUntil = req.ValidUntil
+}
+
+func promoCodeError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrPromoCodeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrPromoCodeTaken), errors.Is(err, services.ErrRedemptionsAboveMax),
+		errors.Is(err, services.ErrPromoCodeInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidPromoCode), errors.Is(err, services.ErrTicketTypeNotFound):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
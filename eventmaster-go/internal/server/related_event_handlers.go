@@ -0,0 +1,57 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// defaultRelatedEventsLimit is how many related events are returned without a limit parameter
+const defaultRelatedEventsLimit = 6
+
+// RelatedEventResponse is an upcoming event recommended alongside another
+type RelatedEventResponse struct {
+	*models.EventResponse
+	// Score ranks the recommendations; it only has meaning relative to the other results
+	Score      float64 `json:"score"`
+	DistanceKm float64 `json:"distanceKm"`
+}
+
+// RegisterRelatedEventHandlers registers the related events HTTP handler
+func (s *Server) RegisterRelatedEventHandlers(relatedEventService services.RelatedEventService) {
+	s.apiGroup.GET("/events/:id/related", s.handleRelatedEvents(relatedEventService), s.optionalAuth)
+}
+
+// handleRelatedEvents lists upcoming events similar to the event by topic, place, date and
+// audience
+func (s *Server) handleRelatedEvents(svc services.RelatedEventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		limit := parseQueryInt(c, "limit", defaultRelatedEventsLimit)
+		if limit > services.MaxRelatedEvents {
+			limit = services.MaxRelatedEvents
+		}
+
+		related, err := svc.RelatedEvents(actorFromContext(c), c.Param("id"), limit)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch related events")
+		}
+
+		resp := make([]*RelatedEventResponse, len(related))
+		for i, candidate := range related {
+			resp[i] = &RelatedEventResponse{
+				EventResponse: candidate.Event.ToResponse(),
+				Score:         candidate.Score,
+				DistanceKm:    candidate.DistanceKm,
+			}
+		}
+		c.Response().Header().Set("Cache-Control", "private, max-age=300")
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handleGetEventRevisions(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := s.loadEvent(c, svc, services.PermissionView, "view the history of")
+		if err != nil {
+			return err
+		}
+
+		revisions, err := svc.GetRevisions(event.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch revisions")
+		}
+
+		responses := make([]*models.EventRevisionResponse, len(revisions))
+		for i, revision := range revisions {
+			responses[i] = revision.ToResponse(false)
+		}
+
+		return c.JSON(http.StatusOK, responses)
+	}
+}
+
+func (s *Server) handleGetEventRevision(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := s.loadEvent(c, svc, services.PermissionView, "view the history of")
+		if err != nil {
+			return err
+		}
+
+		number, err := parseRevisionNumber(c)
+		if err != nil {
+			return err
+		}
+
+		revision, err := svc.GetRevision(event.ID, number)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "revision not found")
+		}
+
+		return c.JSON(http.StatusOK, revision.ToResponse(true))
+	}
+}
+
+func (s *Server) handleRevertEventRevision(svc services.EventService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := s.loadEvent(c, svc, services.PermissionEdit, "revert")
+		if err != nil {
+			return err
+		}
+
+		number, err := parseRevisionNumber(c)
+		if err != nil {
+			return err
+		}
+
+		if _, err := svc.GetRevision(event.ID, number); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "revision not found")
+		}
+
+		userID, _ := c.Get("userID").(string)
+		reverted, err := svc.RevertToRevision(event.ID, number, userID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to revert event")
+		}
+
+		return c.JSON(http.StatusOK, reverted.ToResponse())
+	}
+}
+
+func parseRevisionNumber(c echo.Context) (int, error) {
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid revision number")
+	}
+	return number, nil
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"eventmaster-go/internal/models"
 	"eventmaster-go/internal/services"
 	"fmt"
 	"net/http"
@@ -10,19 +11,24 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 )
 
+const adminRoleName = "admin"
+
 type Server struct {
 	echo        *echo.Echo
 	apiGroup    *echo.Group
 	config      Config
 	authService services.AuthService
+	eventAccess services.EventAccess
 }
 
 type Config struct {
 	Port              string
 	SessionCookieName string
+	PublicURL         string
+	RequireIfMatch    bool
 }
 
-func NewServer(authService services.AuthService, config Config) *Server {
+func NewServer(authService services.AuthService, eventAccess services.EventAccess, config Config) *Server {
 	e := echo.New()
 
 	// Middleware
@@ -31,7 +37,8 @@ func NewServer(authService services.AuthService, config Config) *Server {
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:3001"},
 		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderContentType, echo.HeaderAuthorization, echo.HeaderAccept, "X-Requested-With"},
+		AllowHeaders: []string{echo.HeaderContentType, echo.HeaderAuthorization, echo.HeaderAccept, "X-Requested-With", headerIfMatch, headerIfNoneMatch},
+		ExposeHeaders: []string{headerETag},
 		AllowCredentials: true,
 	}))
 
@@ -47,6 +54,7 @@ func NewServer(authService services.AuthService, config Config) *Server {
 		apiGroup:    apiGroup,
 		config:      config,
 		authService: authService,
+		eventAccess: eventAccess,
 	}
 
 	// Setup routes
@@ -68,6 +76,7 @@ func (s *Server) setupRoutes(authService services.AuthService) {
 
 	// Protected user route
 	s.apiGroup.GET("/user", s.requireAuth(s.handleGetCurrentUser(authService)))
+	s.apiGroup.GET("/users", s.handleListUsers(authService), s.requireAuth, s.requireAdmin)
 }
 
 func (s *Server) requireAuth(next echo.HandlerFunc) echo.HandlerFunc {
@@ -93,6 +102,59 @@ func (s *Server) requireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// requireAdmin restricts a route to users holding the admin role; it must run after requireAuth
+func (s *Server) requireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !isAdmin(c) {
+			return echo.NewHTTPError(http.StatusForbidden, "admin privileges required")
+		}
+		return next(c)
+	}
+}
+
+// isAdmin reports whether the authenticated user holds the admin role
+func isAdmin(c echo.Context) bool {
+	user, _ := c.Get("currentUser").(*models.User)
+	if user == nil {
+		return false
+	}
+	for _, role := range user.Roles {
+		if role != nil && role.Name == adminRoleName {
+			return true
+		}
+	}
+	return false
+}
+
+// actorFromContext describes the authenticated caller for service-level authorization
+func actorFromContext(c echo.Context) services.Actor {
+	userID, _ := c.Get("userID").(string)
+	actor := services.Actor{UserID: userID, IsAdmin: isAdmin(c)}
+	if user, _ := c.Get("currentUser").(*models.User); user != nil {
+		actor.Email = user.Email
+	}
+	return actor
+}
+
+// optionalAuth identifies the caller when a valid session cookie is present
+// but lets anonymous requests through
+func (s *Server) optionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cookie, err := c.Cookie(s.config.SessionCookieName)
+		if err != nil || cookie.Value == "" {
+			return next(c)
+		}
+
+		user, err := s.authService.ValidateSession(cookie.Value)
+		if err == nil {
+			c.Set("userID", user.ID)
+			c.Set("currentUser", user)
+		}
+
+		return next(c)
+	}
+}
+
 // Start starts the HTTP server
 func (s *Server) Start() error {
 	return s.echo.Start(fmt.Sprintf(":%s", s.config.Port))
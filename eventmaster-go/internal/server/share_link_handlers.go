@@ -0,0 +1,118 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// CreateShareLinkRequest represents the request body for creating a share link
+type CreateShareLinkRequest struct {
+	Label string `json:"label" validate:"omitempty,max=100"`
+}
+
+// ShareLinkResponse is a share link with the short URL to hand out
+type ShareLinkResponse struct {
+	*models.ShareLinkResponse
+	URL string `json:"url"`
+}
+
+// RegisterShareLinkHandlers registers share link HTTP handlers. Organizers manage the links of
+// their events; anyone may follow one.
+func (s *Server) RegisterShareLinkHandlers(shareLinkService services.ShareLinkService) {
+	shareGroup := s.apiGroup.Group("/events/:id/share-links")
+	shareGroup.Use(s.requireAuth)
+	{
+		shareGroup.GET("", s.handleListShareLinks(shareLinkService))
+		shareGroup.POST("", s.handleCreateShareLink(shareLinkService))
+		shareGroup.DELETE("/:linkId", s.handleDeleteShareLink(shareLinkService))
+	}
+
+	s.apiGroup.GET("/s/:code", s.handleFollowShareLink(shareLinkService))
+}
+
+func (s *Server) handleListShareLinks(svc services.ShareLinkService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		links, err := svc.ListShareLinks(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return shareLinkError(err, "list share links")
+		}
+
+		resp := make([]*ShareLinkResponse, len(links))
+		for i, link := range links {
+			resp[i] = s.shareLinkResponse(link)
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleCreateShareLink(svc services.ShareLinkService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CreateShareLinkRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		link, err := svc.CreateShareLink(actorFromContext(c), c.Param("id"), req.Label)
+		if err != nil {
+			return shareLinkError(err, "create share link")
+		}
+
+		return c.JSON(http.StatusCreated, s.shareLinkResponse(link))
+	}
+}
+
+func (s *Server) handleDeleteShareLink(svc services.ShareLinkService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteShareLink(actorFromContext(c), c.Param("id"), c.Param("linkId")); err != nil {
+			return shareLinkError(err, "delete share link")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// handleFollowShareLink counts the click and redirects to the event's page
+func (s *Server) handleFollowShareLink(svc services.ShareLinkService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := svc.FollowShareLink(c.Param("code"))
+		if err != nil {
+			return shareLinkError(err, "follow share link")
+		}
+
+		ref := event.ID
+		if event.Slug != nil {
+			ref = *event.Slug
+		}
+		return c.Redirect(http.StatusFound, strings.TrimRight(s.config.PublicURL, "/")+"/events/"+ref)
+	}
+}
+
+func (s *Server) shareLinkResponse(link *models.ShareLink) *ShareLinkResponse {
+	return &ShareLinkResponse{
+		ShareLinkResponse: link.ToResponse(),
+		URL:               strings.TrimRight(s.config.PublicURL, "/") + "/api/s/" + link.Code,
+	}
+}
+
+func shareLinkError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrShareLinkNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
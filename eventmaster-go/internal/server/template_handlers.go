@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// CreateTemplateRequest represents the request body for saving an event template
+type CreateTemplateRequest struct {
+	Name        string   `json:"name" validate:"required,min=1,max=255"`
+	Title       string   `json:"title" validate:"omitempty,min=2,max=255"`
+	Description string   `json:"description" validate:"omitempty,max=5000"`
+	Organizer   string   `json:"organizer" validate:"omitempty,max=255"`
+	TimeZone    string   `json:"timeZone" validate:"omitempty,timezone"`
+	Latitude    float64  `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude   float64  `json:"longitude" validate:"gte=-180,lte=180"`
+	Location    string   `json:"location" validate:"omitempty,max=1000"`
+	VenueID     string   `json:"venueId" validate:"omitempty,uuid4"`
+	EventType   string   `json:"eventType" validate:"omitempty,max=255"`
+	CategoryIDs []string `json:"categoryIds" validate:"omitempty,unique,dive,uuid4"`
+	Tags        []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50"`
+	ImageIDs    []string `json:"imageIds" validate:"omitempty,dive,uuid4"`
+}
+
+// CreateTemplateFromEventRequest represents the request body for saving an event as a template
+type CreateTemplateFromEventRequest struct {
+	Name string `json:"name" validate:"omitempty,max=255"`
+}
+
+// RegisterTemplateHandlers registers handlers for saved event templates
+func (s *Server) RegisterTemplateHandlers(templateService services.EventTemplateService) {
+	templateGroup := s.apiGroup.Group("/event-templates")
+	templateGroup.Use(s.requireAuth)
+
+	templateGroup.GET("", s.handleGetTemplates(templateService))
+	templateGroup.POST("", s.handleCreateTemplate(templateService))
+	templateGroup.GET("/:id", s.handleGetTemplate(templateService))
+	templateGroup.DELETE("/:id", s.handleDeleteTemplate(templateService))
+
+	s.apiGroup.POST("/events/:id/template", s.handleCreateTemplateFromEvent(templateService), s.requireAuth)
+}
+
+func (s *Server) handleGetTemplates(svc services.EventTemplateService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		templates, err := svc.ListTemplates(userID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch templates")
+		}
+
+		responses := make([]*models.EventTemplateResponse, len(templates))
+		for i, template := range templates {
+			responses[i] = template.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, responses)
+	}
+}
+
+func (s *Server) handleGetTemplate(svc services.EventTemplateService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		template, err := svc.GetTemplate(c.Param("id"), userID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "template not found")
+		}
+
+		return c.JSON(http.StatusOK, template.ToResponse())
+	}
+}
+
+func (s *Server) handleCreateTemplate(svc services.EventTemplateService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		var req CreateTemplateRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		template := &models.EventTemplate{
+			Name:        req.Name,
+			Title:       req.Title,
+			Description: req.Description,
+			Organizer:   req.Organizer,
+			TimeZone:    req.TimeZone,
+			Latitude:    req.Latitude,
+			Longitude:   req.Longitude,
+			Location:    req.Location,
+			EventType:   req.EventType,
+			CategoryIDs: req.CategoryIDs,
+			Tags:        req.Tags,
+			ImageIDs:    req.ImageIDs,
+		}
+		if req.VenueID != "" {
+			template.VenueID = &req.VenueID
+		}
+
+		created, err := svc.CreateTemplate(template, userID)
+		if errors.Is(err, services.ErrCategoryNotFound) || errors.Is(err, services.ErrImageNotFound) ||
+			errors.Is(err, services.ErrVenueNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create template")
+		}
+
+		return c.JSON(http.StatusCreated, created.ToResponse())
+	}
+}
+
+func (s *Server) handleCreateTemplateFromEvent(svc services.EventTemplateService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CreateTemplateFromEventRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		template, err := svc.CreateTemplateFromEvent(actorFromContext(c), c.Param("id"), req.Name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create template")
+		}
+
+		return c.JSON(http.StatusCreated, template.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteTemplate(svc services.EventTemplateService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		err := svc.DeleteTemplate(c.Param("id"), userID)
+		if errors.Is(err, services.ErrTemplateNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "template not found")
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete template")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,361 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"eventmaster-go/pkg/qr"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// liveHeartbeat is how often an idle check-in stream sends a comment to keep proxies from closing it
+const liveHeartbeat = 25 * time.Second
+
+// TicketResponse is a participant's ticket with links to its QR code
+type TicketResponse struct {
+	ParticipantID string     `json:"participantId"`
+	EventID       string     `json:"eventId"`
+	FullName      string     `json:"fullName"`
+	TicketTypeID  *string    `json:"ticketTypeId,omitempty"`
+	Code          string     `json:"code"`
+	QRPNGURL      string     `json:"qrPngUrl"`
+	QRSVGURL      string     `json:"qrSvgUrl"`
+	PDFURL        string     `json:"pdfUrl"`
+	CheckedInAt   *time.Time `json:"checkedInAt,omitempty"`
+}
+
+// CheckInRequest represents a scanned ticket code
+type CheckInRequest struct {
+	Code string `json:"code" validate:"required,max=128"`
+}
+
+// SyncCheckInsRequest is a batch of scans a kiosk recorded while offline
+type SyncCheckInsRequest struct {
+	DeviceID string               `json:"deviceId" validate:"required,max=100"`
+	Scans    []OfflineScanRequest `json:"scans" validate:"required,min=1,max=1000,dive"`
+}
+
+// OfflineScanRequest is one offline scan with the kiosk's clock time
+type OfflineScanRequest struct {
+	Code      string    `json:"code" validate:"required,max=128"`
+	ScannedAt time.Time `json:"scannedAt" validate:"required"`
+}
+
+// SyncResultResponse reports what became of one uploaded scan, in upload order
+type SyncResultResponse struct {
+	Code          string                  `json:"code"`
+	Status        services.SyncStatus     `json:"status"`
+	Reason        string                  `json:"reason,omitempty"`
+	ParticipantID string                  `json:"participantId,omitempty"`
+	CheckIn       *models.CheckInResponse `json:"checkIn,omitempty"`
+}
+
+// ManifestKeyResponse is the public key verifying ticket manifest signatures
+type ManifestKeyResponse struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"publicKey"`
+}
+
+// CheckInResultResponse is the outcome of a scan; duplicates carry the first check-in
+type CheckInResultResponse struct {
+	Message string                  `json:"message,omitempty"`
+	Ticket  *TicketResponse         `json:"ticket"`
+	CheckIn *models.CheckInResponse `json:"checkIn"`
+}
+
+// RegisterTicketHandlers registers ticket, QR code and check-in HTTP handlers. Tickets are
+// looked up by their code, which is the credential; check-ins are for organizers.
+func (s *Server) RegisterTicketHandlers(ticketService services.TicketService) {
+	ticketGroup := s.apiGroup.Group("/tickets")
+	ticketGroup.GET("/manifest-key", s.handleManifestKey(ticketService))
+	ticketGroup.GET("/:code", s.handleGetTicket(ticketService))
+	ticketGroup.GET("/:code/qr.png", s.handleTicketQRPNG(ticketService))
+	ticketGroup.GET("/:code/qr.svg", s.handleTicketQRSVG(ticketService))
+
+	s.apiGroup.GET("/orders/:id/tickets", s.handleOrderTickets(ticketService))
+
+	checkInGroup := s.apiGroup.Group("/events/:id/check-ins")
+	checkInGroup.Use(s.requireAuth)
+	{
+		checkInGroup.POST("", s.handleCheckIn(ticketService))
+		checkInGroup.GET("", s.handleListCheckIns(ticketService))
+		checkInGroup.GET("/stats", s.handleCheckInStats(ticketService))
+		checkInGroup.GET("/live", s.handleLiveCheckIns(ticketService))
+		checkInGroup.GET("/manifest", s.handleTicketManifest(ticketService))
+		checkInGroup.POST("/sync", s.handleSyncCheckIns(ticketService))
+	}
+}
+
+func (s *Server) handleGetTicket(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ticket, err := svc.FindTicket(c.Param("code"))
+		if err != nil {
+			return ticketError(err, "fetch ticket")
+		}
+
+		return c.JSON(http.StatusOK, newTicketResponse(ticket))
+	}
+}
+
+func (s *Server) handleTicketQRPNG(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ticket, err := svc.FindTicket(c.Param("code"))
+		if err != nil {
+			return ticketError(err, "fetch ticket")
+		}
+
+		png, err := qr.PNG(ticket.Code, qr.DefaultSize)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to render QR code")
+		}
+		c.Response().Header().Set("Cache-Control", "private, max-age=86400")
+		return c.Blob(http.StatusOK, qr.PNGContentType, png)
+	}
+}
+
+func (s *Server) handleTicketQRSVG(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ticket, err := svc.FindTicket(c.Param("code"))
+		if err != nil {
+			return ticketError(err, "fetch ticket")
+		}
+
+		svg, err := qr.SVG(ticket.Code)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to render QR code")
+		}
+		c.Response().Header().Set("Cache-Control", "private, max-age=86400")
+		return c.Blob(http.StatusOK, qr.SVGContentType, []byte(svg))
+	}
+}
+
+func (s *Server) handleOrderTickets(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tickets, err := svc.OrderTickets(c.Param("id"))
+		if err != nil {
+			return ticketError(err, "fetch tickets")
+		}
+
+		resp := make([]*TicketResponse, len(tickets))
+		for i, ticket := range tickets {
+			resp[i] = newTicketResponse(ticket)
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleCheckIn(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req CheckInRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		ticket, err := svc.CheckIn(actorFromContext(c), c.Param("id"), req.Code)
+		if errors.Is(err, services.ErrAlreadyCheckedIn) {
+			return c.JSON(http.StatusConflict, &CheckInResultResponse{
+				Message: err.Error(),
+				Ticket:  newTicketResponse(ticket),
+				CheckIn: ticket.CheckIn.ToResponse(),
+			})
+		}
+		if err != nil {
+			return ticketError(err, "check in ticket")
+		}
+
+		return c.JSON(http.StatusCreated, &CheckInResultResponse{
+			Ticket:  newTicketResponse(ticket),
+			CheckIn: ticket.CheckIn.ToResponse(),
+		})
+	}
+}
+
+func (s *Server) handleListCheckIns(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		checkIns, err := svc.ListCheckIns(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return ticketError(err, "list check-ins")
+		}
+
+		resp := make([]*models.CheckInResponse, len(checkIns))
+		for i, checkIn := range checkIns {
+			resp[i] = checkIn.ToResponse()
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleCheckInStats(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		stats, err := svc.CheckInStats(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return ticketError(err, "fetch check-in stats")
+		}
+
+		return c.JSON(http.StatusOK, stats)
+	}
+}
+
+// handleLiveCheckIns streams the check-in counts of an event as server-sent events: the
+// current counts first, then fresh counts after every check-in
+func (s *Server) handleLiveCheckIns(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		actor := actorFromContext(c)
+		eventID := c.Param("id")
+		updates, unsubscribe, err := svc.SubscribeCheckIns(actor, eventID)
+		if err != nil {
+			return ticketError(err, "follow check-ins")
+		}
+		defer unsubscribe()
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set("Cache-Control", "no-cache")
+		res.Header().Set("Connection", "keep-alive")
+		res.WriteHeader(http.StatusOK)
+
+		send := func() error {
+			stats, err := svc.CheckInStats(actor, eventID)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(stats)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "event: stats\ndata: %s\n\n", data); err != nil {
+				return err
+			}
+			res.Flush()
+			return nil
+		}
+		if err := send(); err != nil {
+			return nil
+		}
+
+		heartbeat := time.NewTicker(liveHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case <-updates:
+				if err := send(); err != nil {
+					return nil
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+					return nil
+				}
+				res.Flush()
+			}
+		}
+	}
+}
+
+func (s *Server) handleManifestKey(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, &ManifestKeyResponse{Algorithm: "Ed25519", PublicKey: svc.ManifestPublicKey()})
+	}
+}
+
+// handleTicketManifest serves the ticket manifest kiosks validate against while offline. The
+// signature covers the exact response body and is sent in the X-Manifest-Signature header.
+func (s *Server) handleTicketManifest(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		manifest, signature, err := svc.TicketManifest(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return ticketError(err, "fetch ticket manifest")
+		}
+
+		c.Response().Header().Set("X-Manifest-Signature", signature)
+		c.Response().Header().Set("Cache-Control", "no-store")
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, manifest)
+	}
+}
+
+// handleSyncCheckIns accepts the scans a kiosk made offline and reports the outcome of each
+func (s *Server) handleSyncCheckIns(svc services.TicketService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req SyncCheckInsRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		scans := make([]services.OfflineScan, len(req.Scans))
+		for i, scan := range req.Scans {
+			scans[i] = services.OfflineScan{Code: scan.Code, ScannedAt: scan.ScannedAt}
+		}
+		results, err := svc.SyncCheckIns(actorFromContext(c), c.Param("id"), req.DeviceID, scans)
+		if err != nil {
+			return ticketError(err, "sync check-ins")
+		}
+
+		resp := make([]*SyncResultResponse, len(results))
+		for i, result := range results {
+			resp[i] = &SyncResultResponse{
+				Code:          result.Code,
+				Status:        result.Status,
+				Reason:        result.Reason,
+				ParticipantID: result.ParticipantID,
+			}
+			if result.CheckIn != nil {
+				resp[i].CheckIn = result.CheckIn.ToResponse()
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+// newTicketResponse converts a ticket, linking its QR code images
+func newTicketResponse(ticket *services.Ticket) *TicketResponse {
+	base := "/api/tickets/" + url.PathEscape(ticket.Code)
+	resp := &TicketResponse{
+		ParticipantID: ticket.Participant.ID,
+		EventID:       ticket.Participant.EventID,
+		FullName:      ticket.Participant.FullName,
+		TicketTypeID:  ticket.Participant.TicketTypeID,
+		Code:          ticket.Code,
+		QRPNGURL:      base + "/qr.png",
+		QRSVGURL:      base + "/qr.svg",
+		PDFURL:        base + "/ticket.pdf",
+	}
+	if ticket.CheckIn != nil {
+		resp.CheckedInAt = &ticket.CheckIn.CheckedInAt
+	}
+	return resp
+}
+
+func ticketError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrTicketNotFound), errors.Is(err, services.ErrOrderNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrTicketRevoked):
+		return echo.NewHTTPError(http.StatusGone, err.Error())
+	case errors.Is(err, services.ErrWrongEvent), errors.Is(err, services.ErrOrderNotPaid):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidTicketCode):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// TicketTypeRequest represents the request body for creating or replacing a ticket type.
+// Prices are in the minor unit of the currency; an omitted quantity means unlimited.
+type TicketTypeRequest struct {
+	Name        string     `json:"name" validate:"required,min=1,max=255"`
+	Description string     `json:"description" validate:"omitempty,max=2000"`
+	PriceCents  int64      `json:"priceCents" validate:"gte=0"`
+	Currency    string     `json:"currency" validate:"omitempty,iso4217"`
+	Quantity    *int       `json:"quantity" validate:"omitempty,gte=0,lte=1000000"`
+	SalesStart  *time.Time `json:"salesStart"`
+	SalesEnd    *time.Time `json:"salesEnd"`
+	MinPerOrder int        `json:"minPerOrder" validate:"gte=0,lte=100"`
+	MaxPerOrder int        `json:"maxPerOrder" validate:"gte=0,lte=100"`
+	Position    int        `json:"position" validate:"gte=0,lte=1000"`
+}
+
+// TicketTypeListResponse lists the ticket types of an event with the tickets left overall
+type TicketTypeListResponse struct {
+	EventID          string                       `json:"eventId"`
+	TicketTypes      []*models.TicketTypeResponse `json:"ticketTypes"`
+	TicketsAvailable *int                         `json:"ticketsAvailable,omitempty"`
+}
+
+// RegisterTicketTypeHandlers registers ticket type HTTP handlers
+func (s *Server) RegisterTicketTypeHandlers(ticketTypeService services.TicketTypeService) {
+	ticketTypeGroup := s.apiGroup.Group("/events/:id/ticket-types")
+
+	ticketTypeGroup.GET("", s.handleListTicketTypes(ticketTypeService), s.optionalAuth)
+
+	protected := ticketTypeGroup.Group("")
+	protected.Use(s.requireAuth)
+	{
+		protected.POST("", s.handleCreateTicketType(ticketTypeService))
+		protected.PUT("/:ticketTypeId", s.handleUpdateTicketType(ticketTypeService))
+		protected.DELETE("/:ticketTypeId", s.handleDeleteTicketType(ticketTypeService))
+	}
+}
+
+func (s *Server) handleListTicketTypes(svc services.TicketTypeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, ticketTypes, err := svc.ListTicketTypes(c.Param("id"))
+		if err != nil {
+			return ticketTypeError(err, "fetch ticket types")
+		}
+		if !s.canViewEvent(c, event) {
+			return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		}
+
+		resp := &TicketTypeListResponse{
+			EventID:     event.ID,
+			TicketTypes: make([]*models.TicketTypeResponse, len(ticketTypes)),
+		}
+		event.TicketTypes = make([]models.TicketType, len(ticketTypes))
+		for i, ticketType := range ticketTypes {
+			resp.TicketTypes[i] = ticketType.ToResponse()
+			event.TicketTypes[i] = *ticketType
+		}
+		resp.TicketsAvailable = event.TicketsAvailable()
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleCreateTicketType(svc services.TicketTypeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req TicketTypeRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		ticketType := &models.TicketType{}
+		applyTicketTypeRequest(ticketType, &req)
+		ticketType, err := svc.CreateTicketType(actorFromContext(c), c.Param("id"), ticketType)
+		if err != nil {
+			return ticketTypeError(err, "create ticket type")
+		}
+
+		return c.JSON(http.StatusCreated, ticketType.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdateTicketType(svc services.TicketTypeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req TicketTypeRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		ticketType, err := svc.UpdateTicketType(actorFromContext(c), c.Param("id"), c.Param("ticketTypeId"), func(ticketType *models.TicketType) {
+			applyTicketTypeRequest(ticketType, &req)
+		})
+		if err != nil {
+			return ticketTypeError(err, "update ticket type")
+		}
+
+		return c.JSON(http.StatusOK, ticketType.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteTicketType(svc services.TicketTypeService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteTicketType(actorFromContext(c), c.Param("id"), c.Param("ticketTypeId")); err != nil {
+			return ticketTypeError(err, "delete ticket type")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// applyTicketTypeRequest copies the request onto a ticket type, leaving its counters alone
+func applyTicketTypeRequest(ticketType *models.TicketType, req *TicketTypeRequest) {
+	ticketType.Name = req.Name
+	ticketType.Description = req.Description
+	ticketType.PriceCents = req.PriceCents
+	ticketType.Currency = req.Currency
+	ticketType.Quantity = req.Quantity
+	ticketType.SalesStart = req.SalesStart
+	ticketType.SalesEnd = req.SalesEnd
+	ticketType.MinPerOrder = req.MinPerOrder
+	ticketType.MaxPerOrder = req.MaxPerOrder
+	ticketType.Position = req.Position
+}
+
+func ticketTypeError(err error, action string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+	case errors.Is(err, services.ErrTicketTypeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrInformationalTicket), errors.Is(err, services.ErrQuantityBelowSold),
+		errors.Is(err, services.ErrTicketTypeInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidTicketType):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterTrashHandlers registers handlers for listing, restoring and purging soft-deleted items
+func (s *Server) RegisterTrashHandlers(trashService services.TrashService) {
+	trashGroup := s.apiGroup.Group("/trash")
+	trashGroup.Use(s.requireAuth)
+
+	trashGroup.GET("/events", s.handleListTrashedEvents(trashService))
+	trashGroup.POST("/events/:id/restore", s.handleRestoreEvent(trashService))
+	trashGroup.DELETE("/events/:id", s.handlePurgeEvent(trashService))
+
+	trashGroup.GET("/participants", s.handleListTrashedParticipants(trashService))
+	trashGroup.POST("/participants/:id/restore", s.handleRestoreParticipant(trashService))
+	trashGroup.DELETE("/participants/:id", s.handlePurgeParticipant(trashService))
+}
+
+func (s *Server) handleListTrashedEvents(svc services.TrashService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		events, err := svc.ListDeletedEvents(actorFromContext(c))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch trashed events")
+		}
+
+		responses := make([]*models.EventResponse, len(events))
+		for i, event := range events {
+			responses[i] = event.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, responses)
+	}
+}
+
+func (s *Server) handleRestoreEvent(svc services.TrashService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		event, err := svc.RestoreEvent(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return trashError(err, "restore event")
+		}
+
+		return c.JSON(http.StatusOK, event.ToResponse())
+	}
+}
+
+func (s *Server) handlePurgeEvent(svc services.TrashService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.PurgeEvent(actorFromContext(c), c.Param("id")); err != nil {
+			return trashError(err, "purge event")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleListTrashedParticipants(svc services.TrashService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		participants, err := svc.ListDeletedParticipants(actorFromContext(c))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch trashed participants")
+		}
+
+		responses := make([]*models.ParticipantResponse, len(participants))
+		for i, participant := range participants {
+			responses[i] = participant.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, responses)
+	}
+}
+
+func (s *Server) handleRestoreParticipant(svc services.TrashService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		participant, err := svc.RestoreParticipant(actorFromContext(c), c.Param("id"))
+		if err != nil {
+			return trashError(err, "restore participant")
+		}
+
+		return c.JSON(http.StatusOK, participant.ToResponse())
+	}
+}
+
+func (s *Server) handlePurgeParticipant(svc services.TrashService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.PurgeParticipant(actorFromContext(c), c.Param("id")); err != nil {
+			return trashError(err, "purge participant")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func trashError(err error, action string) error {
+	switch {
+	case errors.Is(err, services.ErrNotInTrash):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrParentEventDeleted), errors.Is(err, services.ErrSoldOut),
+		errors.Is(err, services.ErrOrderRefunded):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateVenueRequest represents the request body for creating a venue
+type CreateVenueRequest struct {
+	Name      string  `json:"name" validate:"required,min=2,max=255"`
+	Address   string  `json:"address" validate:"omitempty,max=1000"`
+	City      string  `json:"city" validate:"omitempty,max=100"`
+	Country   string  `json:"country" validate:"omitempty,max=100"`
+	Latitude  float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	TimeZone  string  `json:"timeZone" validate:"omitempty,timezone"`
+	Capacity  *int    `json:"capacity" validate:"omitempty,gte=1"`
+}
+
+// UpdateVenueRequest represents the request body for updating a venue; omitted fields are kept
+type UpdateVenueRequest struct {
+	Name      *string  `json:"name" validate:"omitempty,min=2,max=255"`
+	Address   *string  `json:"address" validate:"omitempty,max=1000"`
+	City      *string  `json:"city" validate:"omitempty,max=100"`
+	Country   *string  `json:"country" validate:"omitempty,max=100"`
+	Latitude  *float64 `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	TimeZone  *string  `json:"timeZone" validate:"omitempty,timezone"`
+	Capacity  *int     `json:"capacity" validate:"omitempty,gte=1"`
+}
+
+// RegisterVenueHandlers registers venue-related HTTP handlers
+func (s *Server) RegisterVenueHandlers(venueService services.VenueService) {
+	venueGroup := s.apiGroup.Group("/venues")
+
+	venueGroup.GET("", s.handleSearchVenues(venueService))
+	venueGroup.GET("/:id", s.handleGetVenue(venueService))
+
+	protected := venueGroup.Group("")
+	protected.Use(s.requireAuth)
+	{
+		protected.POST("", s.handleCreateVenue(venueService))
+		protected.PUT("/:id", s.handleUpdateVenue(venueService))
+		protected.DELETE("/:id", s.handleDeleteVenue(venueService))
+	}
+}
+
+// handleSearchVenues backs the venue picker: q matches name or city, city narrows to one city
+func (s *Server) handleSearchVenues(svc services.VenueService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		venues, err := svc.SearchVenues(c.QueryParam("q"), c.QueryParam("city"), parseQueryInt(c, "limit", 0))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to search venues")
+		}
+
+		responses := make([]*models.VenueResponse, len(venues))
+		for i, venue := range venues {
+			responses[i] = venue.ToResponse()
+		}
+
+		return c.JSON(http.StatusOK, responses)
+	}
+}
+
+func (s *Server) handleGetVenue(svc services.VenueService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		venue, err := svc.GetVenue(c.Param("id"))
+		if err != nil {
+			return venueError(err, "fetch venue")
+		}
+
+		return c.JSON(http.StatusOK, venue.ToResponse())
+	}
+}
+
+func (s *Server) handleCreateVenue(svc services.VenueService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get("userID").(string)
+
+		var req CreateVenueRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		venue, err := svc.CreateVenue(&models.Venue{
+			Name:      req.Name,
+			Address:   req.Address,
+			City:      req.City,
+			Country:   req.Country,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+			TimeZone:  req.TimeZone,
+			Capacity:  req.Capacity,
+		}, userID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create venue")
+		}
+
+		return c.JSON(http.StatusCreated, venue.ToResponse())
+	}
+}
+
+func (s *Server) handleUpdateVenue(svc services.VenueService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req UpdateVenueRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		venue, err := svc.UpdateVenue(actorFromContext(c), c.Param("id"), func(venue *models.Venue) {
+			if req.Name != nil {
+				venue.Name = *req.Name
+			}
+			if req.Address != nil {
+				venue.Address = *req.Address
+			}
+			if req.City != nil {
+				venue.City = *req.City
+			}
+			if req.Country != nil {
+				venue.Country = *req.Country
+			}
+			if req.Latitude != nil {
+				venue.Latitude = *req.Latitude
+			}
+			if req.Longitude != nil {
+				venue.Longitude = *req.Longitude
+			}
+			if req.TimeZone != nil {
+				venue.TimeZone = *req.TimeZone
+			}
+			if req.Capacity != nil {
+				venue.Capacity = req.Capacity
+			}
+		})
+		if err != nil {
+			return venueError(err, "update venue")
+		}
+
+		return c.JSON(http.StatusOK, venue.ToResponse())
+	}
+}
+
+func (s *Server) handleDeleteVenue(svc services.VenueService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := svc.DeleteVenue(actorFromContext(c), c.Param("id")); err != nil {
+			return venueError(err, "delete venue")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func venueError(err error, action string) error {
+	switch {
+	case errors.Is(err, services.ErrVenueNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "not authorized to "+action)
+	case errors.Is(err, services.ErrVenueInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"eventmaster-go/internal/models"
+)
+
+// ErrForbidden is returned when the acting user may not perform an operation
+var ErrForbidden = errors.New("not authorized to perform this action")
+
+// Actor identifies the user on whose behalf a service operation runs
+type Actor struct {
+	UserID string
+	// Email lets users act on participant records registered with their address
+	Email   string
+	IsAdmin bool
+}
+
+// CanManage reports whether the actor may manage a resource owned by ownerID
+func (a Actor) CanManage(ownerID string) bool {
+	return a.IsAdmin || (a.UserID != "" && a.UserID == ownerID)
+}
+
+// IsParticipant reports whether the participant was registered with the actor's email
+func (a Actor) IsParticipant(participant *models.Participant) bool {
+	return a.Email != "" && strings.EqualFold(a.Email, participant.Email)
+}
+
+// ownerScope returns the owner filter for listings: admins see everything
+func (a Actor) ownerScope() string {
+	if a.IsAdmin {
+		return ""
+	}
+	return a.UserID
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound = errors.New("agenda session not found")
+	ErrTrackNotFound   = errors.New("agenda track not found")
+	ErrSpeakerNotFound = errors.New("one or more speakers were not found")
+	ErrInvalidSchedule = errors.New("session must end after it starts")
+)
+
+// ErrRoomOverlap is returned when a session would share its room with another session at the same time
+var ErrRoomOverlap = repositories.ErrRoomOverlap
+
+// Agenda is the full programme of an event
+type Agenda struct {
+	Event    *models.Event
+	Tracks   []*models.AgendaTrack
+	Speakers []*models.Speaker
+	Sessions []*models.AgendaSession
+}
+
+// SessionChanges describes an edit to a session. Apply changes the plain fields;
+// a non-nil SpeakerIDs replaces the speakers.
+type SessionChanges struct {
+	Apply      func(session *models.AgendaSession)
+	SpeakerIDs []string
+}
+
+// AgendaService manages the tracks, speakers and sessions of events and the personal agendas of participants
+type AgendaService interface {
+	GetAgenda(eventID string) (*Agenda, error)
+	CreateTrack(actor Actor, eventID string, track *models.AgendaTrack) (*models.AgendaTrack, error)
+	UpdateTrack(actor Actor, eventID, id string, apply func(track *models.AgendaTrack)) (*models.AgendaTrack, error)
+	DeleteTrack(actor Actor, eventID, id string) error
+	CreateSpeaker(actor Actor, eventID string, speaker *models.Speaker) (*models.Speaker, error)
+	UpdateSpeaker(actor Actor, eventID, id string, apply func(speaker *models.Speaker)) (*models.Speaker, error)
+	DeleteSpeaker(actor Actor, eventID, id string) error
+	CreateSession(actor Actor, eventID string, session *models.AgendaSession, speakerIDs []string) (*models.AgendaSession, error)
+	UpdateSession(actor Actor, eventID, id string, changes SessionChanges) (*models.AgendaSession, error)
+	DeleteSession(actor Actor, eventID, id string) error
+	GetPersonalAgenda(actor Actor, participantID string) ([]*models.AgendaSession, error)
+	AddToPersonalAgenda(actor Actor, participantID, sessionID string) ([]*models.AgendaSession, error)
+	RemoveFromPersonalAgenda(actor Actor, participantID, sessionID string) ([]*models.AgendaSession, error)
+}
+
+type agendaService struct {
+	agendaRepo      repositories.AgendaRepository
+	eventRepo       repositories.EventRepository
+	participantRepo repositories.ParticipantRepository
+	imageRepo       repositories.ImageRepository
+	access          EventAccess
+}
+
+// NewAgendaService creates a new agenda service
+func NewAgendaService(
+	agendaRepo repositories.AgendaRepository,
+	eventRepo repositories.EventRepository,
+	participantRepo repositories.ParticipantRepository,
+	imageRepo repositories.ImageRepository,
+	access EventAccess,
+) AgendaService {
+	return &agendaService{
+		agendaRepo:      agendaRepo,
+		eventRepo:       eventRepo,
+		participantRepo: participantRepo,
+		imageRepo:       imageRepo,
+		access:          access,
+	}
+}
+
+// GetAgenda loads the event with its tracks, speakers and sessions in schedule order.
+// Callers decide whether the event is visible to the requester.
+func (s *agendaService) GetAgenda(eventID string) (*Agenda, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	agenda := &Agenda{Event: event}
+	if agenda.Tracks, err = s.agendaRepo.FindTracks(eventID); err != nil {
+		return nil, err
+	}
+	if agenda.Speakers, err = s.agendaRepo.FindSpeakers(eventID); err != nil {
+		return nil, err
+	}
+	if agenda.Sessions, err = s.agendaRepo.FindSessions(eventID); err != nil {
+		return nil, err
+	}
+	return agenda, nil
+}
+
+func (s *agendaService) CreateTrack(actor Actor, eventID string, track *models.AgendaTrack) (*models.AgendaTrack, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+
+	track.EventID = eventID
+	if err := s.agendaRepo.SaveTrack(track); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+func (s *agendaService) UpdateTrack(actor Actor, eventID, id string, apply func(track *models.AgendaTrack)) (*models.AgendaTrack, error) {
+	track, err := s.findTrack(actor, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apply(track)
+	if err := s.agendaRepo.SaveTrack(track); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// DeleteTrack removes a track; its sessions stay on the agenda without one
+func (s *agendaService) DeleteTrack(actor Actor, eventID, id string) error {
+	if _, err := s.findTrack(actor, eventID, id); err != nil {
+		return err
+	}
+	return s.agendaRepo.DeleteTrack(id)
+}
+
+func (s *agendaService) CreateSpeaker(actor Actor, eventID string, speaker *models.Speaker) (*models.Speaker, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	if err := s.checkPhoto(speaker); err != nil {
+		return nil, err
+	}
+
+	speaker.EventID = eventID
+	if err := s.agendaRepo.SaveSpeaker(speaker); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindSpeaker(eventID, speaker.ID)
+}
+
+func (s *agendaService) UpdateSpeaker(actor Actor, eventID, id string, apply func(speaker *models.Speaker)) (*models.Speaker, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	speaker, err := s.agendaRepo.FindSpeaker(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSpeakerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	apply(speaker)
+	if err := s.checkPhoto(speaker); err != nil {
+		return nil, err
+	}
+	if err := s.agendaRepo.SaveSpeaker(speaker); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindSpeaker(eventID, id)
+}
+
+// DeleteSpeaker removes a speaker from the event and every session they presented
+func (s *agendaService) DeleteSpeaker(actor Actor, eventID, id string) error {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return err
+	}
+	if _, err := s.agendaRepo.FindSpeaker(eventID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSpeakerNotFound
+		}
+		return err
+	}
+	return s.agendaRepo.DeleteSpeaker(id)
+}
+
+// CreateSession schedules a session. Sessions in the same room may not overlap;
+// sessions without a room are never in conflict.
+func (s *agendaService) CreateSession(actor Actor, eventID string, session *models.AgendaSession, speakerIDs []string) (*models.AgendaSession, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+
+	session.EventID = eventID
+	speakers, err := s.prepareSession(session, speakerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if speakers == nil {
+		speakers = []models.Speaker{}
+	}
+	if err := s.agendaRepo.SaveSession(session, speakers); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindSession(eventID, session.ID)
+}
+
+func (s *agendaService) UpdateSession(actor Actor, eventID, id string, changes SessionChanges) (*models.AgendaSession, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	session, err := s.findSession(eventID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Apply != nil {
+		changes.Apply(session)
+	}
+	speakers, err := s.prepareSession(session, changes.SpeakerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.agendaRepo.SaveSession(session, speakers); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindSession(eventID, id)
+}
+
+// DeleteSession removes a session, also taking it off participants' personal agendas
+func (s *agendaService) DeleteSession(actor Actor, eventID, id string) error {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return err
+	}
+	if _, err := s.findSession(eventID, id); err != nil {
+		return err
+	}
+	return s.agendaRepo.DeleteSession(id)
+}
+
+// GetPersonalAgenda lists the sessions a participant picked, in schedule order
+func (s *agendaService) GetPersonalAgenda(actor Actor, participantID string) ([]*models.AgendaSession, error) {
+	if _, err := s.findParticipant(actor, participantID, PermissionView); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindPersonalSessions(participantID)
+}
+
+// AddToPersonalAgenda adds a session of the participant's event to their agenda; adding it twice is a no-op
+func (s *agendaService) AddToPersonalAgenda(actor Actor, participantID, sessionID string) ([]*models.AgendaSession, error) {
+	participant, err := s.findParticipant(actor, participantID, PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findSession(participant.EventID, sessionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.agendaRepo.AddPersonalItem(participantID, sessionID); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindPersonalSessions(participantID)
+}
+
+func (s *agendaService) RemoveFromPersonalAgenda(actor Actor, participantID, sessionID string) ([]*models.AgendaSession, error) {
+	if _, err := s.findParticipant(actor, participantID, PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := s.agendaRepo.RemovePersonalItem(participantID, sessionID); err != nil {
+		return nil, err
+	}
+	return s.agendaRepo.FindPersonalSessions(participantID)
+}
+
+// findParticipant loads a participant for the user registered with its email, or for
+// collaborators whose role on the event grants permission
+func (s *agendaService) findParticipant(actor Actor, participantID string, permission Permission) (*models.Participant, error) {
+	participant, err := s.participantRepo.FindByID(participantID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.IsParticipant(participant) {
+		return participant, nil
+	}
+	if _, err := s.access.FindEvent(actor, participant.EventID, permission); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+func (s *agendaService) findManagedEvent(actor Actor, eventID string) (*models.Event, error) {
+	return s.access.FindEvent(actor, eventID, PermissionEdit)
+}
+
+func (s *agendaService) findTrack(actor Actor, eventID, id string) (*models.AgendaTrack, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	track, err := s.agendaRepo.FindTrack(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTrackNotFound
+	}
+	return track, err
+}
+
+func (s *agendaService) findSession(eventID, id string) (*models.AgendaSession, error) {
+	session, err := s.agendaRepo.FindSession(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	return session, err
+}
+
+// prepareSession validates the schedule and track of a session and resolves speakerIDs,
+// returning nil speakers when speakerIDs is nil so the current ones are kept
+func (s *agendaService) prepareSession(session *models.AgendaSession, speakerIDs []string) ([]models.Speaker, error) {
+	session.Room = strings.TrimSpace(session.Room)
+	if !session.EndsAt.After(session.StartsAt) {
+		return nil, ErrInvalidSchedule
+	}
+
+	session.Track = nil
+	if session.TrackID != nil {
+		if _, err := s.agendaRepo.FindTrack(session.EventID, *session.TrackID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTrackNotFound
+			}
+			return nil, err
+		}
+	}
+
+	if speakerIDs == nil {
+		return nil, nil
+	}
+	speakers, err := s.agendaRepo.FindSpeakersByIDs(session.EventID, speakerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(speakers) != len(speakerIDs) {
+		return nil, ErrSpeakerNotFound
+	}
+	return speakers, nil
+}
+
+func (s *agendaService) checkPhoto(speaker *models.Speaker) error {
+	speaker.Photo = nil
+	if speaker.PhotoID == nil {
+		return nil
+	}
+	images, err := s.imageRepo.FindByIDs([]string{*speaker.PhotoID})
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return ErrImageNotFound
+	}
+	return nil
+}
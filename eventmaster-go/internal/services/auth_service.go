@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
 	"eventmaster-go/internal/repositories"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -20,6 +21,7 @@ type AuthService interface {
 	GetUserByID(id string) (*models.User, error)
 	ValidateSession(token string) (*models.User, error)
 	Logout(token string) error
+	ListUsers(q query.Query, page, limit int) ([]*models.User, int64, error)
 }
 
 type authService struct {
@@ -126,3 +128,7 @@ func generateSessionToken() (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+func (s *authService) ListUsers(q query.Query, page, limit int) ([]*models.User, int64, error) {
+	return s.userRepo.FindPaginated(q, page, limit)
+}
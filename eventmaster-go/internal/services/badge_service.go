@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+	"eventmaster-go/pkg/badge"
+)
+
+// BadgeService renders printable PDF tickets for attendees and badge sheets for organizers
+type BadgeService interface {
+	Templates() []badge.Template
+	TicketPDF(code string) ([]byte, error)
+	BadgeSheet(actor Actor, eventID, templateName, ticketTypeID string) ([]byte, error)
+}
+
+type badgeService struct {
+	ticketService   TicketService
+	participantRepo repositories.ParticipantRepository
+	eventRepo       repositories.EventRepository
+	ticketTypeRepo  repositories.TicketTypeRepository
+	signer          *TicketSigner
+	access          EventAccess
+}
+
+// NewBadgeService creates a new badge service; codes on badges are signed with signer
+func NewBadgeService(
+	ticketService TicketService,
+	participantRepo repositories.ParticipantRepository,
+	eventRepo repositories.EventRepository,
+	ticketTypeRepo repositories.TicketTypeRepository,
+	signer *TicketSigner,
+	access EventAccess,
+) BadgeService {
+	return &badgeService{
+		ticketService:   ticketService,
+		participantRepo: participantRepo,
+		eventRepo:       eventRepo,
+		ticketTypeRepo:  ticketTypeRepo,
+		signer:          signer,
+		access:          access,
+	}
+}
+
+func (s *badgeService) Templates() []badge.Template {
+	return badge.Templates()
+}
+
+// TicketPDF renders the ticket of a code as a one-page PDF. Like the ticket itself, it is
+// available to whoever holds the code.
+func (s *badgeService) TicketPDF(code string) ([]byte, error) {
+	ticket, err := s.ticketService.FindTicket(code)
+	if err != nil {
+		return nil, err
+	}
+	event, err := s.eventRepo.FindByID(ticket.Participant.EventID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.ticketTypeNames(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := badge.Lookup(badge.TicketTemplate)
+	if err != nil {
+		return nil, err
+	}
+	sheet := badge.NewSheet(template, event.Title+" - "+ticket.Participant.FullName)
+	if err := sheet.Add(s.card(event, ticket.Participant, names)); err != nil {
+		return nil, err
+	}
+	return writeSheet(sheet)
+}
+
+// BadgeSheet renders a badge for every participant of an event, or only for those holding
+// ticketTypeID when it is set, laid out by the named template
+func (s *badgeService) BadgeSheet(actor Actor, eventID, templateName, ticketTypeID string) ([]byte, error) {
+	template, err := badge.Lookup(templateName)
+	if err != nil {
+		return nil, err
+	}
+	event, err := s.access.FindEvent(actor, eventID, PermissionCheckIn)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.ticketTypeNames(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := names[ticketTypeID]; ticketTypeID != "" && !ok {
+		return nil, ErrTicketTypeNotFound
+	}
+
+	sheet := badge.NewSheet(template, event.Title+" - Badges")
+	err = s.participantRepo.StreamByEventID(eventID, func(participant *models.Participant) error {
+		if ticketTypeID != "" && (participant.TicketTypeID == nil || *participant.TicketTypeID != ticketTypeID) {
+			return nil
+		}
+		return sheet.Add(s.card(event, participant, names))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return writeSheet(sheet)
+}
+
+func (s *badgeService) card(event *models.Event, participant *models.Participant, ticketTypeNames map[string]string) badge.Card {
+	card := badge.Card{
+		EventTitle: event.Title,
+		Date:       event.LocalEventDate(),
+		Location:   event.Location,
+		Name:       participant.FullName,
+		Code:       s.signer.Sign(participant),
+	}
+	if participant.TicketTypeID != nil {
+		card.Label = ticketTypeNames[*participant.TicketTypeID]
+	}
+	return card
+}
+
+func (s *badgeService) ticketTypeNames(eventID string) (map[string]string, error) {
+	ticketTypes, err := s.ticketTypeRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		names[ticketType.ID] = ticketType.Name
+	}
+	return names, nil
+}
+
+func writeSheet(sheet *badge.Sheet) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := sheet.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
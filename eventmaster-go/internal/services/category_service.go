@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+	"eventmaster-go/pkg/slug"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrInvalidName      = errors.New("name must contain letters or digits")
+)
+
+// CategoryService handles the event taxonomy
+type CategoryService interface {
+	GetCategoryTree() ([]*models.CategoryResponse, error)
+	CreateCategory(name string, parentID *string) (*models.Category, error)
+	GetTags() ([]*models.TagResponse, error)
+}
+
+type categoryService struct {
+	categoryRepo repositories.CategoryRepository
+	tagRepo      repositories.TagRepository
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo repositories.CategoryRepository, tagRepo repositories.TagRepository) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+	}
+}
+
+// GetCategoryTree returns root categories with nested children and event counts
+func (s *categoryService) GetCategoryTree() ([]*models.CategoryResponse, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.categoryRepo.CountPublicEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*models.CategoryResponse, len(categories))
+	for _, category := range categories {
+		node := category.ToResponse()
+		count := counts[category.ID]
+		node.EventCount = &count
+		nodes[category.ID] = node
+	}
+
+	// Categories are ordered by slug, so parents are always visited before children
+	roots := make([]*models.CategoryResponse, 0)
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots, nil
+}
+
+func (s *categoryService) CreateCategory(name string, parentID *string) (*models.Category, error) {
+	categorySlug := slug.Make(name)
+	if categorySlug == "" {
+		return nil, ErrInvalidName
+	}
+
+	category := &models.Category{Name: name, Slug: categorySlug}
+	if parentID != nil && *parentID != "" {
+		parent, err := s.categoryRepo.FindByID(*parentID)
+		if err != nil {
+			return nil, ErrCategoryNotFound
+		}
+		category.ParentID = &parent.ID
+		category.Slug = parent.Slug + "/" + categorySlug
+	}
+
+	existing, err := s.categoryRepo.FindBySlug(category.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrCategoryExists
+	}
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *categoryService) GetTags() ([]*models.TagResponse, error) {
+	tags, counts, err := s.tagRepo.FindAllWithCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*models.TagResponse, len(tags))
+	for i, tag := range tags {
+		response := tag.ToResponse()
+		count := counts[tag.ID]
+		response.EventCount = &count
+		responses[i] = response
+	}
+	return responses, nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrInviteNotFound       = errors.New("invitation not found")
+	ErrInviteExpired        = errors.New("invitation has expired")
+	ErrInviteEmailMismatch  = errors.New("invitation was sent to another email address")
+)
+
+// ErrAlreadyCollaborator is returned when inviting or accepting for someone already on the event
+var ErrAlreadyCollaborator = repositories.ErrAlreadyCollaborator
+
+// ErrInviteNotPending is returned when an invitation was accepted or withdrawn in the meantime
+var ErrInviteNotPending = repositories.ErrInviteNotPending
+
+// inviteTTL is how long an invitation email can be accepted
+const inviteTTL = 7 * 24 * time.Hour
+
+// CollaboratorService lets event owners share their events. Owners invite people by email
+// with a role; the email carries a one-time link that the invited user accepts while signed
+// in with the same address.
+type CollaboratorService interface {
+	ListCollaborators(actor Actor, eventID string) ([]*models.Collaborator, error)
+	Invite(actor Actor, eventID, email string, role models.CollaboratorRole) (*models.Collaborator, error)
+	UpdateRole(actor Actor, eventID, id string, role models.CollaboratorRole) (*models.Collaborator, error)
+	RemoveCollaborator(actor Actor, eventID, id string) error
+	AcceptInvite(actor Actor, token string) (*models.Collaborator, error)
+	MyRole(actor Actor, eventID string) (models.CollaboratorRole, error)
+	SharedWithMe(actor Actor) ([]*models.Collaborator, error)
+}
+
+type collaboratorService struct {
+	collaboratorRepo repositories.CollaboratorRepository
+	eventRepo        repositories.EventRepository
+	userRepo         repositories.UserRepository
+	access           EventAccess
+	mailer           Mailer
+	inviteBaseURL    string
+}
+
+// NewCollaboratorService creates a new collaborator service. Invitation links point to
+// inviteBaseURL followed by the token.
+func NewCollaboratorService(
+	collaboratorRepo repositories.CollaboratorRepository,
+	eventRepo repositories.EventRepository,
+	userRepo repositories.UserRepository,
+	access EventAccess,
+	mailer Mailer,
+	inviteBaseURL string,
+) CollaboratorService {
+	return &collaboratorService{
+		collaboratorRepo: collaboratorRepo,
+		eventRepo:        eventRepo,
+		userRepo:         userRepo,
+		access:           access,
+		mailer:           mailer,
+		inviteBaseURL:    inviteBaseURL,
+	}
+}
+
+// ListCollaborators lists the collaborators and pending invitations of an event
+func (s *collaboratorService) ListCollaborators(actor Actor, eventID string) ([]*models.Collaborator, error) {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionView); err != nil {
+		return nil, err
+	}
+	return s.collaboratorRepo.FindByEventID(eventID)
+}
+
+// Invite emails an invitation to join the event with role. Inviting an email again sends a
+// fresh link and invalidates the previous one.
+func (s *collaboratorService) Invite(actor Actor, eventID, email string, role models.CollaboratorRole) (*models.Collaborator, error) {
+	event, err := s.access.FindEvent(actor, eventID, PermissionManage)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := s.userRepo.FindByID(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	creator, err := s.userRepo.FindByID(event.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if creator != nil && strings.EqualFold(creator.Email, strings.TrimSpace(email)) {
+		return nil, ErrAlreadyCollaborator
+	}
+
+	token, tokenHash, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(inviteTTL)
+	collaborator := &models.Collaborator{
+		EventID:         eventID,
+		Email:           email,
+		Role:            role,
+		InvitedBy:       actor.UserID,
+		InviteTokenHash: &tokenHash,
+		InviteExpiresAt: &expiresAt,
+	}
+	if err := s.collaboratorRepo.Invite(collaborator); err != nil {
+		return nil, err
+	}
+
+	subject := fmt.Sprintf("You're invited to help organize %s", event.Title)
+	body := fmt.Sprintf("%s invited you to collaborate on %q as %s.\n\nAccept the invitation: %s%s\n\nThe link expires on %s.\n",
+		inviter.Email, event.Title, roleName(role), s.inviteBaseURL, token, expiresAt.UTC().Format("2 Jan 2006 15:04 MST"))
+	if err := s.mailer.Send(collaborator.Email, subject, body); err != nil {
+		return nil, fmt.Errorf("send invitation: %w", err)
+	}
+	return collaborator, nil
+}
+
+func (s *collaboratorService) UpdateRole(actor Actor, eventID, id string, role models.CollaboratorRole) (*models.Collaborator, error) {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionManage); err != nil {
+		return nil, err
+	}
+	collaborator, err := s.findCollaborator(eventID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.collaboratorRepo.UpdateRole(collaborator, role); err != nil {
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+// RemoveCollaborator removes a collaborator or withdraws an invitation. Owners may remove
+// anyone; collaborators may remove themselves to leave an event.
+func (s *collaboratorService) RemoveCollaborator(actor Actor, eventID, id string) error {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return err
+	}
+	collaborator, err := s.findCollaborator(eventID, id)
+	if err != nil {
+		return err
+	}
+	leaving := collaborator.UserID != nil && *collaborator.UserID == actor.UserID
+	if !leaving {
+		if err := s.access.Authorize(actor, event, PermissionManage); err != nil {
+			return err
+		}
+	}
+	return s.collaboratorRepo.Remove(collaborator.ID)
+}
+
+// AcceptInvite joins the signed-in user to the event of an invitation token. The user's
+// email must be the one the invitation was sent to.
+func (s *collaboratorService) AcceptInvite(actor Actor, token string) (*models.Collaborator, error) {
+	collaborator, err := s.collaboratorRepo.FindByInviteTokenHash(hashInviteToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInviteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if collaborator.InviteExpiresAt != nil && !now.Before(*collaborator.InviteExpiresAt) {
+		return nil, ErrInviteExpired
+	}
+
+	user, err := s.userRepo.FindByID(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, collaborator.Email) {
+		return nil, ErrInviteEmailMismatch
+	}
+	event, err := s.eventRepo.FindByID(collaborator.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.UserID == user.ID {
+		return nil, ErrAlreadyCollaborator
+	}
+
+	if err := s.collaboratorRepo.Accept(collaborator, user.ID, now); err != nil {
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+// MyRole returns the actor's role on an event; it is empty for events they may only see
+// because they are public
+func (s *collaboratorService) MyRole(actor Actor, eventID string) (models.CollaboratorRole, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return "", err
+	}
+	return s.access.Role(actor, event)
+}
+
+// SharedWithMe lists the events other users share with the actor
+func (s *collaboratorService) SharedWithMe(actor Actor) ([]*models.Collaborator, error) {
+	return s.collaboratorRepo.FindActiveByUserID(actor.UserID)
+}
+
+func (s *collaboratorService) findCollaborator(eventID, id string) (*models.Collaborator, error) {
+	collaborator, err := s.collaboratorRepo.FindForEvent(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCollaboratorNotFound
+	}
+	return collaborator, err
+}
+
+// newInviteToken returns a random invitation token and the hash stored in its place
+func newInviteToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashInviteToken(token), nil
+}
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func roleName(role models.CollaboratorRole) string {
+	if role == models.CollaboratorRoleCheckIn {
+		return "check-in staff"
+	}
+	return string(role)
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+	"eventmaster-go/pkg/markdown"
+	"fmt"
+)
+
+// ImportAction describes what happened, or would happen in a dry run, to an imported row
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionError  ImportAction = "error"
+)
+
+// EventImportRow is one parsed row of an import file. Rows carrying Errors were
+// rejected during parsing and are only reported.
+type EventImportRow struct {
+	Row        int
+	ExternalID string
+	Event      *models.Event
+	Errors     []string
+}
+
+// ImportRowResult reports the outcome of a single row
+type ImportRowResult struct {
+	Row        int          `json:"row"`
+	ExternalID string       `json:"externalId,omitempty"`
+	Action     ImportAction `json:"action"`
+	EventID    string       `json:"eventId,omitempty"`
+	Errors     []string     `json:"errors,omitempty"`
+}
+
+// ImportReport summarizes an import run
+type ImportReport struct {
+	DryRun  bool              `json:"dryRun"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// EventImportService loads events in bulk, upserting rows that carry an external key
+type EventImportService interface {
+	ImportEvents(rows []EventImportRow, userID string, dryRun bool) (*ImportReport, error)
+}
+
+type eventImportService struct {
+	eventRepo    repositories.EventRepository
+	eventService EventService
+}
+
+// NewEventImportService creates a new event import service
+func NewEventImportService(eventRepo repositories.EventRepository, eventService EventService) EventImportService {
+	return &eventImportService{
+		eventRepo:    eventRepo,
+		eventService: eventService,
+	}
+}
+
+// ImportEvents creates or updates one event per row on behalf of userID. Rows are
+// processed independently so one bad row does not abort the rest. In a dry run
+// nothing is written and each row reports the action it would take.
+func (s *eventImportService) ImportEvents(rows []EventImportRow, userID string, dryRun bool) (*ImportReport, error) {
+	report := &ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]ImportRowResult, 0, len(rows)),
+	}
+	firstRowByKey := make(map[string]int)
+
+	for _, row := range rows {
+		result := ImportRowResult{Row: row.Row, ExternalID: row.ExternalID, Errors: row.Errors}
+
+		if len(result.Errors) == 0 && row.ExternalID != "" {
+			if first, seen := firstRowByKey[row.ExternalID]; seen {
+				result.Errors = []string{fmt.Sprintf("externalId duplicates row %d", first)}
+			} else {
+				firstRowByKey[row.ExternalID] = row.Row
+			}
+		}
+
+		if len(result.Errors) == 0 {
+			if err := s.importRow(row, userID, dryRun, &result); err != nil {
+				result.Errors = []string{err.Error()}
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			result.Action = ImportActionError
+			result.EventID = ""
+			report.Failed++
+		} else if result.Action == ImportActionCreate {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+func (s *eventImportService) importRow(row EventImportRow, userID string, dryRun bool, result *ImportRowResult) error {
+	// Descriptions are Markdown; HTML exported by other tools is converted on the way in
+	if markdown.ContainsHTML(row.Event.Description) {
+		row.Event.Description = markdown.FromHTML(row.Event.Description)
+	}
+
+	var existing *models.Event
+	if row.ExternalID != "" {
+		found, err := s.eventRepo.FindByExternalID(row.ExternalID)
+		if err != nil {
+			return err
+		}
+		if found != nil && found.UserID != userID {
+			return errors.New("externalId belongs to an event owned by another user")
+		}
+		existing = found
+	}
+
+	if existing == nil {
+		result.Action = ImportActionCreate
+		if dryRun {
+			return s.eventService.CheckReferences(row.Event, userID)
+		}
+
+		row.Event.ExternalID = row.ExternalID
+		created, err := s.eventService.CreateEvent(row.Event, userID, nil)
+		if err != nil {
+			return err
+		}
+		result.EventID = created.ID
+		return nil
+	}
+
+	result.Action = ImportActionUpdate
+	result.EventID = existing.ID
+	if dryRun {
+		// Updates keep the stored title and ignore templates
+		check := *row.Event
+		check.Title = existing.Title
+		check.TemplateID = nil
+		return s.eventService.CheckReferences(&check, userID)
+	}
+
+	// Only the fields the row provides replace the stored values
+	merged := existing.Snapshot()
+	incoming := row.Event.Snapshot()
+	if incoming.Title != "" {
+		merged.Title = incoming.Title
+	}
+	if incoming.Description != "" {
+		merged.Description = incoming.Description
+	}
+	if incoming.Organizer != "" {
+		merged.Organizer = incoming.Organizer
+	}
+	if incoming.EventDate != nil {
+		merged.EventDate = incoming.EventDate
+	}
+	if incoming.TimeZone != "" {
+		merged.TimeZone = incoming.TimeZone
+	}
+	if incoming.Latitude != 0 || incoming.Longitude != 0 {
+		merged.Latitude = incoming.Latitude
+		merged.Longitude = incoming.Longitude
+	}
+	if incoming.Location != "" {
+		merged.Location = incoming.Location
+	}
+	if incoming.VenueID != nil {
+		merged.VenueID = incoming.VenueID
+	}
+	if incoming.EventType != "" {
+		merged.EventType = incoming.EventType
+	}
+	// Categories and tags are replaced only when the row lists some
+	merged.CategoryIDs, merged.Tags = nil, nil
+	if len(row.Event.Categories) > 0 {
+		merged.CategoryIDs = incoming.CategoryIDs
+	}
+	if len(row.Event.Tags) > 0 {
+		merged.Tags = incoming.Tags
+	}
+
+	update := &models.Event{Version: existing.Version}
+	merged.ApplyTo(update)
+	_, err := s.eventService.UpdateEvent(existing.ID, update, userID)
+	return err
+}
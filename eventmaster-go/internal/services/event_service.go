@@ -2,39 +2,122 @@ package services
 
 import (
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
 	"eventmaster-go/internal/repositories"
+	"eventmaster-go/pkg/markdown"
 	"time"
 	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
 )
 
 // EventService handles event-related business logic
 type EventService interface {
 	CreateEvent(event *models.Event, userID string, imageIDs []string) (*models.Event, error)
+	CheckReferences(event *models.Event, userID string) error
+	CloneEvent(id string, userID string, options CloneOptions) (*models.Event, error)
 	GetEventByID(id string) (*models.Event, error)
+	GetEventBySlug(slug string) (*models.Event, error)
 	GetEventsByDateRange(start, end time.Time) ([]*models.Event, error)
 	GetUserEvents(userID string) ([]*models.Event, error)
-	GetPaginatedEvents(page, limit int, sortBy, sortOrder string) ([]*models.Event, int64, error)
-	UpdateEvent(id string, event *models.Event) (*models.Event, error)
-	DeleteEvent(id string) error
+	GetPaginatedEvents(filter repositories.EventFilter, q query.Query, page, limit int) ([]*models.Event, int64, error)
+	UpdateEvent(id string, event *models.Event, userID string) (*models.Event, error)
+	DeleteEvent(id string, expectedVersion int) error
+	PublishEvent(id string, eventDate *time.Time) (*models.Event, error)
+	CancelEvent(id string, reason string) (*models.Event, error)
+	PostponeEvent(id string, reason string) (*models.Event, error)
+	GetRevisions(id string) ([]*models.EventRevision, error)
+	GetRevision(id string, number int) (*models.EventRevision, error)
+	RevertToRevision(id string, number int, userID string) (*models.Event, error)
+	MigrateHTMLDescriptions() (int64, error)
+	MigrateSlugs() (int64, error)
 }
 
 type eventService struct {
-	eventRepo repositories.EventRepository
-	imageRepo repositories.ImageRepository
+	eventRepo    repositories.EventRepository
+	imageRepo    repositories.ImageRepository
+	categoryRepo repositories.CategoryRepository
+	tagRepo      repositories.TagRepository
+	revisionRepo repositories.EventRevisionRepository
+	templateRepo repositories.EventTemplateRepository
+	venueRepo    repositories.VenueRepository
+	organizerRepo repositories.OrganizerRepository
+}
+
+// CloneOptions adjusts the copy made by CloneEvent. EventDate replaces the start outright;
+// otherwise ShiftDays moves it by whole days on the event's local calendar.
+type CloneOptions struct {
+	Title     string
+	EventDate *time.Time
+	ShiftDays int
 }
 
 // NewEventService creates a new event service
-func NewEventService(eventRepo repositories.EventRepository, imageRepo repositories.ImageRepository) EventService {
+func NewEventService(
+	eventRepo repositories.EventRepository,
+	imageRepo repositories.ImageRepository,
+	categoryRepo repositories.CategoryRepository,
+	tagRepo repositories.TagRepository,
+	revisionRepo repositories.EventRevisionRepository,
+	templateRepo repositories.EventTemplateRepository,
+	venueRepo repositories.VenueRepository,
+	organizerRepo repositories.OrganizerRepository,
+) EventService {
 	return &eventService{
-		eventRepo: eventRepo,
-		imageRepo: imageRepo,
+		eventRepo:    eventRepo,
+		imageRepo:    imageRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		revisionRepo: revisionRepo,
+		templateRepo: templateRepo,
+		venueRepo:    venueRepo,
+		organizerRepo: organizerRepo,
 	}
 }
 
 var ErrImageNotFound = errors.New("one or more images were not found")
 
+// ErrTitleRequired is returned when neither the request nor its template provides a title
+var ErrTitleRequired = errors.New("title is required")
+
+// ErrVersionConflict is returned when an event changed after the version the caller based its edit on
+var ErrVersionConflict = repositories.ErrVersionConflict
+
+// ErrInvalidStatusTransition is returned when an event cannot move to the requested state
+var ErrInvalidStatusTransition = errors.New("invalid event status transition")
+
+// CreateEvent stores a new event owned by userID. When event.TemplateID is set, fields left
+// empty are filled from that template, including its images if imageIDs is empty.
 func (s *eventService) CreateEvent(event *models.Event, userID string, imageIDs []string) (*models.Event, error) {
 	event.UserID = userID
+	if event.TemplateID != nil {
+		template, err := s.templateRepo.FindByID(*event.TemplateID)
+		if err != nil || template.UserID != userID {
+			return nil, ErrTemplateNotFound
+		}
+		template.ApplyTo(event)
+		if len(imageIDs) == 0 {
+			imageIDs = template.ImageIDs
+		}
+	}
+	if event.Title == "" {
+		return nil, ErrTitleRequired
+	}
+	if err := s.linkVenue(event, models.EventSnapshot{}); err != nil {
+		return nil, err
+	}
+	if err := s.linkOrganizer(event, models.EventSnapshot{}, userID); err != nil {
+		return nil, err
+	}
+	if event.Status == "" {
+		// Clients that predate the lifecycle send no status and expect the event to go live
+		event.Status = models.EventStatusPublished
+	}
+	if event.Status == models.EventStatusPublished {
+		now := time.Now()
+		event.PublishedAt = &now
+	}
 	
 	var images []*models.Image
 	var err error
@@ -48,6 +131,10 @@ func (s *eventService) CreateEvent(event *models.Event, userID string, imageIDs
 		}
 	}
 
+	if err := s.resolveTaxonomy(event); err != nil {
+		return nil, err
+	}
+
 	if err := s.eventRepo.Create(event); err != nil {
 		return nil, err
 	}
@@ -58,14 +145,105 @@ func (s *eventService) CreateEvent(event *models.Event, userID string, imageIDs
 		}
 	}
 
+	// The first revision records the initial values so later edits can be reverted to it
+	initial := &models.EventRevision{
+		EventID:  event.ID,
+		UserID:   userID,
+		Changes:  models.EventSnapshot{}.Diff(event.Snapshot()),
+		Snapshot: event.Snapshot(),
+	}
+	if err := s.revisionRepo.Append(initial); err != nil {
+		return nil, err
+	}
+
 	// Return the created event with related data
 	return s.eventRepo.FindWithImages(event.ID)
 }
 
+// CloneEvent copies the fields, images, categories and tags of an event into a new draft owned by userID
+func (s *eventService) CloneEvent(id string, userID string, options CloneOptions) (*models.Event, error) {
+	source, err := s.eventRepo.FindWithImages(id)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &models.Event{
+		Title:       source.Title,
+		Description: source.Description,
+		Organizer:   source.Organizer,
+		EventDate:   source.EventDate,
+		TimeZone:    source.TimeZone,
+		Latitude:    source.Latitude,
+		Longitude:   source.Longitude,
+		Location:    source.Location,
+		VenueID:     source.VenueID,
+		EventType:   source.EventType,
+		Categories:  source.Categories,
+		Tags:        source.Tags,
+		Status:      models.EventStatusDraft,
+	}
+	if source.OrganizerID != nil {
+		// The clone keeps the profile only for users who manage it; others get one by name
+		if managed, err := s.organizerRepo.IsManager(*source.OrganizerID, userID); err == nil && managed {
+			clone.OrganizerID = source.OrganizerID
+		}
+	}
+	if options.Title != "" {
+		clone.Title = options.Title
+	}
+	if options.EventDate != nil {
+		clone.EventDate = options.EventDate
+	} else if options.ShiftDays != 0 && source.EventDate != nil {
+		// Shift on the local calendar so the wall-clock start survives DST changes
+		shifted := source.LocalEventDate().AddDate(0, 0, options.ShiftDays)
+		clone.EventDate = &shifted
+	}
+
+	imageIDs := make([]string, len(source.Images))
+	for i, image := range source.Images {
+		imageIDs[i] = image.ID
+	}
+
+	return s.CreateEvent(clone, userID, imageIDs)
+}
+
+// CheckReferences returns the error CreateEvent would return for the template, venue and
+// categories the event refers to, without writing anything
+func (s *eventService) CheckReferences(event *models.Event, userID string) error {
+	title := event.Title
+	if event.TemplateID != nil {
+		template, err := s.templateRepo.FindByID(*event.TemplateID)
+		if err != nil || template.UserID != userID {
+			return ErrTemplateNotFound
+		}
+		if title == "" {
+			title = template.Title
+		}
+	}
+	if title == "" {
+		return ErrTitleRequired
+	}
+	if event.VenueID != nil {
+		if _, err := s.venueRepo.FindByID(*event.VenueID); errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrVenueNotFound
+		} else if err != nil {
+			return err
+		}
+	}
+	_, err := s.findCategories(event.Categories)
+	return err
+}
+
 func (s *eventService) GetEventByID(id string) (*models.Event, error) {
 	return s.eventRepo.FindWithImages(id)
 }
 
+// GetEventBySlug finds an event by its slug. An event found by a slug it had before its
+// title changed carries its current slug, which differs from the one asked for.
+func (s *eventService) GetEventBySlug(slug string) (*models.Event, error) {
+	return s.eventRepo.FindBySlug(slug)
+}
+
 func (s *eventService) GetEventsByDateRange(start, end time.Time) ([]*models.Event, error) {
 	return s.eventRepo.FindByDateRange(start, end)
 }
@@ -74,31 +252,307 @@ func (s *eventService) GetUserEvents(userID string) ([]*models.Event, error) {
 	return s.eventRepo.FindByUserID(userID)
 }
 
-func (s *eventService) GetPaginatedEvents(page, limit int, sortBy, sortOrder string) ([]*models.Event, int64, error) {
-	return s.eventRepo.FindPaginated(page, limit, sortBy, sortOrder)
+func (s *eventService) GetPaginatedEvents(filter repositories.EventFilter, q query.Query, page, limit int) ([]*models.Event, int64, error) {
+	return s.eventRepo.FindPaginated(filter, q, page, limit)
+}
+
+// UpdateEvent applies the editable fields of event and records the change as a revision by userID.
+// Categories and tags are replaced unless left nil. A non-zero event.Version is the version the
+// edit was based on; ErrVersionConflict is returned if the stored event has moved on since.
+func (s *eventService) UpdateEvent(id string, event *models.Event, userID string) (*models.Event, error) {
+	snapshot := event.Snapshot()
+	if event.Categories == nil {
+		snapshot.CategoryIDs = nil
+	}
+	if event.Tags == nil {
+		snapshot.Tags = nil
+	}
+	return s.applySnapshot(id, snapshot, event.Version, userID, nil)
+}
+
+func (s *eventService) GetRevisions(id string) ([]*models.EventRevision, error) {
+	return s.revisionRepo.FindByEventID(id)
+}
+
+func (s *eventService) GetRevision(id string, number int) (*models.EventRevision, error) {
+	return s.revisionRepo.FindByNumber(id, number)
+}
+
+// RevertToRevision restores the event fields to the state recorded by revision number,
+// recording the rollback as a new revision
+func (s *eventService) RevertToRevision(id string, number int, userID string) (*models.Event, error) {
+	revision, err := s.revisionRepo.FindByNumber(id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applySnapshot(id, revision.Snapshot, 0, userID, &revision.Number)
+}
+
+// applySnapshot writes snapshot onto the stored event and appends a revision when anything changed
+func (s *eventService) applySnapshot(id string, snapshot models.EventSnapshot, expectedVersion int, userID string, revertedFrom *int) (*models.Event, error) {
+	// The taxonomy is loaded so the snapshots compare categories and tags
+	existingEvent, err := s.eventRepo.FindWithImages(id)
+	if err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && existingEvent.Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
+
+	before := existingEvent.Snapshot()
+	snapshot.ApplyTo(existingEvent)
+	// A reverted snapshot already holds the location that went with its venue
+	if revertedFrom == nil && !sameID(before.VenueID, existingEvent.VenueID) {
+		if err := s.linkVenue(existingEvent, before); err != nil {
+			return nil, err
+		}
+	}
+	if revertedFrom == nil {
+		if err := s.linkOrganizer(existingEvent, before, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.resolveTaxonomy(existingEvent); err != nil {
+		return nil, err
+	}
+	after := existingEvent.Snapshot()
+
+	changes := before.Diff(after)
+	if len(changes) == 0 {
+		return s.eventRepo.FindWithImages(id)
+	}
+
+	revision := &models.EventRevision{
+		EventID:      id,
+		UserID:       userID,
+		Changes:      changes,
+		Snapshot:     after,
+		RevertedFrom: revertedFrom,
+	}
+	if err := s.eventRepo.UpdateWithRevision(existingEvent, revision); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.FindWithImages(id)
+}
+
+// linkVenue copies the location, coordinates and time zone of the event's venue onto
+// every one of those fields the caller left as it was in previous, so explicit values win
+func (s *eventService) linkVenue(event *models.Event, previous models.EventSnapshot) error {
+	if event.VenueID == nil {
+		return nil
+	}
+
+	venue, err := s.venueRepo.FindByID(*event.VenueID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrVenueNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if event.Location == previous.Location {
+		event.Location = venue.DisplayName()
+	}
+	if event.Latitude == previous.Latitude && event.Longitude == previous.Longitude &&
+		(venue.Latitude != 0 || venue.Longitude != 0) {
+		event.Latitude = venue.Latitude
+		event.Longitude = venue.Longitude
+	}
+	if event.TimeZone == previous.TimeZone && venue.TimeZone != "" {
+		event.TimeZone = venue.TimeZone
+	}
+	return nil
 }
 
-func (s *eventService) UpdateEvent(id string, event *models.Event) (*models.Event, error) {
-	existingEvent, err := s.eventRepo.FindByID(id)
+// linkOrganizer keeps the event attached to an organizer profile. A newly chosen profile must
+// be one userID manages and supplies the organizer name unless the caller set one; a changed
+// organizer name without a chosen profile links the profile of that name the event's owner
+// manages, creating it on first use. Unlinking the profile keeps the name.
+func (s *eventService) linkOrganizer(event *models.Event, previous models.EventSnapshot, userID string) error {
+	if event.OrganizerID != nil && !sameID(event.OrganizerID, previous.OrganizerID) {
+		managed, err := s.organizerRepo.IsManager(*event.OrganizerID, userID)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			return ErrOrganizerNotFound
+		}
+		profile, err := s.organizerRepo.FindByID(*event.OrganizerID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrganizerNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if event.Organizer == previous.Organizer {
+			event.Organizer = profile.Name
+		}
+		return nil
+	}
+
+	if event.Organizer == "" || event.Organizer == previous.Organizer {
+		return nil
+	}
+	profile, err := s.organizerRepo.FindOrCreateManaged(event.Organizer, event.UserID)
+	if err != nil {
+		return err
+	}
+	event.OrganizerID = &profile.ID
+	return nil
+}
+
+func sameID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// DeleteEvent moves the event and its participants to the trash. A non-zero
+// expectedVersion makes the delete fail with ErrVersionConflict if the event changed.
+func (s *eventService) DeleteEvent(id string, expectedVersion int) error {
+	return s.eventRepo.SoftDeleteWithParticipants(id, expectedVersion)
+}
+
+func (s *eventService) PublishEvent(id string, eventDate *time.Time) (*models.Event, error) {
+	return s.transitionEvent(id, models.EventStatusPublished, func(event *models.Event) {
+		now := time.Now()
+		event.PublishedAt = &now
+		event.StatusReason = ""
+		if eventDate != nil {
+			event.EventDate = eventDate
+		}
+	})
+}
+
+func (s *eventService) CancelEvent(id string, reason string) (*models.Event, error) {
+	return s.transitionEvent(id, models.EventStatusCancelled, func(event *models.Event) {
+		now := time.Now()
+		event.CancelledAt = &now
+		event.StatusReason = reason
+	})
+}
+
+func (s *eventService) PostponeEvent(id string, reason string) (*models.Event, error) {
+	return s.transitionEvent(id, models.EventStatusPostponed, func(event *models.Event) {
+		event.StatusReason = reason
+	})
+}
+
+// transitionEvent moves an event to next if the lifecycle allows it and applies any side effects
+func (s *eventService) transitionEvent(id string, next models.EventStatus, apply func(event *models.Event)) (*models.Event, error) {
+	event, err := s.eventRepo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update fields
-	existingEvent.Title = event.Title
-	existingEvent.Description = event.Description
-	existingEvent.Organizer = event.Organizer
-	existingEvent.EventDate = event.EventDate
-	existingEvent.Latitude = event.Latitude
-	existingEvent.Longitude = event.Longitude
+	if !event.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, event.Status, next)
+	}
 
-	if err := s.eventRepo.Update(existingEvent); err != nil {
+	event.Status = next
+	apply(event)
+
+	if err := s.eventRepo.Update(event); err != nil {
 		return nil, err
 	}
 
 	return s.eventRepo.FindWithImages(id)
 }
 
-func (s *eventService) DeleteEvent(id string) error {
-	return s.eventRepo.Delete(id)
+// resolveTaxonomy swaps the category and tag stubs carried by an event for stored rows,
+// so callers can pass categories by ID and tags by name
+func (s *eventService) resolveTaxonomy(event *models.Event) error {
+	if len(event.Categories) > 0 {
+		categories, err := s.findCategories(event.Categories)
+		if err != nil {
+			return err
+		}
+
+		event.Categories = make([]models.Category, len(categories))
+		for i, category := range categories {
+			event.Categories[i] = *category
+		}
+	}
+
+	if len(event.Tags) > 0 {
+		names := make([]string, len(event.Tags))
+		for i, tag := range event.Tags {
+			names[i] = tag.Name
+		}
+
+		tags, err := s.tagRepo.FindOrCreateByNames(names)
+		if err != nil {
+			return err
+		}
+
+		event.Tags = make([]models.Tag, len(tags))
+		for i, tag := range tags {
+			event.Tags[i] = *tag
+		}
+	}
+
+	return nil
+}
+
+// findCategories loads the stored categories matching the given ones by ID
+func (s *eventService) findCategories(categories []models.Category) ([]*models.Category, error) {
+	if len(categories) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+
+	found, err := s.categoryRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != len(ids) {
+		return nil, ErrCategoryNotFound
+	}
+	return found, nil
+}
+
+// htmlDescriptionMigration names the one-time conversion of imported descriptions
+const htmlDescriptionMigration = "imported-html-descriptions"
+
+// MigrateHTMLDescriptions rewrites the descriptions of events imported before descriptions
+// were defined as Markdown, which mix Markdown with tags such as <br />, into plain
+// Markdown. It runs once; later calls return 0. It returns the number of events rewritten.
+func (s *eventService) MigrateHTMLDescriptions() (int64, error) {
+	return s.eventRepo.MigrateImportedDescriptions(htmlDescriptionMigration, func(description string) string {
+		if !markdown.ContainsHTML(description) {
+			return description
+		}
+		return markdown.FromHTML(description)
+	})
+}
+
+// slugMigrationBatch is how many events MigrateSlugs loads at a time
+const slugMigrationBatch = 500
+
+// MigrateSlugs gives every event created before events had slugs one made from its title,
+// oldest first so earlier events get the unsuffixed slugs. It is safe to run repeatedly and
+// returns the number of events given a slug.
+func (s *eventService) MigrateSlugs() (int64, error) {
+	var migrated int64
+	for {
+		events, err := s.eventRepo.FindWithoutSlug(slugMigrationBatch)
+		if err != nil {
+			return migrated, err
+		}
+		for _, event := range events {
+			if err := s.eventRepo.AssignSlug(event); err != nil {
+				return migrated, err
+			}
+			migrated++
+		}
+		if len(events) < slugMigrationBatch {
+			return migrated, nil
+		}
+	}
 }
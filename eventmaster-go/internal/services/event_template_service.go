@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrTemplateNotFound is returned when a template does not exist or belongs to another user
+var ErrTemplateNotFound = errors.New("event template not found")
+
+// EventTemplateService manages the saved event templates of a user
+type EventTemplateService interface {
+	ListTemplates(userID string) ([]*models.EventTemplate, error)
+	GetTemplate(id string, userID string) (*models.EventTemplate, error)
+	CreateTemplate(template *models.EventTemplate, userID string) (*models.EventTemplate, error)
+	CreateTemplateFromEvent(actor Actor, eventID string, name string) (*models.EventTemplate, error)
+	DeleteTemplate(id string, userID string) error
+}
+
+type eventTemplateService struct {
+	templateRepo repositories.EventTemplateRepository
+	eventRepo    repositories.EventRepository
+	categoryRepo repositories.CategoryRepository
+	imageRepo    repositories.ImageRepository
+	venueRepo    repositories.VenueRepository
+	access       EventAccess
+}
+
+// NewEventTemplateService creates a new event template service
+func NewEventTemplateService(
+	templateRepo repositories.EventTemplateRepository,
+	eventRepo repositories.EventRepository,
+	categoryRepo repositories.CategoryRepository,
+	imageRepo repositories.ImageRepository,
+	venueRepo repositories.VenueRepository,
+	access EventAccess,
+) EventTemplateService {
+	return &eventTemplateService{
+		templateRepo: templateRepo,
+		eventRepo:    eventRepo,
+		categoryRepo: categoryRepo,
+		imageRepo:    imageRepo,
+		venueRepo:    venueRepo,
+		access:       access,
+	}
+}
+
+func (s *eventTemplateService) ListTemplates(userID string) ([]*models.EventTemplate, error) {
+	return s.templateRepo.FindByUserID(userID)
+}
+
+func (s *eventTemplateService) GetTemplate(id string, userID string) (*models.EventTemplate, error) {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil || template.UserID != userID {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// CreateTemplate stores a template owned by userID after checking its category, image and venue references
+func (s *eventTemplateService) CreateTemplate(template *models.EventTemplate, userID string) (*models.EventTemplate, error) {
+	if len(template.CategoryIDs) > 0 {
+		categories, err := s.categoryRepo.FindByIDs(template.CategoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(categories) != len(template.CategoryIDs) {
+			return nil, ErrCategoryNotFound
+		}
+	}
+
+	if len(template.ImageIDs) > 0 {
+		images, err := s.imageRepo.FindByIDs(template.ImageIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(images) != len(template.ImageIDs) {
+			return nil, ErrImageNotFound
+		}
+	}
+
+	if template.VenueID != nil {
+		if _, err := s.venueRepo.FindByID(*template.VenueID); errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVenueNotFound
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	template.UserID = userID
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// CreateTemplateFromEvent saves the settings of an existing event as a template named name,
+// owned by the actor
+func (s *eventTemplateService) CreateTemplateFromEvent(actor Actor, eventID string, name string) (*models.EventTemplate, error) {
+	event, err := s.eventRepo.FindWithImages(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.Status.IsPublic() {
+		// Drafts the actor may not view are treated as missing
+		if err := s.access.Authorize(actor, event, PermissionView); errors.Is(err, ErrForbidden) {
+			return nil, gorm.ErrRecordNotFound
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if name == "" {
+		name = event.Title
+	}
+
+	template := models.TemplateFromEvent(event, name)
+	template.UserID = actor.UserID
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *eventTemplateService) DeleteTemplate(id string, userID string) error {
+	if _, err := s.GetTemplate(id, userID); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(id)
+}
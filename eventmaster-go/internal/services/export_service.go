@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
+	"eventmaster-go/internal/repositories"
+	"eventmaster-go/pkg/tabular"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrExportNotFound = errors.New("export not found")
+	ErrExportNotReady = errors.New("export has not completed")
+)
+
+// maxConcurrentExports bounds how many background export jobs write at once
+const maxConcurrentExports = 2
+
+var eventExportColumns = []string{
+	"id", "title", "description", "organizer", "eventDate", "eventDateLocal", "timeZone",
+	"status", "latitude", "longitude", "location", "venueId", "eventType", "categories", "tags",
+	"externalId", "externalUrl", "createdAt", "updatedAt",
+}
+
+var participantExportColumns = []string{
+	"id", "fullName", "email", "dateOfBirth", "sourceOfDiscovery", "registeredAt",
+}
+
+// ExportService streams events and participants as CSV, NDJSON or XLSX, either
+// directly to a writer or as background jobs whose files are downloaded later
+type ExportService interface {
+	ExportEvents(w io.Writer, format tabular.Format, filter repositories.EventFilter, q query.Query) (int, error)
+	// FindExportableEvent returns the event if the actor may export its participants
+	FindExportableEvent(actor Actor, eventID string) (*models.Event, error)
+	ExportParticipants(w io.Writer, format tabular.Format, event *models.Event) (int, error)
+	StartEventsExport(actor Actor, format tabular.Format, filter repositories.EventFilter, q query.Query) (*models.ExportJob, error)
+	StartParticipantsExport(actor Actor, format tabular.Format, eventID string) (*models.ExportJob, error)
+	GetExportJob(actor Actor, id string) (*models.ExportJob, error)
+	// OpenExportFile opens the file of a completed job; the caller must close it
+	OpenExportFile(actor Actor, id string) (*models.ExportJob, *os.File, error)
+	PurgeExpired(before time.Time) (int, error)
+	StartCleanupJob(ctx context.Context, interval time.Duration)
+}
+
+type exportService struct {
+	eventRepo       repositories.EventRepository
+	participantRepo repositories.ParticipantRepository
+	jobRepo         repositories.ExportJobRepository
+	dir             string
+	retention       time.Duration
+	slots           chan struct{}
+	access          EventAccess
+}
+
+// NewExportService creates a new export service writing job files to dir and keeping them for retention
+func NewExportService(
+	eventRepo repositories.EventRepository,
+	participantRepo repositories.ParticipantRepository,
+	jobRepo repositories.ExportJobRepository,
+	dir string,
+	retention time.Duration,
+	access EventAccess,
+) ExportService {
+	return &exportService{
+		eventRepo:       eventRepo,
+		participantRepo: participantRepo,
+		jobRepo:         jobRepo,
+		dir:             dir,
+		retention:       retention,
+		slots:           make(chan struct{}, maxConcurrentExports),
+		access:          access,
+	}
+}
+
+func (s *exportService) ExportEvents(w io.Writer, format tabular.Format, filter repositories.EventFilter, q query.Query) (int, error) {
+	writer, err := tabular.NewWriter(format, w, "Events")
+	if err != nil {
+		return 0, err
+	}
+	if err := writer.WriteHeader(eventExportColumns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = s.eventRepo.StreamFiltered(filter, q, func(row *repositories.EventExportRow) error {
+		count++
+		return writer.WriteRow(eventExportValues(row))
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
+
+func (s *exportService) FindExportableEvent(actor Actor, eventID string) (*models.Event, error) {
+	return s.access.FindEvent(actor, eventID, PermissionView)
+}
+
+func (s *exportService) ExportParticipants(w io.Writer, format tabular.Format, event *models.Event) (int, error) {
+	writer, err := tabular.NewWriter(format, w, event.Title)
+	if err != nil {
+		return 0, err
+	}
+	if err := writer.WriteHeader(participantExportColumns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = s.participantRepo.StreamByEventID(event.ID, func(p *models.Participant) error {
+		count++
+		var dateOfBirth string
+		if p.DateOfBirth != nil {
+			dateOfBirth = p.DateOfBirth.Format("2006-01-02")
+		}
+		return writer.WriteRow([]interface{}{
+			p.ID, p.FullName, p.Email, dateOfBirth, string(p.SourceOfDiscovery), p.CreatedAt.UTC(),
+		})
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
+
+func (s *exportService) StartEventsExport(actor Actor, format tabular.Format, filter repositories.EventFilter, q query.Query) (*models.ExportJob, error) {
+	job := &models.ExportJob{
+		UserID: actor.UserID,
+		Kind:   models.ExportKindEvents,
+		Format: string(format),
+		Status: models.ExportStatusPending,
+	}
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	s.run(job, func(w io.Writer) (int, error) {
+		return s.ExportEvents(w, format, filter, q)
+	})
+	return job, nil
+}
+
+func (s *exportService) StartParticipantsExport(actor Actor, format tabular.Format, eventID string) (*models.ExportJob, error) {
+	event, err := s.FindExportableEvent(actor, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.ExportJob{
+		UserID:  actor.UserID,
+		Kind:    models.ExportKindParticipants,
+		Format:  string(format),
+		EventID: &event.ID,
+		Status:  models.ExportStatusPending,
+	}
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	s.run(job, func(w io.Writer) (int, error) {
+		return s.ExportParticipants(w, format, event)
+	})
+	return job, nil
+}
+
+// run writes the job file in the background, waiting for a free slot first. The
+// file is written under a temporary name and renamed once complete so a download
+// never sees a partial export. The goroutine works on its own copy of the job so
+// callers can keep using theirs.
+func (s *exportService) run(job *models.ExportJob, export func(w io.Writer) (int, error)) {
+	go func(record models.ExportJob) {
+		job := &record
+		s.slots <- struct{}{}
+		defer func() { <-s.slots }()
+
+		job.Status = models.ExportStatusRunning
+		if err := s.jobRepo.Update(job); err != nil {
+			log.Printf("Export job %s: failed to mark running: %v", job.ID, err)
+		}
+
+		count, path, err := s.writeFile(job, export)
+		now := time.Now()
+		job.CompletedAt = &now
+		job.RowCount = count
+		if err != nil {
+			log.Printf("Export job %s failed: %v", job.ID, err)
+			job.Status = models.ExportStatusFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = models.ExportStatusCompleted
+			job.FilePath = path
+		}
+		expiresAt := now.Add(s.retention)
+		job.ExpiresAt = &expiresAt
+
+		if err := s.jobRepo.Update(job); err != nil {
+			log.Printf("Export job %s: failed to save result: %v", job.ID, err)
+		}
+	}(*job)
+}
+
+func (s *exportService) writeFile(job *models.ExportJob, export func(w io.Writer) (int, error)) (int, string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return 0, "", err
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("%s.%s", job.ID, job.Format))
+	file, err := os.CreateTemp(s.dir, job.ID+"-*.tmp")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(file.Name())
+
+	count, err := export(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return count, "", err
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return count, "", err
+	}
+	return count, path, nil
+}
+
+func (s *exportService) GetExportJob(actor Actor, id string) (*models.ExportJob, error) {
+	job, err := s.jobRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Jobs are private to whoever started them; hide their existence from others
+	if !actor.CanManage(job.UserID) {
+		return nil, ErrExportNotFound
+	}
+	return job, nil
+}
+
+func (s *exportService) OpenExportFile(actor Actor, id string) (*models.ExportJob, *os.File, error) {
+	job, err := s.GetExportJob(actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != models.ExportStatusCompleted {
+		return job, nil, ErrExportNotReady
+	}
+
+	file, err := os.Open(job.FilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return job, nil, ErrExportNotFound
+	}
+	if err != nil {
+		return job, nil, err
+	}
+	return job, file, nil
+}
+
+// PurgeExpired deletes export files and their jobs once they have expired
+func (s *exportService) PurgeExpired(before time.Time) (int, error) {
+	jobs, err := s.jobRepo.FindExpired(before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, job := range jobs {
+		if job.FilePath != "" {
+			if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("Export cleanup failed: job=%s err=%v", job.ID, err)
+				continue
+			}
+		}
+		if err := s.jobRepo.Purge(job.ID); err != nil {
+			log.Printf("Export cleanup failed: job=%s err=%v", job.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// StartCleanupJob removes expired exports on the provided interval until the context is cancelled.
+func (s *exportService) StartCleanupJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := s.PurgeExpired(time.Now())
+				if err != nil {
+					log.Printf("Export cleanup error: %v", err)
+					continue
+				}
+				if purged > 0 {
+					log.Printf("Export cleanup completed: jobs=%d", purged)
+				}
+			}
+		}
+	}()
+}
+
+func eventExportValues(row *repositories.EventExportRow) []interface{} {
+	e := &row.Event
+
+	var eventDate, eventDateLocal interface{}
+	if e.EventDate != nil {
+		eventDate = e.EventDate.UTC()
+		eventDateLocal = e.LocalEventDate().Format(time.RFC3339)
+	}
+
+	var venueID interface{}
+	if e.VenueID != nil {
+		venueID = *e.VenueID
+	}
+
+	return []interface{}{
+		e.ID, e.Title, e.Description, e.Organizer, eventDate, eventDateLocal, e.TimeZoneName(),
+		string(e.Status), e.Latitude, e.Longitude, e.Location, venueID, e.EventType, row.CategoryNames, row.TagNames,
+		e.ExternalID, e.ExternalURL, e.CreatedAt.UTC(), e.UpdatedAt.UTC(),
+	}
+}
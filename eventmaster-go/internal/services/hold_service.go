@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrHoldNotFound is returned when a hold does not exist or belongs to another event
+var ErrHoldNotFound = errors.New("hold not found")
+
+// ErrHoldNotActive is returned when a hold has expired or was already converted or released
+var ErrHoldNotActive = repositories.ErrHoldNotActive
+
+// HoldService keeps tickets aside while users register or pay. Holds on free tickets are
+// placed explicitly; paid tickets are held by their pending order. Both are released by the
+// reaper once they are older than the hold TTL.
+type HoldService interface {
+	PlaceHold(eventID, ticketTypeID, email string) (*models.Hold, error)
+	GetHold(id string) (*models.Hold, error)
+	ReleaseHold(id string) error
+	ReleaseExpired(now time.Time) (holds int64, orders int, err error)
+	StartReaper(ctx context.Context, interval time.Duration)
+}
+
+type holdService struct {
+	holdRepo       repositories.HoldRepository
+	eventRepo      repositories.EventRepository
+	ticketTypeRepo repositories.TicketTypeRepository
+	orderRepo      repositories.OrderRepository
+	ttl            time.Duration
+}
+
+// NewHoldService creates a new hold service; holds and pending orders keep their tickets for ttl
+func NewHoldService(
+	holdRepo repositories.HoldRepository,
+	eventRepo repositories.EventRepository,
+	ticketTypeRepo repositories.TicketTypeRepository,
+	orderRepo repositories.OrderRepository,
+	ttl time.Duration,
+) HoldService {
+	return &holdService{
+		holdRepo:       holdRepo,
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		orderRepo:      orderRepo,
+		ttl:            ttl,
+	}
+}
+
+// PlaceHold reserves a free ticket for email until the hold expires
+func (s *holdService) PlaceHold(eventID, ticketTypeID, email string) (*models.Hold, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != models.EventStatusPublished {
+		return nil, ErrEventNotOpen
+	}
+
+	ticketType, err := s.ticketTypeRepo.FindForEvent(eventID, ticketTypeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTicketTypeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := checkFreeTicketOnSale(ticketType, now); err != nil {
+		return nil, err
+	}
+
+	hold := &models.Hold{
+		EventID:      eventID,
+		TicketTypeID: ticketTypeID,
+		Email:        email,
+		ExpiresAt:    now.Add(s.ttl),
+	}
+	if err := s.holdRepo.Place(hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// GetHold returns a hold. Hold IDs are unguessable, so whoever placed one can follow it
+// without an account.
+func (s *holdService) GetHold(id string) (*models.Hold, error) {
+	hold, err := s.holdRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrHoldNotFound
+	}
+	return hold, err
+}
+
+// ReleaseHold gives the ticket of an abandoned registration back straight away
+func (s *holdService) ReleaseHold(id string) error {
+	hold, err := s.GetHold(id)
+	if err != nil {
+		return err
+	}
+	return s.holdRepo.Release(hold)
+}
+
+// ReleaseExpired expires the holds past their expiry and fails the orders left pending for
+// longer than the TTL, putting their tickets back on sale. A payment the provider confirms
+// for such an order later is refunded by the webhook.
+func (s *holdService) ReleaseExpired(now time.Time) (int64, int, error) {
+	holds, err := s.holdRepo.ExpireBefore(now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stale, err := s.orderRepo.FindStalePending(now.Add(-s.ttl))
+	if err != nil {
+		return holds, 0, err
+	}
+	orders := 0
+	for _, order := range stale {
+		// A webhook may have settled the order since it was listed
+		if err := s.orderRepo.MarkFailed(order); err != nil {
+			if !errors.Is(err, ErrOrderStateChanged) {
+				log.Printf("Hold reaper failed to expire order: order=%s err=%v", order.ID, err)
+			}
+			continue
+		}
+		orders++
+	}
+	return holds, orders, nil
+}
+
+// StartReaper releases expired holds and pending orders on the provided interval until the
+// context is cancelled.
+func (s *holdService) StartReaper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				holds, orders, err := s.ReleaseExpired(time.Now())
+				if err != nil {
+					log.Printf("Hold reaper error: %v", err)
+					continue
+				}
+				if holds > 0 || orders > 0 {
+					log.Printf("Hold reaper released tickets: holds=%d orders=%d", holds, orders)
+				}
+			}
+		}
+	}()
+}
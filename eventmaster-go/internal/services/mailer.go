@@ -0,0 +1,23 @@
+package services
+
+import "log"
+
+// Mailer delivers transactional email such as collaborator invitations
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// LogMailer writes emails to the log instead of sending them. It is used for local
+// development until a mail provider is configured.
+type LogMailer struct{}
+
+// NewLogMailer creates a mailer that logs every email
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the email
+func (m *LogMailer) Send(to, subject, body string) error {
+	log.Printf("Email to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrPaymentNotFound    = errors.New("payment not found")
+	ErrEmptyOrder         = errors.New("an order needs at least one ticket")
+	ErrInvalidOrderItem   = errors.New("invalid order item")
+	ErrMixedCurrency      = errors.New("all tickets of an order must be priced in the same currency")
+	ErrUnknownProvider    = errors.New("unknown payment provider")
+	ErrPaymentRequired    = errors.New("paid tickets must be bought through checkout")
+	ErrOrderNotRefundable = errors.New("only paid orders can be refunded")
+)
+
+// ErrOrderStateChanged is returned when an order is no longer in the state a transition starts from
+var ErrOrderStateChanged = repositories.ErrOrderStateChanged
+
+// ErrOrderRefunded is returned when restoring a participant whose order was refunded
+var ErrOrderRefunded = repositories.ErrOrderRefunded
+
+// OrderService sells tickets through orders. Tickets are held while an order is pending and
+// participants are created once the payment provider confirms the payment.
+type OrderService interface {
+	Checkout(ctx context.Context, order *models.Order) (*models.Order, *PaymentSession, error)
+	GetOrder(id string) (*models.Order, error)
+	ListEventOrders(actor Actor, eventID string) ([]*models.Order, error)
+	RefundOrder(ctx context.Context, actor Actor, id string) (*models.Order, error)
+	HandleWebhook(provider string, header http.Header, body []byte) error
+}
+
+type orderService struct {
+	orderRepo      repositories.OrderRepository
+	eventRepo      repositories.EventRepository
+	ticketTypeRepo repositories.TicketTypeRepository
+	promoCodeRepo  repositories.PromoCodeRepository
+	provider       PaymentProvider
+	access         EventAccess
+}
+
+// NewOrderService creates a new order service collecting payments through provider
+func NewOrderService(
+	orderRepo repositories.OrderRepository,
+	eventRepo repositories.EventRepository,
+	ticketTypeRepo repositories.TicketTypeRepository,
+	promoCodeRepo repositories.PromoCodeRepository,
+	provider PaymentProvider,
+	access EventAccess,
+) OrderService {
+	return &orderService{
+		orderRepo:      orderRepo,
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		promoCodeRepo:  promoCodeRepo,
+		provider:       provider,
+		access:         access,
+	}
+}
+
+// Checkout prices the order lines, applies the promo code entered by the buyer, holds the
+// tickets and starts the payment. Free orders, such as comps with a 100% code, are paid
+// straight away and come back without a payment session.
+func (s *orderService) Checkout(ctx context.Context, order *models.Order) (*models.Order, *PaymentSession, error) {
+	event, err := s.eventRepo.FindByID(order.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event.Status != models.EventStatusPublished {
+		return nil, nil, ErrEventNotOpen
+	}
+
+	if err := s.priceItems(order); err != nil {
+		return nil, nil, err
+	}
+	promoCode, err := s.applyPromoCode(order)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.DateOfBirth == nil {
+		now := time.Now()
+		order.DateOfBirth = &now
+	}
+	order.Provider = ""
+	order.ProviderRef = ""
+
+	if err := s.orderRepo.CreatePending(order, promoCode); err != nil {
+		return nil, nil, err
+	}
+
+	if order.TotalCents == 0 {
+		if err := s.orderRepo.MarkPaid(order, order.Participants()); err != nil {
+			return nil, nil, err
+		}
+		paid, err := s.orderRepo.FindWithItems(order.ID)
+		return paid, nil, err
+	}
+
+	session, err := s.provider.CreatePayment(ctx, order)
+	if err == nil {
+		err = s.orderRepo.SetProviderRef(order.ID, s.provider.Name(), session.Reference)
+	}
+	if err != nil {
+		// Give the held tickets back rather than leaving an order nobody can pay
+		if failErr := s.orderRepo.MarkFailed(order); failErr != nil {
+			return nil, nil, errors.Join(err, failErr)
+		}
+		return nil, nil, err
+	}
+
+	pending, err := s.orderRepo.FindWithItems(order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pending, session, nil
+}
+
+// GetOrder returns an order with its lines. Order IDs are unguessable, so buyers can follow
+// their order without an account.
+func (s *orderService) GetOrder(id string) (*models.Order, error) {
+	order, err := s.orderRepo.FindWithItems(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	return order, err
+}
+
+func (s *orderService) ListEventOrders(actor Actor, eventID string) ([]*models.Order, error) {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionView); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.FindByEventID(eventID)
+}
+
+// RefundOrder refunds a paid order at the provider, returns its tickets to sale and
+// removes the participants it created
+func (s *orderService) RefundOrder(ctx context.Context, actor Actor, id string) (*models.Order, error) {
+	order, err := s.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.access.FindEvent(actor, order.EventID, PermissionEdit); err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPaid {
+		return nil, ErrOrderNotRefundable
+	}
+
+	// Free orders never reached the provider
+	if order.Provider != "" {
+		if err := s.provider.Refund(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.orderRepo.MarkRefunded(order); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.FindWithItems(id)
+}
+
+// HandleWebhook applies a payment outcome reported by the provider. Repeated deliveries of
+// the same outcome are acknowledged without changing anything.
+func (s *orderService) HandleWebhook(provider string, header http.Header, body []byte) error {
+	if provider != s.provider.Name() {
+		return ErrUnknownProvider
+	}
+	notification, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	order, err := s.orderRepo.FindByProviderRef(provider, notification.Reference)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPaymentNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	switch notification.Outcome {
+	case PaymentSucceeded:
+		if order.Status == models.OrderStatusPaid {
+			return nil
+		}
+		err := s.orderRepo.MarkPaid(order, order.Participants())
+		if errors.Is(err, ErrOrderStateChanged) {
+			return s.refundLatePayment(order.ID)
+		}
+		return err
+	case PaymentFailed:
+		if order.Status == models.OrderStatusFailed {
+			return nil
+		}
+		return s.orderRepo.MarkFailed(order)
+	case PaymentRefunded:
+		if order.Status == models.OrderStatusRefunded {
+			return nil
+		}
+		return s.orderRepo.MarkRefunded(order)
+	default:
+		return fmt.Errorf("%w: unknown outcome %q", ErrInvalidWebhook, notification.Outcome)
+	}
+}
+
+// refundLatePayment refunds a payment that succeeded after the hold reaper failed its order
+// and put the tickets back on sale. Other states are left alone: a concurrent delivery may
+// have settled the order already.
+func (s *orderService) refundLatePayment(id string) error {
+	order, err := s.orderRepo.FindWithItems(id)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.OrderStatusFailed {
+		return nil
+	}
+
+	// Refund before recording it, so a failed refund is retried with the next delivery
+	if err := s.provider.Refund(context.Background(), order); err != nil {
+		return err
+	}
+	log.Printf("Refunded payment received after the order expired: order=%s", order.ID)
+	if err := s.orderRepo.MarkLatePaymentRefunded(order); err != nil && !errors.Is(err, ErrOrderStateChanged) {
+		return err
+	}
+	return nil
+}
+
+// priceItems checks every line against its ticket type and fills in names, prices and the total
+func (s *orderService) priceItems(order *models.Order) error {
+	if len(order.Items) == 0 {
+		return ErrEmptyOrder
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(order.Items))
+	order.Currency = ""
+	order.SubtotalCents = 0
+	for i := range order.Items {
+		item := &order.Items[i]
+		if seen[item.TicketTypeID] {
+			return fmt.Errorf("%w: ticket type %s is listed twice", ErrInvalidOrderItem, item.TicketTypeID)
+		}
+		seen[item.TicketTypeID] = true
+
+		ticketType, err := s.ticketTypeRepo.FindForEvent(order.EventID, item.TicketTypeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTicketTypeNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !ticketType.OnSaleAt(now) {
+			if available := ticketType.Available(); available != nil && *available == 0 {
+				return ErrSoldOut
+			}
+			return ErrTicketNotOnSale
+		}
+		if item.Quantity < ticketType.MinPerOrder || item.Quantity > ticketType.MaxPerOrder {
+			return fmt.Errorf("%w: %q tickets are sold %d to %d per order", ErrInvalidOrderItem,
+				ticketType.Name, ticketType.MinPerOrder, ticketType.MaxPerOrder)
+		}
+		if order.Currency == "" {
+			order.Currency = ticketType.Currency
+		} else if order.Currency != ticketType.Currency {
+			return ErrMixedCurrency
+		}
+
+		item.TicketTypeName = ticketType.Name
+		item.UnitPriceCents = ticketType.PriceCents
+		order.SubtotalCents += item.TotalCents()
+	}
+	order.DiscountCents = 0
+	order.TotalCents = order.SubtotalCents
+	return nil
+}
+
+// applyPromoCode looks up the code entered for the order and takes its discount off the total.
+// The code is only redeemed, within its limits, when the order is saved.
+func (s *orderService) applyPromoCode(order *models.Order) (*models.PromoCode, error) {
+	order.PromoCodeID = nil
+	order.PromoCode = strings.TrimSpace(order.PromoCode)
+	if order.PromoCode == "" {
+		return nil, nil
+	}
+
+	promoCode, err := s.promoCodeRepo.FindByCode(order.EventID, order.PromoCode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !promoCode.ValidAt(time.Now()) {
+		return nil, ErrPromoCodeNotValid
+	}
+	if promoCode.Exhausted() {
+		return nil, ErrPromoCodeExhausted
+	}
+	if !promoCode.AppliesTo(order) {
+		return nil, ErrPromoCodeNotApplicable
+	}
+	if promoCode.DiscountType == models.DiscountFixed && promoCode.Currency != order.Currency {
+		return nil, ErrPromoCodeNotApplicable
+	}
+
+	order.PromoCodeID = &promoCode.ID
+	order.PromoCode = promoCode.Code
+	order.DiscountCents = promoCode.DiscountFor(order)
+	order.TotalCents = order.SubtotalCents - order.DiscountCents
+	return promoCode, nil
+}
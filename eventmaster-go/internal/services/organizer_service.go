@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizerNotFound = errors.New("organizer profile not found")
+	ErrOrganizerInUse    = errors.New("organizer profile is linked to events")
+	ErrLastManager       = errors.New("an organizer profile needs at least one manager")
+	ErrUserNotFound      = errors.New("user not found")
+)
+
+const (
+	defaultOrganizerPageLimit = 20
+	maxOrganizerPageLimit     = 100
+)
+
+// OrganizerPage is the public page of an organizer: the profile with its
+// upcoming events soonest first and past events most recent first
+type OrganizerPage struct {
+	Profile       *models.OrganizerProfile
+	Upcoming      []*models.Event
+	UpcomingTotal int64
+	Past          []*models.Event
+	PastTotal     int64
+}
+
+// OrganizerService manages organizer profiles and the users allowed to manage them
+type OrganizerService interface {
+	GetOrganizer(id string) (*models.OrganizerProfile, error)
+	GetOrganizerPage(id string, limit int) (*OrganizerPage, error)
+	ListManagedOrganizers(userID string) ([]*models.OrganizerProfile, error)
+	CreateOrganizer(profile *models.OrganizerProfile, userID string) (*models.OrganizerProfile, error)
+	UpdateOrganizer(actor Actor, id string, apply func(profile *models.OrganizerProfile)) (*models.OrganizerProfile, error)
+	DeleteOrganizer(actor Actor, id string) error
+	ListManagers(actor Actor, id string) (*models.OrganizerProfile, error)
+	AddManager(actor Actor, id, email string) (*models.OrganizerProfile, error)
+	RemoveManager(actor Actor, id, userID string) (*models.OrganizerProfile, error)
+	MigrateOrganizerStrings() (int64, error)
+}
+
+type organizerService struct {
+	organizerRepo repositories.OrganizerRepository
+	eventRepo     repositories.EventRepository
+	userRepo      repositories.UserRepository
+	imageRepo     repositories.ImageRepository
+}
+
+// NewOrganizerService creates a new organizer profile service
+func NewOrganizerService(
+	organizerRepo repositories.OrganizerRepository,
+	eventRepo repositories.EventRepository,
+	userRepo repositories.UserRepository,
+	imageRepo repositories.ImageRepository,
+) OrganizerService {
+	return &organizerService{
+		organizerRepo: organizerRepo,
+		eventRepo:     eventRepo,
+		userRepo:      userRepo,
+		imageRepo:     imageRepo,
+	}
+}
+
+func (s *organizerService) GetOrganizer(id string) (*models.OrganizerProfile, error) {
+	profile, err := s.organizerRepo.FindWithDetails(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrganizerNotFound
+	}
+	return profile, err
+}
+
+// GetOrganizerPage loads a profile with up to limit of its upcoming and past events.
+// Drafts never appear on the public page.
+func (s *organizerService) GetOrganizerPage(id string, limit int) (*OrganizerPage, error) {
+	if limit < 1 {
+		limit = defaultOrganizerPageLimit
+	}
+	if limit > maxOrganizerPageLimit {
+		limit = maxOrganizerPageLimit
+	}
+
+	profile, err := s.GetOrganizer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	page := &OrganizerPage{Profile: profile}
+
+	page.Upcoming, page.UpcomingTotal, err = s.eventRepo.FindPaginated(
+		repositories.EventFilter{OrganizerID: id, From: &now}, query.Query{}, 1, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst := query.Query{Orders: []query.Order{{Column: "event_date", Desc: true}}}
+	page.Past, page.PastTotal, err = s.eventRepo.FindPaginated(
+		repositories.EventFilter{OrganizerID: id, To: &now}, newestFirst, 1, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *organizerService) ListManagedOrganizers(userID string) ([]*models.OrganizerProfile, error) {
+	return s.organizerRepo.FindByManager(userID)
+}
+
+// CreateOrganizer stores a new profile managed by userID
+func (s *organizerService) CreateOrganizer(profile *models.OrganizerProfile, userID string) (*models.OrganizerProfile, error) {
+	if err := s.checkLogo(profile); err != nil {
+		return nil, err
+	}
+	if err := s.organizerRepo.CreateWithManager(profile, userID); err != nil {
+		return nil, err
+	}
+	return s.GetOrganizer(profile.ID)
+}
+
+// UpdateOrganizer lets a manager or an admin change a profile. Linked events keep the
+// organizer name they were saved with. The profile's update time is part of their ETags,
+// so conditional requests for them stop returning 304 after the edit.
+func (s *organizerService) UpdateOrganizer(actor Actor, id string, apply func(profile *models.OrganizerProfile)) (*models.OrganizerProfile, error) {
+	profile, err := s.findManaged(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apply(profile)
+	if err := s.checkLogo(profile); err != nil {
+		return nil, err
+	}
+	if err := s.organizerRepo.Update(profile); err != nil {
+		return nil, err
+	}
+	return s.GetOrganizer(id)
+}
+
+// DeleteOrganizer removes a profile no event links to
+func (s *organizerService) DeleteOrganizer(actor Actor, id string) error {
+	if _, err := s.findManaged(actor, id); err != nil {
+		return err
+	}
+
+	count, err := s.organizerRepo.CountEvents(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrOrganizerInUse
+	}
+
+	return s.organizerRepo.Delete(id)
+}
+
+// ListManagers returns the profile with its managers, which only managers and admins may see
+func (s *organizerService) ListManagers(actor Actor, id string) (*models.OrganizerProfile, error) {
+	return s.findManaged(actor, id)
+}
+
+// AddManager grants the user registered with email the right to manage the profile
+func (s *organizerService) AddManager(actor Actor, id, email string) (*models.OrganizerProfile, error) {
+	if _, err := s.findManaged(actor, id); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.organizerRepo.AddManager(id, user.ID); err != nil {
+		return nil, err
+	}
+	return s.GetOrganizer(id)
+}
+
+// RemoveManager revokes a manager, refusing to leave the profile without one
+func (s *organizerService) RemoveManager(actor Actor, id, userID string) (*models.OrganizerProfile, error) {
+	profile, err := s.findManaged(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.IsManagedBy(userID) {
+		return nil, ErrUserNotFound
+	}
+	if len(profile.Managers) <= 1 {
+		return nil, ErrLastManager
+	}
+
+	if err := s.organizerRepo.RemoveManager(id, userID); err != nil {
+		return nil, err
+	}
+	return s.GetOrganizer(id)
+}
+
+// MigrateOrganizerStrings gives every event still carrying only a free-text organizer a
+// profile: one per owner and name, managed by the owner. It is safe to run repeatedly and
+// returns the number of events linked.
+func (s *organizerService) MigrateOrganizerStrings() (int64, error) {
+	unlinked, err := s.organizerRepo.FindUnlinkedOrganizers()
+	if err != nil {
+		return 0, err
+	}
+
+	var linked int64
+	for _, organizer := range unlinked {
+		profile, err := s.organizerRepo.FindOrCreateManaged(organizer.Organizer, organizer.UserID)
+		if err != nil {
+			return linked, err
+		}
+		count, err := s.organizerRepo.LinkEvents(profile.ID, organizer.UserID, organizer.Organizer)
+		if err != nil {
+			return linked, err
+		}
+		linked += count
+	}
+	return linked, nil
+}
+
+func (s *organizerService) findManaged(actor Actor, id string) (*models.OrganizerProfile, error) {
+	profile, err := s.GetOrganizer(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin && !profile.IsManagedBy(actor.UserID) {
+		return nil, ErrForbidden
+	}
+	return profile, nil
+}
+
+func (s *organizerService) checkLogo(profile *models.OrganizerProfile) error {
+	profile.Logo = nil
+	if profile.LogoID == nil {
+		return nil
+	}
+	images, err := s.imageRepo.FindByIDs([]string{*profile.LogoID})
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return ErrImageNotFound
+	}
+	return nil
+}
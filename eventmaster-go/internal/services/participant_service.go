@@ -1,24 +1,30 @@
 package services
 
 import (
+	"errors"
 	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/query"
 	"eventmaster-go/internal/repositories"
 	"fmt"
 	"math/rand"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // ParticipantService handles participant-related business logic
 type ParticipantService interface {
 	RegisterParticipant(participant *models.Participant) (*models.Participant, error)
-	GetEventParticipants(eventID string) ([]*models.Participant, error)
-	GetParticipantByID(id string) (*models.Participant, error)
+	RegisterWithHold(holdID string, participant *models.Participant) (*models.Participant, error)
+	TicketCode(participant *models.Participant) string
+	GetEventParticipants(actor Actor, eventID string, q query.Query) ([]*models.Participant, error)
+	GetParticipantByID(actor Actor, id string) (*models.Participant, error)
 	GetParticipantByEmail(email string) ([]*models.Participant, error)
 	GetEventParticipantCount(eventID string) (int64, error)
-	DeleteParticipant(id string) error
+	DeleteParticipant(actor Actor, id string) error
 	GenerateFakeParticipants(event *models.Event, count int) error
-	RegistrationsPerDay(eventID string) ([]*repositories.RegistrationsPerDayResult, error)
+	RegistrationsPerDay(actor Actor, eventID, timeZone string) ([]*repositories.RegistrationsPerDayResult, error)
 }
 
 func (s *participantService) GenerateFakeParticipants(event *models.Event, count int) error {
@@ -79,28 +85,46 @@ func randomDiscoverySource() models.SourceOfDiscovery {
 	return discoverySources[seededRand.Intn(len(discoverySources))]
 }
 
+// ErrEventNotOpen is returned when registering for an event that is not published
+var ErrEventNotOpen = errors.New("event is not open for registration")
+
 type participantService struct {
 	participantRepo repositories.ParticipantRepository
 	eventRepo       repositories.EventRepository
+	ticketTypeRepo  repositories.TicketTypeRepository
+	holdRepo        repositories.HoldRepository
+	signer          *TicketSigner
+	access          EventAccess
 }
 
 // NewParticipantService creates a new participant service
 func NewParticipantService(
 	participantRepo repositories.ParticipantRepository,
 	eventRepo repositories.EventRepository,
+	ticketTypeRepo repositories.TicketTypeRepository,
+	holdRepo repositories.HoldRepository,
+	signer *TicketSigner,
+	access EventAccess,
 ) ParticipantService {
 	return &participantService{
 		participantRepo: participantRepo,
 		eventRepo:       eventRepo,
+		ticketTypeRepo:  ticketTypeRepo,
+		holdRepo:        holdRepo,
+		signer:          signer,
+		access:          access,
 	}
 }
 
 func (s *participantService) RegisterParticipant(participant *models.Participant) (*models.Participant, error) {
-	// Check if event exists
-	_, err := s.eventRepo.FindByID(participant.EventID)
+	// Check if event exists and is open for registration
+	event, err := s.eventRepo.FindByID(participant.EventID)
 	if err != nil {
 		return nil, err
 	}
+	if event.Status != models.EventStatusPublished {
+		return nil, ErrEventNotOpen
+	}
 
 	// Set current time if DateOfBirth is not provided
 	if participant.DateOfBirth == nil {
@@ -108,20 +132,106 @@ func (s *participantService) RegisterParticipant(participant *models.Participant
 		participant.DateOfBirth = &now
 	}
 
-	// Save participant
-	if err := s.participantRepo.Create(participant); err != nil {
+	if err := s.checkTicketType(participant); err != nil {
+		return nil, err
+	}
+
+	// Save participant, claiming a ticket of the chosen type in the same transaction
+	if err := s.participantRepo.CreateWithTicket(participant); err != nil {
 		return nil, err
 	}
 
 	return participant, nil
 }
 
-func (s *participantService) GetEventParticipants(eventID string) ([]*models.Participant, error) {
-	return s.participantRepo.FindByEventID(eventID)
+// RegisterWithHold completes a registration started with a ticket hold: the held ticket becomes
+// the participant's, so it cannot be lost to other registrations in the meantime
+func (s *participantService) RegisterWithHold(holdID string, participant *models.Participant) (*models.Participant, error) {
+	hold, err := s.holdRepo.FindByID(holdID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && hold.EventID != participant.EventID) {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.FindByID(participant.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != models.EventStatusPublished {
+		return nil, ErrEventNotOpen
+	}
+
+	if participant.DateOfBirth == nil {
+		now := time.Now()
+		participant.DateOfBirth = &now
+	}
+	participant.TicketTypeID = &hold.TicketTypeID
+
+	if err := s.holdRepo.Convert(hold, participant, time.Now()); err != nil {
+		return nil, err
+	}
+	return participant, nil
 }
 
-func (s *participantService) GetParticipantByID(id string) (*models.Participant, error) {
-	return s.participantRepo.FindByID(id)
+// TicketCode returns the signed code on the participant's ticket
+func (s *participantService) TicketCode(participant *models.Participant) string {
+	return s.signer.Sign(participant)
+}
+
+// checkTicketType makes sure the chosen ticket type belongs to the event, is on sale and free;
+// paid tickets go through checkout. Events selling tickets require one to be chosen; events
+// without any accept free registration.
+func (s *participantService) checkTicketType(participant *models.Participant) error {
+	if participant.TicketTypeID == nil {
+		ticketTypes, err := s.ticketTypeRepo.FindByEventID(participant.EventID)
+		if err != nil {
+			return err
+		}
+		for _, ticketType := range ticketTypes {
+			if !ticketType.Informational {
+				return ErrTicketTypeRequired
+			}
+		}
+		return nil
+	}
+
+	ticketType, err := s.ticketTypeRepo.FindForEvent(participant.EventID, *participant.TicketTypeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTicketTypeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return checkFreeTicketOnSale(ticketType, time.Now())
+}
+
+// checkFreeTicketOnSale makes sure tickets of the type can be taken without paying at the given time
+func checkFreeTicketOnSale(ticketType *models.TicketType, now time.Time) error {
+	if ticketType.OnSaleAt(now) {
+		if !ticketType.IsFree() {
+			return ErrPaymentRequired
+		}
+		return nil
+	}
+	if available := ticketType.Available(); available != nil && *available == 0 {
+		return ErrSoldOut
+	}
+	return ErrTicketNotOnSale
+}
+
+// GetEventParticipants lists the participants of an event to its collaborators
+func (s *participantService) GetEventParticipants(actor Actor, eventID string, q query.Query) ([]*models.Participant, error) {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionView); err != nil {
+		return nil, err
+	}
+	return s.participantRepo.FindByEventID(eventID, q)
+}
+
+// GetParticipantByID returns a participant to the collaborators of their event
+func (s *participantService) GetParticipantByID(actor Actor, id string) (*models.Participant, error) {
+	return s.findParticipant(actor, id, PermissionView)
 }
 
 func (s *participantService) GetParticipantByEmail(email string) ([]*models.Participant, error) {
@@ -132,10 +242,34 @@ func (s *participantService) GetEventParticipantCount(eventID string) (int64, er
 	return s.participantRepo.CountByEventID(eventID)
 }
 
-func (s *participantService) DeleteParticipant(id string) error {
-	return s.participantRepo.Delete(id)
+func (s *participantService) DeleteParticipant(actor Actor, id string) error {
+	participant, err := s.findParticipant(actor, id, PermissionEdit)
+	if err != nil {
+		return err
+	}
+	return s.participantRepo.DeleteWithTicket(participant)
+}
+
+// RegistrationsPerDay buckets registrations by day in timeZone, or in the event's own zone when empty
+func (s *participantService) RegistrationsPerDay(actor Actor, eventID, timeZone string) ([]*repositories.RegistrationsPerDayResult, error) {
+	event, err := s.access.FindEvent(actor, eventID, PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	if timeZone == "" {
+		timeZone = event.TimeZoneName()
+	}
+	return s.participantRepo.RegistrationsPerDay(eventID, timeZone)
 }
 
-func (s *participantService) RegistrationsPerDay(eventID string) ([]*repositories.RegistrationsPerDayResult, error) {
-	return s.participantRepo.RegistrationsPerDay(eventID)
+// findParticipant loads a participant the actor holds permission on through their event
+func (s *participantService) findParticipant(actor Actor, id string, permission Permission) (*models.Participant, error) {
+	participant, err := s.participantRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.access.FindEvent(actor, participant.EventID, permission); err != nil {
+		return nil, err
+	}
+	return participant, nil
 }
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"eventmaster-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidWebhook is returned when a payment webhook cannot be verified or decoded
+var ErrInvalidWebhook = errors.New("invalid payment webhook")
+
+// PaymentOutcome is the result of a payment reported by a provider
+type PaymentOutcome string
+
+const (
+	PaymentSucceeded PaymentOutcome = "succeeded"
+	PaymentFailed    PaymentOutcome = "failed"
+	PaymentRefunded  PaymentOutcome = "refunded"
+)
+
+// PaymentSession is a payment started at a provider for an order
+type PaymentSession struct {
+	// Reference identifies the payment at the provider; webhooks refer to it
+	Reference string
+	// CheckoutURL is where the buyer completes the payment
+	CheckoutURL string
+}
+
+// PaymentNotification is a verified webhook telling the outcome of a payment
+type PaymentNotification struct {
+	Reference string
+	Outcome   PaymentOutcome
+}
+
+// PaymentProvider collects payments for orders. Providers confirm payments asynchronously
+// by calling the payment webhook, which is decoded with ParseWebhook.
+type PaymentProvider interface {
+	// Name identifies the provider in webhook URLs and on orders
+	Name() string
+	CreatePayment(ctx context.Context, order *models.Order) (*PaymentSession, error)
+	Refund(ctx context.Context, order *models.Order) error
+	// ParseWebhook verifies the signature of a webhook and decodes it
+	ParseWebhook(header http.Header, body []byte) (*PaymentNotification, error)
+}
+
+// FakePaymentSignatureHeader carries the HMAC-SHA256 of fake provider webhook bodies
+const FakePaymentSignatureHeader = "X-Fake-Signature"
+
+// FakePaymentProvider simulates a payment provider for local development and tests. A payment
+// is settled by calling Complete, after which the provider posts a signed webhook to
+// WebhookURL with a delay, like a real provider would.
+type FakePaymentProvider struct {
+	secret     []byte
+	webhookURL string
+	delay      time.Duration
+	client     *http.Client
+
+	mu       sync.Mutex
+	payments map[string]string
+}
+
+// NewFakePaymentProvider creates a fake provider signing its webhooks with secret
+func NewFakePaymentProvider(secret, webhookURL string, delay time.Duration) *FakePaymentProvider {
+	return &FakePaymentProvider{
+		secret:     []byte(secret),
+		webhookURL: webhookURL,
+		delay:      delay,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		payments:   make(map[string]string),
+	}
+}
+
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePaymentProvider) CreatePayment(ctx context.Context, order *models.Order) (*PaymentSession, error) {
+	reference := "fake_" + uuid.NewString()
+
+	p.mu.Lock()
+	p.payments[reference] = order.ID
+	p.mu.Unlock()
+
+	return &PaymentSession{
+		Reference:   reference,
+		CheckoutURL: "/api/payments/fake/" + reference,
+	}, nil
+}
+
+// Refund always succeeds; the refund is confirmed by the caller rather than a webhook
+func (p *FakePaymentProvider) Refund(ctx context.Context, order *models.Order) error {
+	return nil
+}
+
+// Complete settles a payment as the buyer would on the provider's checkout page and
+// schedules the webhook reporting the outcome
+func (p *FakePaymentProvider) Complete(reference string, outcome PaymentOutcome) error {
+	p.mu.Lock()
+	_, ok := p.payments[reference]
+	delete(p.payments, reference)
+	p.mu.Unlock()
+	if !ok {
+		return ErrPaymentNotFound
+	}
+
+	body, err := json.Marshal(map[string]string{"reference": reference, "outcome": string(outcome)})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		time.Sleep(p.delay)
+		if err := p.deliver(body); err != nil {
+			log.Printf("Fake payment webhook failed: reference=%s err=%v", reference, err)
+		}
+	}()
+	return nil
+}
+
+func (p *FakePaymentProvider) deliver(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, p.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FakePaymentSignatureHeader, p.sign(body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *FakePaymentProvider) ParseWebhook(header http.Header, body []byte) (*PaymentNotification, error) {
+	signature, err := hex.DecodeString(header.Get(FakePaymentSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.mac(body)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidWebhook)
+	}
+
+	var payload struct {
+		Reference string         `json:"reference"`
+		Outcome   PaymentOutcome `json:"outcome"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if payload.Reference == "" {
+		return nil, fmt.Errorf("%w: missing reference", ErrInvalidWebhook)
+	}
+	return &PaymentNotification{Reference: payload.Reference, Outcome: payload.Outcome}, nil
+}
+
+func (p *FakePaymentProvider) sign(body []byte) string {
+	return hex.EncodeToString(p.mac(body))
+}
+
+func (p *FakePaymentProvider) mac(body []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
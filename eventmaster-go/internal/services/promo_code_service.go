@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrInvalidPromoCode       = errors.New("invalid promo code")
+	ErrPromoCodeTaken         = errors.New("the event already has a promo code with this code")
+	ErrPromoCodeNotValid      = errors.New("promo code is not valid at this time")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to the tickets in this order")
+)
+
+var (
+	// ErrPromoCodeExhausted is returned when a promo code has been used as often as allowed
+	ErrPromoCodeExhausted = repositories.ErrPromoCodeExhausted
+	// ErrPromoEmailLimit is returned when a buyer has used a promo code as often as allowed per email
+	ErrPromoEmailLimit = repositories.ErrPromoEmailLimit
+	// ErrRedemptionsAboveMax is returned when a redemption cap would drop below the redemptions made
+	ErrRedemptionsAboveMax = repositories.ErrRedemptionsAboveMax
+	// ErrPromoCodeInUse is returned when deleting a promo code that has been redeemed
+	ErrPromoCodeInUse = repositories.ErrPromoCodeInUse
+)
+
+// promoCodePattern is the shape of a normalized promo code
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{2,63}$`)
+
+// PromoCodeReport is the redemption report of one promo code, broken down by how the
+// buyers found the event
+type PromoCodeReport struct {
+	PromoCode     *models.PromoCode
+	Redemptions   int64
+	PaidOrders    int64
+	DiscountCents int64
+	RevenueCents  int64
+	BySource      []*repositories.RedemptionReportRow
+}
+
+// PromoCodeService manages the promo codes of events and reports on their use
+type PromoCodeService interface {
+	ListPromoCodes(actor Actor, eventID string) ([]*models.PromoCode, error)
+	CreatePromoCode(actor Actor, eventID string, promoCode *models.PromoCode) (*models.PromoCode, error)
+	UpdatePromoCode(actor Actor, eventID, id string, apply func(promoCode *models.PromoCode)) (*models.PromoCode, error)
+	DeletePromoCode(actor Actor, eventID, id string) error
+	RedemptionReport(actor Actor, eventID string) ([]*PromoCodeReport, error)
+}
+
+type promoCodeService struct {
+	promoCodeRepo  repositories.PromoCodeRepository
+	eventRepo      repositories.EventRepository
+	ticketTypeRepo repositories.TicketTypeRepository
+	access         EventAccess
+}
+
+// NewPromoCodeService creates a new promo code service
+func NewPromoCodeService(
+	promoCodeRepo repositories.PromoCodeRepository,
+	eventRepo repositories.EventRepository,
+	ticketTypeRepo repositories.TicketTypeRepository,
+	access EventAccess,
+) PromoCodeService {
+	return &promoCodeService{
+		promoCodeRepo:  promoCodeRepo,
+		eventRepo:      eventRepo,
+		ticketTypeRepo: ticketTypeRepo,
+		access:         access,
+	}
+}
+
+// ListPromoCodes lists the codes of an event; codes are only shown to its organizers
+func (s *promoCodeService) ListPromoCodes(actor Actor, eventID string) ([]*models.PromoCode, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	return s.promoCodeRepo.FindByEventID(eventID)
+}
+
+func (s *promoCodeService) CreatePromoCode(actor Actor, eventID string, promoCode *models.PromoCode) (*models.PromoCode, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+
+	promoCode.EventID = eventID
+	promoCode.Redeemed = 0
+	if err := s.preparePromoCode(promoCode); err != nil {
+		return nil, err
+	}
+	if err := s.promoCodeRepo.Create(promoCode); err != nil {
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+// UpdatePromoCode edits a promo code. The redemption cap can be lowered down to the
+// redemptions made but no further.
+func (s *promoCodeService) UpdatePromoCode(actor Actor, eventID, id string, apply func(promoCode *models.PromoCode)) (*models.PromoCode, error) {
+	promoCode, err := s.findPromoCode(actor, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apply(promoCode)
+	if err := s.preparePromoCode(promoCode); err != nil {
+		return nil, err
+	}
+	if err := s.promoCodeRepo.UpdateDetails(promoCode); err != nil {
+		return nil, err
+	}
+	return s.promoCodeRepo.FindForEvent(eventID, id)
+}
+
+// DeletePromoCode removes a promo code nobody has used yet
+func (s *promoCodeService) DeletePromoCode(actor Actor, eventID, id string) error {
+	if _, err := s.findPromoCode(actor, eventID, id); err != nil {
+		return err
+	}
+	return s.promoCodeRepo.DeleteUnredeemed(id)
+}
+
+// RedemptionReport totals the redemptions of every code of the event, with the breakdown
+// by source of discovery of the buyers who used it
+func (s *promoCodeService) RedemptionReport(actor Actor, eventID string) ([]*PromoCodeReport, error) {
+	promoCodes, err := s.ListPromoCodes(actor, eventID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.promoCodeRepo.RedemptionReport(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*PromoCodeReport, len(promoCodes))
+	byID := make(map[string]*PromoCodeReport, len(promoCodes))
+	for i, promoCode := range promoCodes {
+		reports[i] = &PromoCodeReport{PromoCode: promoCode, BySource: []*repositories.RedemptionReportRow{}}
+		byID[promoCode.ID] = reports[i]
+	}
+	for _, row := range rows {
+		report, ok := byID[row.PromoCodeID]
+		if !ok {
+			continue
+		}
+		report.Redemptions += row.Redemptions
+		report.PaidOrders += row.PaidOrders
+		report.DiscountCents += row.DiscountCents
+		report.RevenueCents += row.RevenueCents
+		report.BySource = append(report.BySource, row)
+	}
+	return reports, nil
+}
+
+func (s *promoCodeService) findManagedEvent(actor Actor, eventID string) (*models.Event, error) {
+	return s.access.FindEvent(actor, eventID, PermissionEdit)
+}
+
+func (s *promoCodeService) findPromoCode(actor Actor, eventID, id string) (*models.PromoCode, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	promoCode, err := s.promoCodeRepo.FindForEvent(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPromoCodeNotFound
+	}
+	return promoCode, err
+}
+
+// preparePromoCode normalizes a promo code and checks the rules that span several fields
+func (s *promoCodeService) preparePromoCode(promoCode *models.PromoCode) error {
+	promoCode.Code = strings.ToUpper(strings.TrimSpace(promoCode.Code))
+	promoCode.Currency = strings.ToUpper(promoCode.Currency)
+	if !promoCodePattern.MatchString(promoCode.Code) {
+		return fmt.Errorf("%w: codes are 3 to 64 letters, digits, dashes or underscores", ErrInvalidPromoCode)
+	}
+
+	switch promoCode.DiscountType {
+	case models.DiscountPercent:
+		if promoCode.DiscountValue < 1 || promoCode.DiscountValue > 100 {
+			return fmt.Errorf("%w: percentages run from 1 to 100", ErrInvalidPromoCode)
+		}
+		promoCode.Currency = ""
+	case models.DiscountFixed:
+		if promoCode.DiscountValue < 1 {
+			return fmt.Errorf("%w: fixed discounts must be positive", ErrInvalidPromoCode)
+		}
+		if promoCode.Currency == "" {
+			return fmt.Errorf("%w: fixed discounts need a currency", ErrInvalidPromoCode)
+		}
+	default:
+		return fmt.Errorf("%w: unknown discount type %q", ErrInvalidPromoCode, promoCode.DiscountType)
+	}
+
+	existing, err := s.promoCodeRepo.FindByCode(promoCode.EventID, promoCode.Code)
+	if err == nil && existing.ID != promoCode.ID {
+		return ErrPromoCodeTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if promoCode.ValidFrom != nil && promoCode.ValidUntil != nil && !promoCode.ValidUntil.After(*promoCode.ValidFrom) {
+		return fmt.Errorf("%w: codes must expire after they become valid", ErrInvalidPromoCode)
+	}
+	if promoCode.MaxRedemptions != nil && *promoCode.MaxRedemptions < promoCode.Redeemed {
+		return ErrRedemptionsAboveMax
+	}
+
+	if promoCode.TicketTypeID != nil {
+		ticketType, err := s.ticketTypeRepo.FindForEvent(promoCode.EventID, *promoCode.TicketTypeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTicketTypeNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if promoCode.DiscountType == models.DiscountFixed && promoCode.Currency != ticketType.Currency {
+			return fmt.Errorf("%w: the discount currency must match the ticket type", ErrInvalidPromoCode)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxRelatedEvents is the most related events returned, and how many are cached per event
+	MaxRelatedEvents = 20
+	// maxRelatedCacheEntries bounds the number of events whose related events are cached
+	maxRelatedCacheEntries = 1000
+)
+
+// RelatedEventService recommends events similar to a given one. Results are cached per event
+// for a while, so the ranking lags behind edits by up to the cache TTL; events cancelled,
+// unpublished or trashed in the meantime are left out right away.
+type RelatedEventService interface {
+	RelatedEvents(actor Actor, eventID string, limit int) ([]*repositories.RelatedEvent, error)
+}
+
+type relatedEventService struct {
+	eventRepo repositories.EventRepository
+	access    EventAccess
+	ttl       time.Duration
+
+	mu      sync.Mutex
+	entries map[string]relatedCacheEntry
+	group   singleflight.Group
+}
+
+type relatedCacheEntry struct {
+	related   []*repositories.RelatedEvent
+	expiresAt time.Time
+}
+
+// NewRelatedEventService creates a new related event service caching results for ttl;
+// a zero ttl disables the cache
+func NewRelatedEventService(eventRepo repositories.EventRepository, access EventAccess, ttl time.Duration) RelatedEventService {
+	return &relatedEventService{
+		eventRepo: eventRepo,
+		access:    access,
+		ttl:       ttl,
+		entries:   make(map[string]relatedCacheEntry),
+	}
+}
+
+// RelatedEvents returns up to limit upcoming events most related to the event, best first.
+// Drafts only get recommendations for those who may view them.
+func (s *relatedEventService) RelatedEvents(actor Actor, eventID string, limit int) ([]*repositories.RelatedEvent, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.Status.IsPublic() {
+		if err := s.access.Authorize(actor, event, PermissionView); err != nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+	}
+
+	now := time.Now()
+	related, err := s.cached(event, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached events may have started since they were scored
+	upcoming := make([]*repositories.RelatedEvent, 0, limit)
+	for _, candidate := range related {
+		if len(upcoming) == limit {
+			break
+		}
+		if candidate.Event.EventDate != nil && candidate.Event.EventDate.Before(now) {
+			continue
+		}
+		upcoming = append(upcoming, candidate)
+	}
+	return upcoming, nil
+}
+
+// cached returns the related events of event from the cache, computing them once when
+// missing or expired however many requests ask at the same time
+func (s *relatedEventService) cached(event *models.Event, now time.Time) ([]*repositories.RelatedEvent, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[event.ID]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return s.stillRecommendable(entry.related)
+	}
+
+	result, err, _ := s.group.Do(event.ID, func() (interface{}, error) {
+		related, err := s.eventRepo.FindRelated(event, now, MaxRelatedEvents)
+		if err != nil {
+			return nil, err
+		}
+		if s.ttl > 0 {
+			s.store(event.ID, relatedCacheEntry{related: related, expiresAt: now.Add(s.ttl)}, now)
+		}
+		return related, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*repositories.RelatedEvent), nil
+}
+
+// stillRecommendable drops the cached events that are no longer published or postponed or
+// have been trashed since they were cached
+func (s *relatedEventService) stillRecommendable(related []*repositories.RelatedEvent) ([]*repositories.RelatedEvent, error) {
+	ids := make([]string, len(related))
+	for i, candidate := range related {
+		ids[i] = candidate.Event.ID
+	}
+	found, err := s.eventRepo.FindRecommendableIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == len(related) {
+		return related, nil
+	}
+
+	recommendable := make(map[string]bool, len(found))
+	for _, id := range found {
+		recommendable[id] = true
+	}
+	kept := make([]*repositories.RelatedEvent, 0, len(found))
+	for _, candidate := range related {
+		if recommendable[candidate.Event.ID] {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept, nil
+}
+
+// store caches an entry, first dropping expired entries and then arbitrary ones when full
+func (s *relatedEventService) store(eventID string, entry relatedCacheEntry, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) >= maxRelatedCacheEntries {
+		for id, cached := range s.entries {
+			if !now.Before(cached.expiresAt) {
+				delete(s.entries, id)
+			}
+		}
+	}
+	for id := range s.entries {
+		if len(s.entries) < maxRelatedCacheEntries {
+			break
+		}
+		delete(s.entries, id)
+	}
+	s.entries[eventID] = entry
+}
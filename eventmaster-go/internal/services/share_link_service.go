@@ -0,0 +1,131 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrShareLinkNotFound is returned for unknown share codes and links of other events
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+const (
+	// shareCodeAlphabet leaves out characters easily mistaken for each other when typed
+	shareCodeAlphabet = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	shareCodeLength   = 7
+)
+
+// ShareLinkService manages the short share codes of events and counts their clicks
+type ShareLinkService interface {
+	CreateShareLink(actor Actor, eventID, label string) (*models.ShareLink, error)
+	ListShareLinks(actor Actor, eventID string) ([]*models.ShareLink, error)
+	DeleteShareLink(actor Actor, eventID, id string) error
+	FollowShareLink(code string) (*models.Event, error)
+}
+
+type shareLinkService struct {
+	shareLinkRepo repositories.ShareLinkRepository
+	eventRepo     repositories.EventRepository
+	access        EventAccess
+}
+
+// NewShareLinkService creates a new share link service
+func NewShareLinkService(
+	shareLinkRepo repositories.ShareLinkRepository,
+	eventRepo repositories.EventRepository,
+	access EventAccess,
+) ShareLinkService {
+	return &shareLinkService{
+		shareLinkRepo: shareLinkRepo,
+		eventRepo:     eventRepo,
+		access:        access,
+	}
+}
+
+// CreateShareLink gives the event a new short code, labelled with the channel it is shared on
+func (s *shareLinkService) CreateShareLink(actor Actor, eventID, label string) (*models.ShareLink, error) {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionEdit); err != nil {
+		return nil, err
+	}
+	link := &models.ShareLink{
+		EventID:   eventID,
+		Label:     strings.TrimSpace(label),
+		CreatedBy: actor.UserID,
+	}
+	if err := s.shareLinkRepo.CreateWithCode(link, newShareCode); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// ListShareLinks lists the share links of an event with their click counts
+func (s *shareLinkService) ListShareLinks(actor Actor, eventID string) ([]*models.ShareLink, error) {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionView); err != nil {
+		return nil, err
+	}
+	return s.shareLinkRepo.FindByEventID(eventID)
+}
+
+func (s *shareLinkService) DeleteShareLink(actor Actor, eventID, id string) error {
+	if _, err := s.access.FindEvent(actor, eventID, PermissionEdit); err != nil {
+		return err
+	}
+	link, err := s.shareLinkRepo.FindForEvent(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrShareLinkNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.shareLinkRepo.Remove(link.ID)
+}
+
+// FollowShareLink returns the event the code points to and counts the click. Links to
+// events that are trashed or still drafts are not found and not counted.
+func (s *shareLinkService) FollowShareLink(code string) (*models.Event, error) {
+	link, err := s.shareLinkRepo.FindByCode(code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	event, err := s.eventRepo.FindByID(link.EventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !event.Status.IsPublic() {
+		return nil, ErrShareLinkNotFound
+	}
+
+	if _, err := s.shareLinkRepo.RecordClick(code, time.Now()); errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareLinkNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// newShareCode draws a random code from shareCodeAlphabet
+func newShareCode() (string, error) {
+	max := big.NewInt(int64(len(shareCodeAlphabet)))
+	code := make([]byte, shareCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
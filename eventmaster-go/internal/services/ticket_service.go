@@ -0,0 +1,468 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTicketNotFound   = errors.New("ticket not found")
+	ErrTicketRevoked    = errors.New("ticket is no longer valid")
+	ErrWrongEvent       = errors.New("ticket is for another event")
+	ErrAlreadyCheckedIn = errors.New("ticket has already been checked in")
+	ErrOrderNotPaid     = errors.New("tickets are issued once the order is paid")
+)
+
+const (
+	// manifestTTL is how long kiosks may rely on a manifest before downloading a fresh one
+	manifestTTL = 24 * time.Hour
+	// maxClockSkew is how far in the future a kiosk's scan time may be before it is refused
+	maxClockSkew = 5 * time.Minute
+)
+
+// Ticket is a participant with the code printed on their ticket
+type Ticket struct {
+	Participant *models.Participant
+	Code        string
+	CheckIn     *models.CheckIn
+}
+
+// CheckInStats counts the participants of an event checked in so far
+type CheckInStats struct {
+	EventID       string                  `json:"eventId"`
+	Registered    int64                   `json:"registered"`
+	CheckedIn     int64                   `json:"checkedIn"`
+	LastCheckInAt *time.Time              `json:"lastCheckInAt,omitempty"`
+	ByTicketType  []*TicketTypeCheckInRow `json:"byTicketType"`
+}
+
+// TicketTypeCheckInRow counts the check-ins of one ticket type
+type TicketTypeCheckInRow struct {
+	TicketTypeID   *string `json:"ticketTypeId"`
+	TicketTypeName string  `json:"ticketTypeName,omitempty"`
+	Registered     int64   `json:"registered"`
+	CheckedIn      int64   `json:"checkedIn"`
+}
+
+// TicketManifest lists the valid tickets of an event for kiosks checking in offline. Tickets
+// appear as hashes of their codes, so a leaked manifest cannot be turned into tickets.
+type TicketManifest struct {
+	EventID       string            `json:"eventId"`
+	EventTitle    string            `json:"eventTitle"`
+	EventDate     *time.Time        `json:"eventDate"`
+	GeneratedAt   time.Time         `json:"generatedAt"`
+	ExpiresAt     time.Time         `json:"expiresAt"`
+	HashAlgorithm string            `json:"hashAlgorithm"`
+	Tickets       []*ManifestTicket `json:"tickets"`
+}
+
+// ManifestTicket is one ticket of a manifest
+type ManifestTicket struct {
+	CodeHash      string     `json:"codeHash"`
+	ParticipantID string     `json:"participantId"`
+	FullName      string     `json:"fullName"`
+	TicketTypeID  *string    `json:"ticketTypeId,omitempty"`
+	CheckedInAt   *time.Time `json:"checkedInAt,omitempty"`
+}
+
+// OfflineScan is a ticket scanned by a kiosk while offline, at the kiosk's clock
+type OfflineScan struct {
+	Code      string
+	ScannedAt time.Time
+}
+
+// SyncStatus is the outcome of one uploaded scan
+type SyncStatus string
+
+const (
+	// SyncAccepted means the scan is the recorded check-in of its ticket
+	SyncAccepted SyncStatus = "accepted"
+	// SyncDuplicate means an earlier scan of the same ticket was kept instead
+	SyncDuplicate SyncStatus = "duplicate"
+	// SyncRejected means the scan is not a valid ticket of the event
+	SyncRejected SyncStatus = "rejected"
+)
+
+// SyncResult reports what became of an uploaded scan. CheckIn is the check-in kept for the
+// ticket, which for duplicates is the scan that won.
+type SyncResult struct {
+	Code          string
+	Status        SyncStatus
+	Reason        string
+	ParticipantID string
+	CheckIn       *models.CheckIn
+}
+
+// TicketService issues signed ticket codes and checks tickets in at the door
+type TicketService interface {
+	FindTicket(code string) (*Ticket, error)
+	OrderTickets(orderID string) ([]*Ticket, error)
+	CheckIn(actor Actor, eventID, code string) (*Ticket, error)
+	ListCheckIns(actor Actor, eventID string) ([]*models.CheckIn, error)
+	CheckInStats(actor Actor, eventID string) (*CheckInStats, error)
+	SubscribeCheckIns(actor Actor, eventID string) (<-chan struct{}, func(), error)
+	TicketManifest(actor Actor, eventID string) ([]byte, string, error)
+	ManifestPublicKey() string
+	SyncCheckIns(actor Actor, eventID, deviceID string, scans []OfflineScan) ([]*SyncResult, error)
+}
+
+type ticketService struct {
+	participantRepo repositories.ParticipantRepository
+	eventRepo       repositories.EventRepository
+	ticketTypeRepo  repositories.TicketTypeRepository
+	orderRepo       repositories.OrderRepository
+	checkInRepo     repositories.CheckInRepository
+	signer          *TicketSigner
+	access          EventAccess
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+// NewTicketService creates a new ticket service signing codes with signer
+func NewTicketService(
+	participantRepo repositories.ParticipantRepository,
+	eventRepo repositories.EventRepository,
+	ticketTypeRepo repositories.TicketTypeRepository,
+	orderRepo repositories.OrderRepository,
+	checkInRepo repositories.CheckInRepository,
+	signer *TicketSigner,
+	access EventAccess,
+) TicketService {
+	return &ticketService{
+		participantRepo: participantRepo,
+		eventRepo:       eventRepo,
+		ticketTypeRepo:  ticketTypeRepo,
+		orderRepo:       orderRepo,
+		checkInRepo:     checkInRepo,
+		signer:          signer,
+		access:          access,
+		subscribers:     make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// FindTicket resolves a ticket code to its participant. The code is the credential, so whoever
+// holds it may see the ticket.
+func (s *ticketService) FindTicket(code string) (*Ticket, error) {
+	participant, err := s.verifyCode(code)
+	if err != nil {
+		return nil, err
+	}
+	ticket := &Ticket{Participant: participant, Code: s.signer.Sign(participant)}
+	checkIn, err := s.checkInRepo.FindByParticipantID(participant.ID)
+	if err == nil {
+		ticket.CheckIn = checkIn
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// OrderTickets lists the tickets of a paid order. Order IDs are unguessable, so buyers can
+// fetch their tickets without an account.
+func (s *ticketService) OrderTickets(orderID string) ([]*Ticket, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPaid {
+		return nil, ErrOrderNotPaid
+	}
+
+	participants, err := s.participantRepo.FindByOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]*Ticket, len(participants))
+	for i, participant := range participants {
+		tickets[i] = &Ticket{Participant: participant, Code: s.signer.Sign(participant)}
+	}
+	return tickets, nil
+}
+
+// CheckIn admits the holder of a ticket to an event. Codes for other events, forged codes and
+// tickets of removed participants are rejected; a second scan of the same ticket returns
+// ErrAlreadyCheckedIn along with the first check-in.
+func (s *ticketService) CheckIn(actor Actor, eventID, code string) (*Ticket, error) {
+	if _, err := s.findManagedEvent(actor, eventID, PermissionCheckIn); err != nil {
+		return nil, err
+	}
+	participant, err := s.verifyCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if participant.EventID != eventID {
+		return nil, ErrWrongEvent
+	}
+
+	checkIn, created, err := s.checkInRepo.CreateOnce(&models.CheckIn{
+		EventID:       eventID,
+		ParticipantID: participant.ID,
+		CheckedInAt:   time.Now(),
+		ScannedBy:     actor.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ticket := &Ticket{Participant: participant, Code: s.signer.Sign(participant), CheckIn: checkIn}
+	if !created {
+		return ticket, ErrAlreadyCheckedIn
+	}
+	s.notify(eventID)
+	return ticket, nil
+}
+
+func (s *ticketService) ListCheckIns(actor Actor, eventID string) ([]*models.CheckIn, error) {
+	if _, err := s.findManagedEvent(actor, eventID, PermissionView); err != nil {
+		return nil, err
+	}
+	return s.checkInRepo.FindByEventID(eventID)
+}
+
+// CheckInStats counts registrations and check-ins overall and per ticket type
+func (s *ticketService) CheckInStats(actor Actor, eventID string) (*CheckInStats, error) {
+	if _, err := s.findManagedEvent(actor, eventID, PermissionView); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.checkInRepo.CountsByTicketType(eventID)
+	if err != nil {
+		return nil, err
+	}
+	ticketTypes, err := s.ticketTypeRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		names[ticketType.ID] = ticketType.Name
+	}
+
+	stats := &CheckInStats{EventID: eventID, ByTicketType: make([]*TicketTypeCheckInRow, len(rows))}
+	for i, row := range rows {
+		stats.Registered += row.Registered
+		stats.CheckedIn += row.CheckedIn
+		stats.ByTicketType[i] = &TicketTypeCheckInRow{
+			TicketTypeID: row.TicketTypeID,
+			Registered:   row.Registered,
+			CheckedIn:    row.CheckedIn,
+		}
+		if row.TicketTypeID != nil {
+			stats.ByTicketType[i].TicketTypeName = names[*row.TicketTypeID]
+		}
+	}
+	stats.LastCheckInAt, err = s.checkInRepo.LastCheckInAt(eventID)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// SubscribeCheckIns returns a channel signalled after check-ins at the event and a function
+// ending the subscription. Signals are coalesced: a slow reader sees one signal for a burst.
+func (s *ticketService) SubscribeCheckIns(actor Actor, eventID string) (<-chan struct{}, func(), error) {
+	if _, err := s.findManagedEvent(actor, eventID, PermissionView); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	if s.subscribers[eventID] == nil {
+		s.subscribers[eventID] = make(map[chan struct{}]struct{})
+	}
+	s.subscribers[eventID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[eventID], ch)
+		if len(s.subscribers[eventID]) == 0 {
+			delete(s.subscribers, eventID)
+		}
+	}
+	return ch, unsubscribe, nil
+}
+
+// TicketManifest builds the signed manifest of an event's tickets, returning the manifest
+// document and its signature over exactly those bytes
+func (s *ticketService) TicketManifest(actor Actor, eventID string) ([]byte, string, error) {
+	event, err := s.findManagedEvent(actor, eventID, PermissionCheckIn)
+	if err != nil {
+		return nil, "", err
+	}
+	checkIns, err := s.checkInRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, "", err
+	}
+	checkedIn := make(map[string]time.Time, len(checkIns))
+	for _, checkIn := range checkIns {
+		checkedIn[checkIn.ParticipantID] = checkIn.CheckedInAt
+	}
+
+	now := time.Now().UTC()
+	manifest := &TicketManifest{
+		EventID:       event.ID,
+		EventTitle:    event.Title,
+		EventDate:     event.EventDate,
+		GeneratedAt:   now,
+		ExpiresAt:     now.Add(manifestTTL),
+		HashAlgorithm: "SHA-256",
+		Tickets:       []*ManifestTicket{},
+	}
+	err = s.participantRepo.StreamByEventID(eventID, func(participant *models.Participant) error {
+		ticket := &ManifestTicket{
+			CodeHash:      s.signer.CodeHash(s.signer.Sign(participant)),
+			ParticipantID: participant.ID,
+			FullName:      participant.FullName,
+			TicketTypeID:  participant.TicketTypeID,
+		}
+		if at, ok := checkedIn[participant.ID]; ok {
+			ticket.CheckedInAt = &at
+		}
+		manifest.Tickets = append(manifest.Tickets, ticket)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, s.signer.SignManifest(data), nil
+}
+
+// ManifestPublicKey returns the base64 Ed25519 key that verifies manifest signatures
+func (s *ticketService) ManifestPublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.signer.ManifestPublicKey())
+}
+
+// SyncCheckIns records the scans a kiosk made offline. Scans are applied earliest first and
+// every ticket keeps its earliest scan, the lower device ID breaking ties, so the outcome does
+// not depend on which kiosk syncs first. Uploading the same scans again is harmless.
+func (s *ticketService) SyncCheckIns(actor Actor, eventID, deviceID string, scans []OfflineScan) ([]*SyncResult, error) {
+	if _, err := s.findManagedEvent(actor, eventID, PermissionCheckIn); err != nil {
+		return nil, err
+	}
+
+	results := make([]*SyncResult, len(scans))
+	order := make([]int, len(scans))
+	for i := range scans {
+		// Match the precision the database keeps, so re-uploads compare equal
+		scans[i].ScannedAt = scans[i].ScannedAt.UTC().Truncate(time.Microsecond)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scans[order[a]].ScannedAt.Before(scans[order[b]].ScannedAt)
+	})
+
+	now := time.Now()
+	accepted := false
+	for _, i := range order {
+		result, err := s.syncScan(actor, eventID, deviceID, scans[i], now)
+		if err != nil {
+			return nil, err
+		}
+		accepted = accepted || result.Status == SyncAccepted
+		results[i] = result
+	}
+	if accepted {
+		s.notify(eventID)
+	}
+	return results, nil
+}
+
+func (s *ticketService) syncScan(actor Actor, eventID, deviceID string, scan OfflineScan, now time.Time) (*SyncResult, error) {
+	result := &SyncResult{Code: scan.Code, Status: SyncRejected}
+	if scan.ScannedAt.After(now.Add(maxClockSkew)) {
+		result.Reason = "scan time is in the future"
+		return result, nil
+	}
+
+	participant, err := s.verifyCode(scan.Code)
+	switch {
+	case errors.Is(err, ErrInvalidTicketCode), errors.Is(err, ErrTicketNotFound), errors.Is(err, ErrTicketRevoked):
+		result.Reason = err.Error()
+		return result, nil
+	case err != nil:
+		return nil, err
+	}
+	result.ParticipantID = participant.ID
+	if participant.EventID != eventID {
+		result.Reason = ErrWrongEvent.Error()
+		return result, nil
+	}
+
+	kept, err := s.checkInRepo.Reconcile(&models.CheckIn{
+		EventID:       eventID,
+		ParticipantID: participant.ID,
+		CheckedInAt:   scan.ScannedAt,
+		ScannedBy:     actor.UserID,
+		DeviceID:      deviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.CheckIn = kept
+	if kept.DeviceID == deviceID && kept.CheckedInAt.Equal(scan.ScannedAt) {
+		result.Status = SyncAccepted
+	} else {
+		result.Status = SyncDuplicate
+		result.Reason = ErrAlreadyCheckedIn.Error()
+	}
+	return result, nil
+}
+
+func (s *ticketService) notify(eventID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers[eventID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// verifyCode finds the participant a code was issued to and checks its signature
+func (s *ticketService) verifyCode(code string) (*models.Participant, error) {
+	participantID, err := s.signer.ParticipantID(code)
+	if err != nil {
+		return nil, err
+	}
+	participant, err := s.participantRepo.FindByID(participantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Tell forged codes apart from tickets of participants removed since they were issued
+		deleted, deletedErr := s.participantRepo.FindDeletedByID(participantID)
+		if deletedErr == nil && s.signer.Verify(deleted, code) {
+			return nil, ErrTicketRevoked
+		}
+		return nil, ErrTicketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !s.signer.Verify(participant, code) {
+		return nil, ErrInvalidTicketCode
+	}
+	return participant, nil
+}
+
+// findManagedEvent loads an event the actor holds permission on
+func (s *ticketService) findManagedEvent(actor Actor, eventID string, permission Permission) (*models.Event, error) {
+	return s.access.FindEvent(actor, eventID, permission)
+}
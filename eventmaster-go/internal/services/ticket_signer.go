@@ -0,0 +1,100 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"eventmaster-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTicketCode is returned for ticket codes that are malformed or not signed by us
+var ErrInvalidTicketCode = errors.New("invalid ticket code")
+
+// ticketSignatureSize is how many bytes of the HMAC are kept in a ticket code
+const ticketSignatureSize = 16
+
+// TicketSigner issues the codes printed on tickets and checks them at the door. A code is the
+// participant ID followed by an HMAC over the participant and its event, so codes cannot be
+// guessed from public participant IDs and a ticket only verifies for its own event.
+//
+// Offline kiosks never see the secret: they get manifests of code hashes signed with an
+// Ed25519 key derived from it, and verify them with the public key.
+type TicketSigner struct {
+	secret      []byte
+	manifestKey ed25519.PrivateKey
+}
+
+// NewTicketSigner creates a ticket signer using secret as the HMAC key
+func NewTicketSigner(secret string) *TicketSigner {
+	seed := sha256.Sum256([]byte("manifest\x00" + secret))
+	return &TicketSigner{
+		secret:      []byte(secret),
+		manifestKey: ed25519.NewKeyFromSeed(seed[:]),
+	}
+}
+
+// Sign returns the ticket code of a participant
+func (s *TicketSigner) Sign(participant *models.Participant) string {
+	id := uuid.MustParse(participant.ID)
+	return base64.RawURLEncoding.EncodeToString(id[:]) + "." +
+		base64.RawURLEncoding.EncodeToString(s.mac(participant.EventID, participant.ID))
+}
+
+// ParticipantID extracts the participant ID from a code without checking its signature
+func (s *TicketSigner) ParticipantID(code string) (string, error) {
+	idPart, _, ok := strings.Cut(strings.TrimSpace(code), ".")
+	if !ok {
+		return "", ErrInvalidTicketCode
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(idPart)
+	if err != nil {
+		return "", ErrInvalidTicketCode
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return "", ErrInvalidTicketCode
+	}
+	return id.String(), nil
+}
+
+// Verify reports whether code is the ticket code of the participant
+func (s *TicketSigner) Verify(participant *models.Participant, code string) bool {
+	_, sigPart, ok := strings.Cut(strings.TrimSpace(code), ".")
+	if !ok {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(sig, s.mac(participant.EventID, participant.ID))
+}
+
+// CodeHash is the digest of a ticket code listed in manifests; kiosks hash what they scan
+// and look the digest up
+func (s *TicketSigner) CodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SignManifest signs the bytes of a ticket manifest
+func (s *TicketSigner) SignManifest(manifest []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.manifestKey, manifest))
+}
+
+// ManifestPublicKey is the key kiosks verify manifest signatures with
+func (s *TicketSigner) ManifestPublicKey() ed25519.PublicKey {
+	return s.manifestKey.Public().(ed25519.PublicKey)
+}
+
+func (s *TicketSigner) mac(eventID, participantID string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte("ticket\x00" + eventID + "\x00" + participantID))
+	return h.Sum(nil)[:ticketSignatureSize]
+}
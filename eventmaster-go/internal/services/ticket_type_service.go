@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTicketTypeNotFound  = errors.New("ticket type not found")
+	ErrTicketTypeRequired  = errors.New("a ticket type must be selected")
+	ErrTicketNotOnSale     = errors.New("tickets of this type are not on sale")
+	ErrInvalidTicketType   = errors.New("invalid ticket type")
+	ErrInformationalTicket = errors.New("informational ticket types are managed by the importer")
+)
+
+var (
+	// ErrSoldOut is returned when fewer tickets are left than were requested
+	ErrSoldOut = repositories.ErrSoldOut
+	// ErrQuantityBelowSold is returned when a ticket quantity would drop below the tickets already sold
+	ErrQuantityBelowSold = repositories.ErrQuantityBelowSold
+	// ErrTicketTypeInUse is returned when deleting a ticket type that has sold tickets
+	ErrTicketTypeInUse = repositories.ErrTicketTypeInUse
+)
+
+// TicketTypeService manages the ticket types of events
+type TicketTypeService interface {
+	ListTicketTypes(eventID string) (*models.Event, []*models.TicketType, error)
+	CreateTicketType(actor Actor, eventID string, ticketType *models.TicketType) (*models.TicketType, error)
+	UpdateTicketType(actor Actor, eventID, id string, apply func(ticketType *models.TicketType)) (*models.TicketType, error)
+	DeleteTicketType(actor Actor, eventID, id string) error
+}
+
+type ticketTypeService struct {
+	ticketTypeRepo repositories.TicketTypeRepository
+	eventRepo      repositories.EventRepository
+	access         EventAccess
+}
+
+// NewTicketTypeService creates a new ticket type service
+func NewTicketTypeService(
+	ticketTypeRepo repositories.TicketTypeRepository,
+	eventRepo repositories.EventRepository,
+	access EventAccess,
+) TicketTypeService {
+	return &ticketTypeService{
+		ticketTypeRepo: ticketTypeRepo,
+		eventRepo:      eventRepo,
+		access:         access,
+	}
+}
+
+// ListTicketTypes returns the event with its ticket types in display order.
+// Callers decide whether the event is visible to the requester.
+func (s *ticketTypeService) ListTicketTypes(eventID string) (*models.Event, []*models.TicketType, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ticketTypes, err := s.ticketTypeRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return event, ticketTypes, nil
+}
+
+func (s *ticketTypeService) CreateTicketType(actor Actor, eventID string, ticketType *models.TicketType) (*models.TicketType, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+
+	ticketType.EventID = eventID
+	ticketType.Sold = 0
+	ticketType.Informational = false
+	if err := prepareTicketType(ticketType); err != nil {
+		return nil, err
+	}
+	if err := s.ticketTypeRepo.Create(ticketType); err != nil {
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+// UpdateTicketType edits a ticket type. The quantity can be lowered down to the tickets
+// already sold but no further.
+func (s *ticketTypeService) UpdateTicketType(actor Actor, eventID, id string, apply func(ticketType *models.TicketType)) (*models.TicketType, error) {
+	ticketType, err := s.findTicketType(actor, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apply(ticketType)
+	if err := prepareTicketType(ticketType); err != nil {
+		return nil, err
+	}
+	if err := s.ticketTypeRepo.UpdateDetails(ticketType); err != nil {
+		return nil, err
+	}
+	return s.ticketTypeRepo.FindForEvent(eventID, id)
+}
+
+// DeleteTicketType removes a ticket type nobody has bought yet
+func (s *ticketTypeService) DeleteTicketType(actor Actor, eventID, id string) error {
+	if _, err := s.findTicketType(actor, eventID, id); err != nil {
+		return err
+	}
+	return s.ticketTypeRepo.DeleteUnsold(id)
+}
+
+func (s *ticketTypeService) findManagedEvent(actor Actor, eventID string) (*models.Event, error) {
+	return s.access.FindEvent(actor, eventID, PermissionEdit)
+}
+
+// findTicketType loads a ticket type the actor may edit; imported informational types are read-only
+func (s *ticketTypeService) findTicketType(actor Actor, eventID, id string) (*models.TicketType, error) {
+	if _, err := s.findManagedEvent(actor, eventID); err != nil {
+		return nil, err
+	}
+	ticketType, err := s.ticketTypeRepo.FindForEvent(eventID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTicketTypeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ticketType.Informational {
+		return nil, ErrInformationalTicket
+	}
+	return ticketType, nil
+}
+
+// prepareTicketType normalizes a ticket type and checks the rules that span several fields
+func prepareTicketType(ticketType *models.TicketType) error {
+	ticketType.Name = strings.TrimSpace(ticketType.Name)
+	ticketType.Currency = strings.ToUpper(ticketType.Currency)
+	if ticketType.Currency == "" {
+		ticketType.Currency = models.DefaultCurrency
+	}
+	if ticketType.MinPerOrder == 0 {
+		ticketType.MinPerOrder = 1
+	}
+	if ticketType.MaxPerOrder == 0 {
+		ticketType.MaxPerOrder = 10
+	}
+
+	switch {
+	case ticketType.PriceCents < 0:
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidTicketType)
+	case ticketType.MinPerOrder > ticketType.MaxPerOrder:
+		return fmt.Errorf("%w: minPerOrder cannot exceed maxPerOrder", ErrInvalidTicketType)
+	case ticketType.SalesStart != nil && ticketType.SalesEnd != nil && !ticketType.SalesEnd.After(*ticketType.SalesStart):
+		return fmt.Errorf("%w: sales must end after they start", ErrInvalidTicketType)
+	case ticketType.Quantity != nil && *ticketType.Quantity < ticketType.Sold:
+		return ErrQuantityBelowSold
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"math/rand"
 	"net/http"
 	"strconv"
@@ -19,6 +20,9 @@ import (
 
 type TicketmasterService struct {
 	eventRepo          repositories.EventRepository
+	categoryRepo       repositories.CategoryRepository
+	venueRepo          repositories.VenueRepository
+	organizerRepo      repositories.OrganizerRepository
 	imageService       ImageService
 	participantService ParticipantService
 	apiKey             string
@@ -27,6 +31,9 @@ type TicketmasterService struct {
 
 func NewTicketmasterService(
 	eventRepo repositories.EventRepository,
+	categoryRepo repositories.CategoryRepository,
+	venueRepo repositories.VenueRepository,
+	organizerRepo repositories.OrganizerRepository,
 	imageService ImageService,
 	participantService ParticipantService,
 	apiKey string,
@@ -34,6 +41,9 @@ func NewTicketmasterService(
 ) *TicketmasterService {
 	return &TicketmasterService{
 		eventRepo:          eventRepo,
+		categoryRepo:       categoryRepo,
+		venueRepo:          venueRepo,
+		organizerRepo:      organizerRepo,
 		imageService:       imageService,
 		participantService: participantService,
 		apiKey:             apiKey,
@@ -54,7 +64,12 @@ type TicketmasterEvent struct {
 	} `json:"images"`
 	Embedded struct {
 		Venues []struct {
-			Name string `json:"name"`
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Timezone string `json:"timezone"`
+			Address  struct {
+				Line1 string `json:"line1"`
+			} `json:"address"`
 			City struct {
 				Name string `json:"name"`
 			} `json:"city"`
@@ -95,6 +110,12 @@ type TicketmasterEvent struct {
 			Name string `json:"name"`
 		} `json:"subGenre"`
 	} `json:"classifications"`
+	PriceRanges []struct {
+		Type     string  `json:"type"`
+		Currency string  `json:"currency"`
+		Min      float64 `json:"min"`
+		Max      float64 `json:"max"`
+	} `json:"priceRanges"`
 }
 
 type TicketmasterResponse struct {
@@ -143,14 +164,19 @@ func (s *TicketmasterService) FetchAndSaveEvents() error {
 
 	var (
 		newEvents     int
+		updatedEvents int
 		skippedEvents int
 	)
 	for _, tmEvent := range data.Embedded.Events {
 		// Check if event already exists
 		existing, _ := s.eventRepo.FindByExternalID(tmEvent.ID)
 		if existing != nil {
-			skippedEvents++
-			continue // Skip if event already exists
+			if s.syncStatus(existing, tmEvent) {
+				updatedEvents++
+			} else {
+				skippedEvents++
+			}
+			continue // Existing events only have their status refreshed
 		}
 
 		event := s.mapToEvent(tmEvent)
@@ -172,6 +198,11 @@ func (s *TicketmasterService) FetchAndSaveEvents() error {
 			}
 		}
 
+		event.Categories = s.resolveCategories(tmEvent)
+		event.VenueID = s.resolveVenue(tmEvent)
+		event.OrganizerID = s.resolveOrganizer(event.Organizer)
+		event.TicketTypes = mapPriceRanges(tmEvent)
+
 		err := s.eventRepo.Create(event)
 		if err != nil {
 			// Log error but continue with next event
@@ -188,11 +219,39 @@ func (s *TicketmasterService) FetchAndSaveEvents() error {
 		newEvents++
 	}
 
- 	log.Printf("Ticketmaster fetch completed: total=%d new=%d skipped=%d", len(data.Embedded.Events), newEvents, skippedEvents)
+ 	log.Printf("Ticketmaster fetch completed: total=%d new=%d updated=%d skipped=%d", len(data.Embedded.Events), newEvents, updatedEvents, skippedEvents)
 
 	return nil
 }
 
+// mapPriceRanges turns Ticketmaster price ranges into informational ticket types. Tickets are
+// sold on Ticketmaster, so they only show the prices and cannot be registered with.
+func mapPriceRanges(tmEvent TicketmasterEvent) []models.TicketType {
+	ticketTypes := make([]models.TicketType, 0, len(tmEvent.PriceRanges))
+	for i, priceRange := range tmEvent.PriceRanges {
+		name := "Standard"
+		if priceRange.Type != "" {
+			name = strings.ToUpper(priceRange.Type[:1]) + priceRange.Type[1:]
+		}
+		ticketType := models.TicketType{
+			Name:          name,
+			PriceCents:    int64(math.Round(priceRange.Min * 100)),
+			Currency:      strings.ToUpper(priceRange.Currency),
+			Informational: true,
+			Position:      i,
+		}
+		if priceRange.Max > priceRange.Min {
+			maxCents := int64(math.Round(priceRange.Max * 100))
+			ticketType.MaxPriceCents = &maxCents
+		}
+		if len(ticketType.Currency) != 3 {
+			ticketType.Currency = models.DefaultCurrency
+		}
+		ticketTypes = append(ticketTypes, ticketType)
+	}
+	return ticketTypes
+}
+
 func (s *TicketmasterService) mapToEvent(tmEvent TicketmasterEvent) *models.Event {
 	event := &models.Event{
 		Title:       tmEvent.Name,
@@ -202,6 +261,18 @@ func (s *TicketmasterService) mapToEvent(tmEvent TicketmasterEvent) *models.Even
 		IsExternal:  true,
 		ExternalID:  tmEvent.ID,
 	}
+	applyTicketmasterStatus(event, tmEvent.Dates.Status.Code)
+
+	// Resolve the venue time zone so local dates are interpreted where the event happens
+	loc := time.UTC
+	if len(tmEvent.Embedded.Venues) > 0 && tmEvent.Embedded.Venues[0].Timezone != "" {
+		if venueLoc, err := time.LoadLocation(tmEvent.Embedded.Venues[0].Timezone); err == nil {
+			loc = venueLoc
+			event.TimeZone = tmEvent.Embedded.Venues[0].Timezone
+		} else {
+			log.Printf("Ticketmaster unknown venue time zone: id=%s tz=%s", tmEvent.ID, tmEvent.Embedded.Venues[0].Timezone)
+		}
+	}
 
 	// Parse event date with fallbacks to local date/time information
 	var eventDate time.Time
@@ -218,7 +289,7 @@ func (s *TicketmasterService) mapToEvent(tmEvent TicketmasterEvent) *models.Even
 			layout = "2006-01-02 15:04:05"
 			value = fmt.Sprintf("%s %s", tmEvent.Dates.Start.LocalDate, tmEvent.Dates.Start.LocalTime)
 		}
-		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+		if parsed, err := time.ParseInLocation(layout, value, loc); err == nil {
 			eventDate = parsed
 		}
 	}
@@ -255,6 +326,150 @@ func (s *TicketmasterService) mapToEvent(tmEvent TicketmasterEvent) *models.Even
 	return event
 }
 
+// resolveCategories maps segment/genre/subGenre classifications onto the category tree
+func (s *TicketmasterService) resolveCategories(tmEvent TicketmasterEvent) []models.Category {
+	seen := make(map[string]struct{})
+	categories := make([]models.Category, 0, len(tmEvent.Classifications))
+
+	for _, class := range tmEvent.Classifications {
+		path := make([]string, 0, 3)
+		for _, name := range []string{class.Segment.Name, class.Genre.Name, class.SubGenre.Name} {
+			if name == "" || strings.EqualFold(name, "Undefined") {
+				break
+			}
+			path = append(path, name)
+		}
+		if len(path) == 0 {
+			continue
+		}
+
+		category, err := s.categoryRepo.FindOrCreatePath(path)
+		if err != nil {
+			log.Printf("Ticketmaster category mapping failed: id=%s err=%v", tmEvent.ID, err)
+			continue
+		}
+		if category == nil {
+			continue
+		}
+		if _, exists := seen[category.ID]; exists {
+			continue
+		}
+		seen[category.ID] = struct{}{}
+		categories = append(categories, *category)
+	}
+
+	return categories
+}
+
+// resolveVenue returns the ID of the event's venue, creating the venue the first time its
+// Ticketmaster venue ID is seen so every event at the same place shares one record
+func (s *TicketmasterService) resolveVenue(tmEvent TicketmasterEvent) *string {
+	if len(tmEvent.Embedded.Venues) == 0 || s.systemUserID == "" {
+		return nil
+	}
+	tmVenue := tmEvent.Embedded.Venues[0]
+	if tmVenue.ID == "" {
+		return nil
+	}
+
+	externalID := tmVenue.ID
+	venue := &models.Venue{
+		Name:       tmVenue.Name,
+		Address:    tmVenue.Address.Line1,
+		City:       tmVenue.City.Name,
+		Country:    tmVenue.Country.Name,
+		ExternalID: &externalID,
+		UserID:     s.systemUserID,
+	}
+	if _, err := time.LoadLocation(tmVenue.Timezone); tmVenue.Timezone != "" && err == nil {
+		venue.TimeZone = tmVenue.Timezone
+	}
+	if lat, err := strconv.ParseFloat(tmVenue.Location.Latitude, 64); err == nil {
+		venue.Latitude = lat
+	}
+	if lng, err := strconv.ParseFloat(tmVenue.Location.Longitude, 64); err == nil {
+		venue.Longitude = lng
+	}
+	if venue.Name == "" {
+		venue.Name = venue.City
+	}
+
+	saved, err := s.venueRepo.FindOrCreateByExternalID(venue)
+	if err != nil {
+		log.Printf("Ticketmaster venue mapping failed: id=%s venue=%s err=%v", tmEvent.ID, tmVenue.ID, err)
+		return nil
+	}
+	return &saved.ID
+}
+
+// resolveOrganizer returns the ID of the system user's organizer profile named name,
+// creating it the first time the name is seen
+func (s *TicketmasterService) resolveOrganizer(name string) *string {
+	if name == "" || s.systemUserID == "" {
+		return nil
+	}
+
+	profile, err := s.organizerRepo.FindOrCreateManaged(name, s.systemUserID)
+	if err != nil {
+		log.Printf("Ticketmaster organizer mapping failed: organizer=%s err=%v", name, err)
+		return nil
+	}
+	return &profile.ID
+}
+
+// syncStatus brings the lifecycle state of a previously imported event in line with Ticketmaster,
+// linking the venue and organizer of events imported before those were tracked
+func (s *TicketmasterService) syncStatus(event *models.Event, tmEvent TicketmasterEvent) bool {
+	previous := event.Status
+	applyTicketmasterStatus(event, tmEvent.Dates.Status.Code)
+
+	linked := false
+	if event.VenueID == nil {
+		if venueID := s.resolveVenue(tmEvent); venueID != nil {
+			event.VenueID = venueID
+			linked = true
+		}
+	}
+	if event.OrganizerID == nil {
+		if organizerID := s.resolveOrganizer(event.Organizer); organizerID != nil {
+			event.OrganizerID = organizerID
+			linked = true
+		}
+	}
+	if event.Status == previous && !linked {
+		return false
+	}
+
+	if err := s.eventRepo.Update(event); err != nil {
+		log.Printf("Ticketmaster status update failed: id=%s err=%v", tmEvent.ID, err)
+		return false
+	}
+	return true
+}
+
+// applyTicketmasterStatus maps a Ticketmaster dates.status.code onto the event lifecycle
+func applyTicketmasterStatus(event *models.Event, code string) {
+	now := time.Now()
+	switch strings.ToLower(code) {
+	case "cancelled", "canceled":
+		event.Status = models.EventStatusCancelled
+		event.StatusReason = "Cancelled by Ticketmaster"
+		if event.CancelledAt == nil {
+			event.CancelledAt = &now
+		}
+	case "postponed":
+		event.Status = models.EventStatusPostponed
+		event.StatusReason = "Postponed by Ticketmaster"
+	default:
+		// onsale, offsale and rescheduled events are live with a known date
+		event.Status = models.EventStatusPublished
+		event.StatusReason = ""
+		if event.PublishedAt == nil {
+			event.PublishedAt = &now
+		}
+	}
+}
+
 // StartScheduler triggers Ticketmaster fetch on the provided interval until the context is cancelled.
 func (s *TicketmasterService) StartScheduler(ctx context.Context, interval time.Duration) {
 	go func() {
@@ -351,9 +566,9 @@ func generateEventDescription(event TicketmasterEvent) string {
 		venueAddress = fmt.Sprintf("%s, %s", venue.City.Name, venue.Country.Name)
 	}
 
-	builder.WriteString(fmt.Sprintf("**Event Type:** %s<br />", eventType))
-	builder.WriteString(fmt.Sprintf("**Date and Time:** %s at %s<br />", localDate, localTime))
-	builder.WriteString(fmt.Sprintf("**Event Status:** %s<br />", status))
+	builder.WriteString(fmt.Sprintf("**Event Type:** %s\n", eventType))
+	builder.WriteString(fmt.Sprintf("**Date and Time:** %s at %s\n", localDate, localTime))
+	builder.WriteString(fmt.Sprintf("**Event Status:** %s\n", status))
 	if venueName != "" {
 		builder.WriteString(fmt.Sprintf("**Venue:** %s, %s", venueName, venueAddress))
 	}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotInTrash         = errors.New("item not found in trash")
+	ErrParentEventDeleted = errors.New("the participant's event is in the trash; restore the event instead")
+)
+
+// TrashService lists, restores and purges soft-deleted events and participants
+type TrashService interface {
+	ListDeletedEvents(actor Actor) ([]*models.Event, error)
+	RestoreEvent(actor Actor, id string) (*models.Event, error)
+	PurgeEvent(actor Actor, id string) error
+	ListDeletedParticipants(actor Actor) ([]*models.Participant, error)
+	RestoreParticipant(actor Actor, id string) (*models.Participant, error)
+	PurgeParticipant(actor Actor, id string) error
+	PurgeExpired(before time.Time) (events int, participants int64, err error)
+	StartRetentionJob(ctx context.Context, interval time.Duration)
+}
+
+type trashService struct {
+	eventRepo       repositories.EventRepository
+	participantRepo repositories.ParticipantRepository
+	retention       time.Duration
+	access          EventAccess
+}
+
+// NewTrashService creates a new trash service; items older than retention are purged by the retention job
+func NewTrashService(
+	eventRepo repositories.EventRepository,
+	participantRepo repositories.ParticipantRepository,
+	retention time.Duration,
+	access EventAccess,
+) TrashService {
+	return &trashService{
+		eventRepo:       eventRepo,
+		participantRepo: participantRepo,
+		retention:       retention,
+		access:          access,
+	}
+}
+
+func (s *trashService) ListDeletedEvents(actor Actor) ([]*models.Event, error) {
+	return s.eventRepo.FindDeleted(actor.ownerScope())
+}
+
+func (s *trashService) RestoreEvent(actor Actor, id string) (*models.Event, error) {
+	if _, err := s.findDeletedEvent(actor, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Restore(id); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.FindWithImages(id)
+}
+
+func (s *trashService) PurgeEvent(actor Actor, id string) error {
+	if _, err := s.findDeletedEvent(actor, id); err != nil {
+		return err
+	}
+
+	return s.eventRepo.Purge(id)
+}
+
+func (s *trashService) ListDeletedParticipants(actor Actor) ([]*models.Participant, error) {
+	// Editors may restore participants, so they see the ones trashed from their events
+	return s.participantRepo.FindDeleted(actor.ownerScope(), models.CollaboratorRole(PermissionEdit))
+}
+
+func (s *trashService) RestoreParticipant(actor Actor, id string) (*models.Participant, error) {
+	if _, err := s.findDeletedParticipant(actor, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.participantRepo.Restore(id); err != nil {
+		return nil, err
+	}
+
+	return s.participantRepo.FindByID(id)
+}
+
+func (s *trashService) PurgeParticipant(actor Actor, id string) error {
+	if _, err := s.findDeletedParticipant(actor, id); err != nil {
+		return err
+	}
+
+	return s.participantRepo.Purge(id)
+}
+
+// PurgeExpired permanently removes everything that has been in the trash since before the cutoff
+func (s *trashService) PurgeExpired(before time.Time) (int, int64, error) {
+	eventIDs, err := s.eventRepo.FindDeletedBefore(before)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	purgedEvents := 0
+	for _, id := range eventIDs {
+		if err := s.eventRepo.Purge(id); err != nil {
+			log.Printf("Trash purge failed: event=%s err=%v", id, err)
+			continue
+		}
+		purgedEvents++
+	}
+
+	purgedParticipants, err := s.participantRepo.PurgeDeletedBefore(before)
+	if err != nil {
+		return purgedEvents, 0, err
+	}
+
+	return purgedEvents, purgedParticipants, nil
+}
+
+// StartRetentionJob purges expired trash on the provided interval until the context is cancelled.
+func (s *trashService) StartRetentionJob(ctx context.Context, interval time.Duration) {
+	if s.retention <= 0 {
+		log.Println("Trash retention disabled; soft-deleted items are kept indefinitely")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				events, participants, err := s.PurgeExpired(time.Now().Add(-s.retention))
+				if err != nil {
+					log.Printf("Trash retention purge error: %v", err)
+					continue
+				}
+				if events > 0 || participants > 0 {
+					log.Printf("Trash retention purge completed: events=%d participants=%d", events, participants)
+				}
+			}
+		}
+	}()
+}
+
+func (s *trashService) findDeletedEvent(actor Actor, id string) (*models.Event, error) {
+	event, err := s.eventRepo.FindDeletedByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.access.Authorize(actor, event, PermissionManage); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (s *trashService) findDeletedParticipant(actor Actor, id string) (*models.Participant, error) {
+	participant, err := s.participantRepo.FindDeletedByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.FindByID(participant.EventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrParentEventDeleted
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.access.Authorize(actor, event, PermissionEdit); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+
+	"eventmaster-go/internal/models"
+	"eventmaster-go/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVenueNotFound = errors.New("venue not found")
+	ErrVenueInUse    = errors.New("venue is linked to events")
+)
+
+const (
+	defaultVenueSearchLimit = 20
+	maxVenueSearchLimit     = 50
+)
+
+// VenueService manages the shared venue directory
+type VenueService interface {
+	SearchVenues(term, city string, limit int) ([]*models.Venue, error)
+	GetVenue(id string) (*models.Venue, error)
+	CreateVenue(venue *models.Venue, userID string) (*models.Venue, error)
+	UpdateVenue(actor Actor, id string, apply func(venue *models.Venue)) (*models.Venue, error)
+	DeleteVenue(actor Actor, id string) error
+}
+
+type venueService struct {
+	venueRepo repositories.VenueRepository
+}
+
+// NewVenueService creates a new venue service
+func NewVenueService(venueRepo repositories.VenueRepository) VenueService {
+	return &venueService{
+		venueRepo: venueRepo,
+	}
+}
+
+// SearchVenues backs the venue picker; limit is clamped to a sensible page size
+func (s *venueService) SearchVenues(term, city string, limit int) ([]*models.Venue, error) {
+	if limit < 1 {
+		limit = defaultVenueSearchLimit
+	}
+	if limit > maxVenueSearchLimit {
+		limit = maxVenueSearchLimit
+	}
+	return s.venueRepo.Search(term, city, limit)
+}
+
+func (s *venueService) GetVenue(id string) (*models.Venue, error) {
+	venue, err := s.venueRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVenueNotFound
+	}
+	return venue, err
+}
+
+func (s *venueService) CreateVenue(venue *models.Venue, userID string) (*models.Venue, error) {
+	venue.UserID = userID
+	// External IDs are reserved for venues the importer creates
+	venue.ExternalID = nil
+	if err := s.venueRepo.Create(venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// UpdateVenue lets the creator or an admin change a venue. Events keep the location
+// text copied when they were linked, but their ETags cover the venue they embed, so
+// clients refetch them after the edit.
+func (s *venueService) UpdateVenue(actor Actor, id string, apply func(venue *models.Venue)) (*models.Venue, error) {
+	venue, err := s.GetVenue(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage(venue.UserID) {
+		return nil, ErrForbidden
+	}
+
+	apply(venue)
+	if err := s.venueRepo.Update(venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// DeleteVenue removes a venue no event links to
+func (s *venueService) DeleteVenue(actor Actor, id string) error {
+	venue, err := s.GetVenue(id)
+	if err != nil {
+		return err
+	}
+	if !actor.CanManage(venue.UserID) {
+		return ErrForbidden
+	}
+
+	count, err := s.venueRepo.CountEvents(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrVenueInUse
+	}
+
+	return s.venueRepo.Delete(id)
+}
@@ -0,0 +1,385 @@
+// Package badge lays out printable tickets and attendee badges as A4 PDF documents. A
+// Template divides the page into a grid of cards; a Sheet fills the cards in order and
+// starts a new page whenever the grid is full.
+package badge
+
+import (
+	_ "embed"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"eventmaster-go/pkg/qr"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	// ContentType is the MIME type served for badge documents
+	ContentType = "application/pdf"
+
+	// TicketTemplate is the one-ticket-per-page template used for single tickets
+	TicketTemplate = "ticket"
+	// DefaultTemplate is the badge sheet used when none is asked for
+	DefaultTemplate = "badge-2x4"
+
+	pageWidth  = 210.0
+	pageHeight = 297.0
+	// ptToMM converts font sizes, which are in points, to the page unit
+	ptToMM = 25.4 / 72
+	// minFontSize is how far text is shrunk to fit before it is truncated instead
+	minFontSize = 5.0
+	dateFormat  = "Mon 2 Jan 2006, 15:04 MST"
+)
+
+// The text is set in DejaVu Sans Condensed, a UTF-8 TrueType font covering Latin, Greek and
+// Cyrillic, so attendee names print as written. UseFontFiles swaps in a font covering other
+// scripts, such as CJK.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	regularFont []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	boldFont []byte
+
+	fontMu sync.RWMutex
+)
+
+const textFamily = "Text"
+
+// UseFontFiles replaces the embedded font with the TrueType files at regular and bold. An
+// empty bold path uses the regular font for bold text too.
+func UseFontFiles(regular, bold string) error {
+	regularBytes, err := os.ReadFile(regular)
+	if err != nil {
+		return err
+	}
+	boldBytes := regularBytes
+	if bold != "" {
+		if boldBytes, err = os.ReadFile(bold); err != nil {
+			return err
+		}
+	}
+
+	fontMu.Lock()
+	defer fontMu.Unlock()
+	regularFont, boldFont = regularBytes, boldBytes
+	return nil
+}
+
+var (
+	// ErrUnknownTemplate is returned for template names that are not registered
+	ErrUnknownTemplate = errors.New("unknown badge template")
+	// ErrNoCards is returned when a sheet is written before any card was added
+	ErrNoCards = errors.New("nothing to print")
+)
+
+// Template describes how cards are laid out on an A4 portrait page. Margin and Gutter are
+// in millimetres; the cell size follows from them and the grid.
+type Template struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Columns     int     `json:"columns"`
+	Rows        int     `json:"rows"`
+	Margin      float64 `json:"marginMm"`
+	Gutter      float64 `json:"gutterMm"`
+	// ShowDetails prints the event date and location under the event title
+	ShowDetails bool `json:"showDetails"`
+	// CutMarks outlines every card with a dashed line to cut along
+	CutMarks bool `json:"cutMarks"`
+}
+
+// PerPage is the number of cards on a page
+func (t Template) PerPage() int {
+	return t.Columns * t.Rows
+}
+
+// CardSize returns the width and height of a card in millimetres
+func (t Template) CardSize() (float64, float64) {
+	width := (pageWidth - 2*t.Margin - float64(t.Columns-1)*t.Gutter) / float64(t.Columns)
+	height := (pageHeight - 2*t.Margin - float64(t.Rows-1)*t.Gutter) / float64(t.Rows)
+	return width, height
+}
+
+var templates = map[string]Template{
+	TicketTemplate: {
+		Name:        TicketTemplate,
+		Description: "One ticket per page with a large QR code",
+		Columns:     1,
+		Rows:        1,
+		Margin:      20,
+		ShowDetails: true,
+	},
+	"badge-2x4": {
+		Name:        "badge-2x4",
+		Description: "Eight name badges per page, 93 x 66 mm",
+		Columns:     2,
+		Rows:        4,
+		Margin:      10,
+		Gutter:      4,
+		ShowDetails: true,
+		CutMarks:    true,
+	},
+	"badge-2x5": {
+		Name:        "badge-2x5",
+		Description: "Ten name badges per page, 93 x 52 mm, for standard badge holders",
+		Columns:     2,
+		Rows:        5,
+		Margin:      10,
+		Gutter:      4,
+		ShowDetails: true,
+		CutMarks:    true,
+	},
+	"label-3x8": {
+		Name:        "label-3x8",
+		Description: "Twenty-four adhesive labels per page, 63 x 33 mm, without event details",
+		Columns:     3,
+		Rows:        8,
+		Margin:      8,
+		Gutter:      2.5,
+	},
+}
+
+// Templates lists the registered templates by name
+func Templates() []Template {
+	list := make([]Template, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Lookup finds a template by name, returning the default badge sheet for an empty name
+func Lookup(name string) (Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	t, ok := templates[name]
+	if !ok {
+		return Template{}, ErrUnknownTemplate
+	}
+	return t, nil
+}
+
+// Card is what gets printed for one attendee. Date is shown in its own location and left
+// out when zero; the QR code is left out when Code is empty.
+type Card struct {
+	EventTitle string
+	Date       time.Time
+	Location   string
+	Name       string
+	Label      string
+	Code       string
+}
+
+// Sheet is a PDF document being filled with cards
+type Sheet struct {
+	template Template
+	pdf      *fpdf.Fpdf
+	cards    int
+}
+
+// NewSheet starts a document laid out by t
+func NewSheet(t Template, title string) *Sheet {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("EventMaster", false)
+
+	fontMu.RLock()
+	pdf.AddUTF8FontFromBytes(textFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(textFamily, "B", boldFont)
+	fontMu.RUnlock()
+
+	return &Sheet{
+		template: t,
+		pdf:      pdf,
+	}
+}
+
+// Add places a card in the next free cell, starting a new page when the last one is full
+func (s *Sheet) Add(card Card) error {
+	perPage := s.template.PerPage()
+	slot := s.cards % perPage
+	if slot == 0 {
+		s.pdf.AddPage()
+	}
+	s.cards++
+
+	width, height := s.template.CardSize()
+	x := s.template.Margin + float64(slot%s.template.Columns)*(width+s.template.Gutter)
+	y := s.template.Margin + float64(slot/s.template.Columns)*(height+s.template.Gutter)
+	if s.template.CutMarks {
+		s.pdf.SetDrawColor(170, 170, 170)
+		s.pdf.SetLineWidth(0.2)
+		s.pdf.SetDashPattern([]float64{1.5, 1.5}, 0)
+		s.pdf.Rect(x, y, width, height, "D")
+		s.pdf.SetDashPattern(nil, 0)
+	}
+
+	var modules [][]bool
+	if card.Code != "" {
+		var err error
+		if modules, err = qr.Modules(card.Code); err != nil {
+			return err
+		}
+	}
+	s.pdf.SetTextColor(0, 0, 0)
+	if height > width {
+		s.drawPortrait(card, modules, x, y, width, height)
+	} else {
+		s.drawLandscape(card, modules, x, y, width, height)
+	}
+	return s.pdf.Error()
+}
+
+// Count is the number of cards added so far
+func (s *Sheet) Count() int {
+	return s.cards
+}
+
+// Write finishes the document and writes it to w
+func (s *Sheet) Write(w io.Writer) error {
+	if s.cards == 0 {
+		return ErrNoCards
+	}
+	return s.pdf.Output(w)
+}
+
+// drawLandscape prints the text on the left of a wide card and the QR code on its right.
+// Font sizes scale with the card height so the same layout serves badges and labels.
+func (s *Sheet) drawLandscape(card Card, modules [][]bool, x, y, width, height float64) {
+	padding := math.Max(2, height*0.08)
+	scale := height / 66
+
+	textWidth := width - 2*padding
+	if modules != nil {
+		qrSize := math.Min(height-2*padding, width*0.4)
+		s.drawQR(modules, x+width-padding-qrSize, y+(height-qrSize)/2, qrSize)
+		textWidth -= qrSize + padding
+	}
+
+	cursor := y + padding
+	left := x + padding
+	cursor = s.line(card.EventTitle, text("B", 11*scale), left, cursor, textWidth, false)
+	if s.template.ShowDetails {
+		cursor = s.line(formatDate(card.Date), text("", 8*scale), left, cursor, textWidth, false)
+		cursor = s.line(card.Location, text("", 8*scale), left, cursor, textWidth, false)
+	}
+
+	// The name sits in the middle of the card, or just below the header on crowded ones
+	cursor = math.Max(cursor+2*scale, y+height*0.45)
+	cursor = s.line(card.Name, text("B", 20*scale), left, cursor, textWidth, false)
+	s.line(card.Label, text("", 9*scale), left, cursor, textWidth, false)
+}
+
+// drawPortrait prints a tall card centred top to bottom: header, name and a large QR code
+// with the code spelled out underneath for manual entry
+func (s *Sheet) drawPortrait(card Card, modules [][]bool, x, y, width, height float64) {
+	padding := math.Max(4, width*0.05)
+	textWidth := width - 2*padding
+	left := x + padding
+
+	cursor := y + padding
+	cursor = s.line(card.EventTitle, text("B", 24), left, cursor, textWidth, true)
+	if s.template.ShowDetails {
+		cursor = s.line(formatDate(card.Date), text("", 13), left, cursor, textWidth, true)
+		cursor = s.line(card.Location, text("", 13), left, cursor, textWidth, true)
+	}
+	cursor += 10
+	cursor = s.line(card.Name, text("B", 30), left, cursor, textWidth, true)
+	cursor = s.line(card.Label, text("", 14), left, cursor, textWidth, true)
+
+	if modules == nil {
+		return
+	}
+	codeHeight := 9 * ptToMM * 1.4
+	qrSize := math.Min(textWidth*0.7, y+height-padding-codeHeight-cursor-8)
+	if qrSize <= 0 {
+		return
+	}
+	s.drawQR(modules, x+(width-qrSize)/2, cursor+6, qrSize)
+	s.pdf.SetTextColor(90, 90, 90)
+	s.line(card.Code, font{family: "Courier", size: 9}, left, cursor+6+qrSize+2, textWidth, true)
+}
+
+// font selects a font family and style; size is in points
+type font struct {
+	family string
+	style  string
+	size   float64
+}
+
+func text(style string, size float64) font {
+	return font{family: textFamily, style: style, size: size}
+}
+
+// line prints text on one line starting at top, shrinking it down to minFontSize and then
+// truncating it to fit width, and returns where the next line starts. Empty text takes no
+// room.
+func (s *Sheet) line(text string, f font, left, top, width float64, center bool) float64 {
+	if text == "" {
+		return top
+	}
+
+	size := math.Max(f.size, minFontSize)
+	s.pdf.SetFont(f.family, f.style, size)
+	for size > minFontSize && s.pdf.GetStringWidth(text) > width {
+		size = math.Max(minFontSize, size-0.5)
+		s.pdf.SetFontSize(size)
+	}
+	text = truncate(s.pdf, text, width)
+
+	x := left
+	if center {
+		x += (width - s.pdf.GetStringWidth(text)) / 2
+	}
+	lineHeight := size * ptToMM
+	s.pdf.Text(x, top+lineHeight, text)
+	return top + lineHeight*1.4
+}
+
+// truncate shortens text with an ellipsis until it fits width in the current font
+func truncate(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	const ellipsis = "…"
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+ellipsis) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + ellipsis
+}
+
+// drawQR fills the dark modules as rectangles, merging runs on a row like qr.SVG does, so the
+// code stays sharp at any print size
+func (s *Sheet) drawQR(modules [][]bool, x, y, size float64) {
+	module := size / float64(len(modules))
+	s.pdf.SetFillColor(0, 0, 0)
+	for row, cells := range modules {
+		for col := 0; col < len(cells); col++ {
+			if !cells[col] {
+				continue
+			}
+			start := col
+			for col < len(cells) && cells[col] {
+				col++
+			}
+			// Overlap rows slightly so viewers do not render hairlines between them
+			s.pdf.Rect(x+float64(start)*module, y+float64(row)*module, float64(col-start)*module, module*1.02, "F")
+		}
+	}
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(dateFormat)
+}
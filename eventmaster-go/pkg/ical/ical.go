@@ -0,0 +1,189 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ContentType is the MIME type served for calendar documents
+	ContentType = "text/calendar; charset=utf-8"
+
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// Calendar represents a VCALENDAR object
+type Calendar struct {
+	ProdID          string
+	Name            string
+	Description     string
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event represents a VEVENT component
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time
+	Created      time.Time
+	LastModified time.Time
+	Location     string
+	URL          string
+	Latitude     float64
+	Longitude    float64
+	HasGeo       bool
+	Status       string
+	Sequence     int
+	Categories   []string
+}
+
+// Encode renders the calendar as an RFC 5545 document with CRLF line endings
+func (c *Calendar) Encode() string {
+	w := &writer{}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", EscapeText(c.Name))
+		w.line("NAME", EscapeText(c.Name))
+	}
+	if c.Description != "" {
+		w.line("X-WR-CALDESC", EscapeText(c.Description))
+	}
+	if c.RefreshInterval > 0 {
+		duration := formatDuration(c.RefreshInterval)
+		w.line("REFRESH-INTERVAL;VALUE=DURATION", duration)
+		w.line("X-PUBLISHED-TTL", duration)
+	}
+
+	stamp := time.Now()
+	for i := range c.Events {
+		c.Events[i].encode(w, stamp)
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.String()
+}
+
+func (e *Event) encode(w *writer, stamp time.Time) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", EscapeText(e.UID))
+	if !e.LastModified.IsZero() {
+		stamp = e.LastModified
+	}
+	w.line("DTSTAMP", FormatDateTime(stamp))
+	w.line("DTSTART", FormatDateTime(e.Start))
+	if !e.End.IsZero() && e.End.After(e.Start) {
+		w.line("DTEND", FormatDateTime(e.End))
+	}
+	if !e.Created.IsZero() {
+		w.line("CREATED", FormatDateTime(e.Created))
+	}
+	if !e.LastModified.IsZero() {
+		w.line("LAST-MODIFIED", FormatDateTime(e.LastModified))
+	}
+	w.line("SEQUENCE", fmt.Sprintf("%d", e.Sequence))
+	w.line("SUMMARY", EscapeText(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION", EscapeText(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION", EscapeText(e.Location))
+	}
+	if e.HasGeo {
+		w.line("GEO", fmt.Sprintf("%.6f;%.6f", e.Latitude, e.Longitude))
+	}
+	if e.URL != "" {
+		w.line("URL;VALUE=URI", e.URL)
+	}
+	if len(e.Categories) > 0 {
+		escaped := make([]string, len(e.Categories))
+		for i, category := range e.Categories {
+			escaped[i] = EscapeText(category)
+		}
+		w.line("CATEGORIES", strings.Join(escaped, ","))
+	}
+	if e.Status != "" {
+		w.line("STATUS", e.Status)
+	}
+	w.line("END", "VEVENT")
+}
+
+// FormatDateTime formats a timestamp as an RFC 5545 UTC DATE-TIME value
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// EscapeText escapes a TEXT property value as described in RFC 5545 section 3.3.11
+func EscapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// formatDuration renders a duration as an RFC 5545 DURATION value
+func formatDuration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("P%dD", int(d/(24*time.Hour)))
+	}
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("PT%dH", int(d/time.Hour))
+	}
+	return fmt.Sprintf("PT%dM", int(d/time.Minute))
+}
+
+type writer struct {
+	builder strings.Builder
+}
+
+// line writes a content line, folding it so no physical line exceeds 75 octets
+// and multi-byte UTF-8 sequences are never split.
+func (w *writer) line(name, value string) {
+	content := name + ":" + value
+
+	folded := false
+	for len(content) > 0 {
+		limit := maxLineOctets
+		if folded {
+			// Continuation lines start with a single space
+			limit--
+		}
+
+		if len(content) <= limit {
+			w.builder.WriteString(content)
+			break
+		}
+
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+
+		w.builder.WriteString(content[:cut])
+		w.builder.WriteString("\r\n ")
+		content = content[cut:]
+		folded = true
+	}
+
+	w.builder.WriteString("\r\n")
+}
+
+func (w *writer) String() string {
+	return w.builder.String()
+}
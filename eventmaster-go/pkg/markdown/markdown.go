@@ -0,0 +1,178 @@
+// Package markdown defines the rich-text format of descriptions: Markdown source, stored as
+// written, rendered to HTML that only contains an allowlist of formatting elements.
+//
+// Single newlines are line breaks, as in chat messages. Raw HTML in the source is not
+// rendered, links are limited to http, https and mailto and images are not supported,
+// so the HTML is safe to insert into a page as is.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	xhtml "golang.org/x/net/html"
+)
+
+var (
+	renderer = goldmark.New(
+		goldmark.WithExtensions(extension.Strikethrough, extension.Linkify),
+		goldmark.WithRendererOptions(html.WithHardWraps()),
+	)
+	policy = newPolicy()
+
+	// htmlTag matches the tags of elements descriptions use, with attributes only in
+	// name=value form, so autolinks such as <https://example.com> and comparisons such as
+	// a<b and c>d in Markdown are not mistaken for HTML
+	htmlTag    = regexp.MustCompile(`(?i)</?(?:a|b|big|blockquote|br|center|code|div|em|font|h[1-6]|hr|i|img|li|ol|p|pre|s|script|small|span|strike|strong|style|sub|sup|table|tbody|td|th|thead|tr|u|ul)(?:\s+[a-z][\w:-]*\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'<>=` + "`" + `]+))*\s*/?>`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// newPolicy allows the elements Markdown produces for text formatting and nothing else
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "hr", "strong", "em", "del", "code", "pre", "blockquote",
+		"ul", "ol", "li", "h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render converts Markdown to sanitized HTML. Empty source renders to an empty string.
+func Render(source string) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		// goldmark only fails when writing fails, which a buffer does not
+		return policy.Sanitize(xhtml.EscapeString(source))
+	}
+	return strings.TrimSpace(policy.Sanitize(buf.String()))
+}
+
+// ContainsHTML reports whether text contains HTML tags of formatting elements, as
+// descriptions written before they were defined as Markdown may
+func ContainsHTML(text string) bool {
+	return htmlTag.MatchString(text)
+}
+
+// FromHTML converts text mixing Markdown with HTML into plain Markdown. Line breaks,
+// paragraphs, emphasis, headings, lists and links become their Markdown equivalents; other
+// tags are dropped along with the content of scripts and styles. Text is kept as written,
+// so Markdown already present survives, including autolinks and any other < that does not
+// start a tag ContainsHTML recognizes.
+func FromHTML(text string) string {
+	var out strings.Builder
+	var links []string
+	skip := 0
+
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(escapeNonTags(text)))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		switch tokenType {
+		case xhtml.TextToken:
+			if skip == 0 {
+				out.WriteString(token.Data)
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			switch token.Data {
+			case "script", "style":
+				if tokenType == xhtml.StartTagToken {
+					skip++
+				}
+			case "br":
+				out.WriteString("\n")
+			case "p", "div":
+				out.WriteString("\n\n")
+			case "hr":
+				out.WriteString("\n\n---\n\n")
+			case "strong", "b":
+				out.WriteString("**")
+			case "em", "i":
+				out.WriteString("*")
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				out.WriteString("\n\n" + strings.Repeat("#", int(token.Data[1]-'0')) + " ")
+			case "li":
+				out.WriteString("\n- ")
+			case "a":
+				href := attr(token, "href")
+				if !safeURL(href) {
+					href = ""
+				}
+				links = append(links, href)
+				if href != "" {
+					out.WriteString("[")
+				}
+			}
+		case xhtml.EndTagToken:
+			switch token.Data {
+			case "script", "style":
+				if skip > 0 {
+					skip--
+				}
+			case "p", "div", "ul", "ol", "h1", "h2", "h3", "h4", "h5", "h6":
+				out.WriteString("\n\n")
+			case "strong", "b":
+				out.WriteString("**")
+			case "em", "i":
+				out.WriteString("*")
+			case "a":
+				if len(links) == 0 {
+					continue
+				}
+				href := links[len(links)-1]
+				links = links[:len(links)-1]
+				if href != "" {
+					out.WriteString("](" + href + ")")
+				}
+			}
+		}
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// escapeNonTags escapes every < in text that does not start a recognized tag, so the
+// tokenizer reads it back as text instead of a tag to drop
+func escapeNonTags(text string) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range htmlTag.FindAllStringIndex(text, -1) {
+		out.WriteString(strings.ReplaceAll(text[last:loc[0]], "<", "&lt;"))
+		out.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	out.WriteString(strings.ReplaceAll(text[last:], "<", "&lt;"))
+	return out.String()
+}
+
+func attr(token xhtml.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func safeURL(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
@@ -0,0 +1,65 @@
+// Package qr renders QR codes as PNG images or SVG documents.
+package qr
+
+import (
+	"fmt"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	// PNGContentType is the MIME type served for PNG codes
+	PNGContentType = "image/png"
+	// SVGContentType is the MIME type served for SVG codes
+	SVGContentType = "image/svg+xml"
+
+	// DefaultSize is the width and height of PNG codes in pixels
+	DefaultSize = 256
+)
+
+// PNG encodes content as a size by size pixel PNG with medium error correction
+func PNG(content string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// Modules returns the module grid of the code for content, including the quiet zone; true
+// marks a dark module. The grid is square.
+func Modules(content string) ([][]bool, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	return code.Bitmap(), nil
+}
+
+// SVG encodes content as a scalable SVG document with one unit per module, including the
+// quiet zone. Runs of dark modules on a row are merged into single rectangles.
+func SVG(content string) (string, error) {
+	bitmap, err := Modules(content)
+	if err != nil {
+		return "", err
+	}
+	size := len(bitmap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, size, size)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.String(), nil
+}
@@ -0,0 +1,199 @@
+// Package tabular writes rows of values as CSV, newline-delimited JSON or XLSX.
+// Every writer streams: rows are encoded as they arrive and nothing but the
+// current row is held in memory.
+package tabular
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format identifies an output encoding
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+// ErrUnknownFormat is returned for formats other than csv, ndjson and xlsx
+var ErrUnknownFormat = errors.New("unknown export format, expected csv, ndjson or xlsx")
+
+// Writer encodes a header followed by rows of values. Values may be strings,
+// numbers, booleans, time.Time, *time.Time or nil.
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	// Close finishes the document and flushes buffered output. It does not close
+	// the underlying io.Writer.
+	Close() error
+}
+
+// ParseFormat resolves a format name, defaulting to CSV when empty
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Extension returns the file extension of the format without the dot
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// NewWriter creates a writer for format. sheetName is only used by XLSX.
+func NewWriter(format Format, w io.Writer, sheetName string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{csv: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// flushEvery bounds how many rows text writers buffer before flushing
+const flushEvery = 500
+
+type csvWriter struct {
+	csv  *csv.Writer
+	rows int
+}
+
+func (w *csvWriter) WriteHeader(columns []string) error {
+	return w.csv.Write(columns)
+}
+
+func (w *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatText(value)
+	}
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+func (w *ndjsonWriter) WriteHeader(columns []string) error {
+	w.columns = columns
+	return nil
+}
+
+// WriteRow emits one JSON object per line with keys in header order
+func (w *ndjsonWriter) WriteRow(values []interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("row has %d values for %d columns", len(values), len(w.columns))
+	}
+
+	var line strings.Builder
+	line.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		line.Write(key)
+		line.WriteByte(':')
+
+		value, err := json.Marshal(jsonValue(values[i]))
+		if err != nil {
+			return err
+		}
+		line.Write(value)
+	}
+	line.WriteString("}\n")
+
+	_, err := io.WriteString(w.w, line.String())
+	return err
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// formatText renders a value the way CSV cells show it
+func formatText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatText(*v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return jsonValue(*v)
+	default:
+		return v
+	}
+}
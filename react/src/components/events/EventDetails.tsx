@@ -71,9 +71,9 @@ export const EventDetails = () => {
             <h2 className="text-5xl font-extrabold text-indigo-900 mb-6 border-b-2 border-indigo-500 pb-2">
               {event.title}
             </h2>
-            <p className="text-left text-lg text-gray-700 leading-relaxed mb-8">
-              {parse(event.description)}
-            </p>
+            <div className="text-left text-lg text-gray-700 leading-relaxed mb-8">
+              {parse(event.descriptionHtml ?? "")}
+            </div>
             <div className="flex items-center mb-6">
               <span className="font-semibold text-lg text-indigo-800 mr-2">
                 Date:
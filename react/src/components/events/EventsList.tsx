@@ -141,7 +141,9 @@ export const EventsList = () => {
             >
               <div className="p-6">
                 <h2 className="text-xl font-semibold mb-2">{i.title}</h2>
-                <p className="text-gray-700 mb-4"> {parse(i.description)}</p>
+                <div className="text-gray-700 mb-4">
+                  {parse(i.descriptionHtml ?? "")}
+                </div>
                 <div className="flex justify-between space-x-4">
                   <Link
                     href={`/events/register/${i.id}`}
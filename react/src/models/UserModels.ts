@@ -54,6 +54,8 @@ export type CreateEventDto = {
 
 export type Event = CreateEventDto & {
   id: string;
+  // The description as sanitized HTML, rendered by the server from the Markdown source
+  descriptionHtml?: string;
   organizer: string;
   createdAt: Date;
   updatedAt: Date;